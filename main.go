@@ -5,7 +5,14 @@ import (
 	"github.com/preston4tw/terraform-provider-snowflake/snowflake"
 )
 
+// Version and Build are set with -ldflags by the Makefile.
+var (
+	Version = "dev"
+	Build   = ""
+)
+
 func main() {
+	snowflake.Version = Version
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: snowflake.Provider})
 }
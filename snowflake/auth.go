@@ -0,0 +1,156 @@
+package snowflake
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/youmark/pkcs8"
+)
+
+// providerPrivateKey returns the private key configured through private_key or
+// private_key_path, or nil when key-pair authentication is not configured.
+func providerPrivateKey(d *schema.ResourceData) (*rsa.PrivateKey, error) {
+	var keyBytes []byte
+	if key, ok := d.GetOk("private_key"); ok {
+		keyBytes = []byte(key.(string))
+	} else if path, ok := d.GetOk("private_key_path"); ok {
+		b, err := ioutil.ReadFile(path.(string))
+		if err != nil {
+			return nil, fmt.Errorf("Could not read private key file: %v", err)
+		}
+		keyBytes = b
+	} else {
+		return nil, nil
+	}
+	return parsePrivateKey(keyBytes, d.Get("private_key_passphrase").(string))
+}
+
+/*
+parsePrivateKey parses a PEM encoded RSA private key. Snowflake key-pair
+authentication expects PKCS#8 keys, optionally encrypted, as generated by
+
+openssl genrsa 2048 | openssl pkcs8 -topk8 -inform PEM -out rsa_key.p8
+
+Unencrypted PKCS#1 ("RSA PRIVATE KEY") keys are accepted as well.
+*/
+func parsePrivateKey(keyBytes []byte, passphrase string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyBytes)
+	if block == nil {
+		return nil, fmt.Errorf("Could not decode private key, expected a PEM block")
+	}
+	switch block.Type {
+	case "ENCRYPTED PRIVATE KEY":
+		if passphrase == "" {
+			return nil, fmt.Errorf("Private key is encrypted but private_key_passphrase is not set")
+		}
+		key, err := pkcs8.ParsePKCS8PrivateKeyRSA(block.Bytes, []byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("Could not decrypt private key: %v", err)
+		}
+		return key, nil
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse private key: %v", err)
+		}
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("Private key is not an RSA key")
+		}
+		return rsaKey, nil
+	case "RSA PRIVATE KEY":
+		if x509.IsEncryptedPEMBlock(block) {
+			return nil, fmt.Errorf("Encrypted PKCS#1 private keys are not supported, convert the key to PKCS#8")
+		}
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse private key: %v", err)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("Unsupported private key type %q", block.Type)
+}
+
+// providerOAuthToken returns the OAuth access token to authenticate with. When
+// a refresh token is configured a fresh access token is requested from
+// oauth_endpoint, otherwise the configured token is used as is.
+func providerOAuthToken(d *schema.ResourceData) (string, error) {
+	if refreshToken, ok := d.GetOk("oauth_refresh_token"); ok {
+		endpoint := d.Get("oauth_endpoint").(string)
+		if endpoint == "" {
+			return "", fmt.Errorf("oauth_endpoint must be set when using oauth_refresh_token")
+		}
+		return refreshOAuthToken(
+			http.DefaultClient,
+			endpoint,
+			d.Get("oauth_client_id").(string),
+			d.Get("oauth_client_secret").(string),
+			refreshToken.(string),
+		)
+	}
+	token := d.Get("token").(string)
+	if token == "" {
+		return "", fmt.Errorf("One of token or oauth_refresh_token must be set when authenticator is oauth")
+	}
+	return token, nil
+}
+
+type oauthTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+/*
+refreshOAuthToken exchanges a refresh token for an access token using the
+refresh_token grant (https://tools.ietf.org/html/rfc6749#section-6). For
+Snowflake OAuth the endpoint is
+https://<account>.snowflakecomputing.com/oauth/token-request; the client
+credentials are sent with HTTP basic authentication.
+*/
+func refreshOAuthToken(client *http.Client, endpoint string, clientID string, clientSecret string, refreshToken string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	req, err := http.NewRequest("POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=UTF-8")
+	req.Header.Set("Accept", "application/json")
+	if clientID != "" {
+		req.SetBasicAuth(clientID, clientSecret)
+	}
+	if client.Timeout == 0 {
+		c := *client
+		c.Timeout = 30 * time.Second
+		client = &c
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Could not refresh OAuth token: %v", err)
+	}
+	defer resp.Body.Close()
+	var r oauthTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return "", fmt.Errorf("Could not decode OAuth token response (HTTP %d): %v", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || r.Error != "" {
+		return "", fmt.Errorf("Could not refresh OAuth token (HTTP %d): %s %s", resp.StatusCode, r.Error, r.ErrorDescription)
+	}
+	if r.AccessToken == "" {
+		return "", fmt.Errorf("OAuth token response did not contain an access_token")
+	}
+	return r.AccessToken, nil
+}
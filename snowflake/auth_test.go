@@ -0,0 +1,211 @@
+package snowflake
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/youmark/pkcs8"
+)
+
+func TestParsePrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8Key, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryptedKey, err := pkcs8.MarshalPrivateKey(key, []byte("secret"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8ECKey, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := func(blockType string, b []byte) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: b})
+	}
+
+	// An empty err means the key parses.
+	cases := []struct {
+		name       string
+		key        []byte
+		passphrase string
+		err        string
+	}{
+		{"pkcs8", encode("PRIVATE KEY", pkcs8Key), "", ""},
+		{"pkcs8 passphrase ignored", encode("PRIVATE KEY", pkcs8Key), "secret", ""},
+		{"encrypted pkcs8", encode("ENCRYPTED PRIVATE KEY", encryptedKey), "secret", ""},
+		{"encrypted pkcs8 wrong passphrase", encode("ENCRYPTED PRIVATE KEY", encryptedKey), "wrong", "Could not decrypt private key"},
+		{"encrypted pkcs8 no passphrase", encode("ENCRYPTED PRIVATE KEY", encryptedKey), "", "private_key_passphrase is not set"},
+		{"pkcs1", encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)), "", ""},
+		{"pkcs8 not rsa", encode("PRIVATE KEY", pkcs8ECKey), "", "not an RSA key"},
+		{"pkcs8 corrupt", encode("PRIVATE KEY", pkcs8Key[:len(pkcs8Key)/2]), "", "Could not parse private key"},
+		{"unsupported type", encode("EC PRIVATE KEY", pkcs8ECKey), "", "Unsupported private key type"},
+		{"garbage", []byte("not a key"), "", "expected a PEM block"},
+		{"empty", nil, "", "expected a PEM block"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := parsePrivateKey(c.key, c.passphrase)
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("got error %v, want one containing %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.N.Cmp(key.N) != 0 || got.D.Cmp(key.D) != 0 {
+				t.Fatal("parsed key differs from the generated one")
+			}
+		})
+	}
+}
+
+// testOAuthServer is a token endpoint that hands out access-token for the
+// refresh token refresh-token of client client-id.
+func testOAuthServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != "POST" || r.FormValue("grant_type") != "refresh_token" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error": "unsupported_grant_type"}`)
+			return
+		}
+		id, secret, _ := r.BasicAuth()
+		if id != "client-id" || secret != "client-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": "invalid_client"}`)
+			return
+		}
+		if r.FormValue("refresh_token") != "refresh-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error": "invalid_grant", "error_description": "refresh token expired"}`)
+			return
+		}
+		fmt.Fprint(w, `{"access_token": "access-token", "token_type": "Bearer", "expires_in": 600}`)
+	}))
+}
+
+func TestProviderOAuthToken(t *testing.T) {
+	server := testOAuthServer()
+	defer server.Close()
+
+	// An empty err means providerOAuthToken returns token.
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		token  string
+		err    string
+	}{
+		{
+			name:   "access token",
+			config: map[string]interface{}{"token": "configured-token"},
+			token:  "configured-token",
+		},
+		{
+			name:   "no token",
+			config: map[string]interface{}{},
+			err:    "One of token or oauth_refresh_token must be set",
+		},
+		{
+			name: "refresh",
+			config: map[string]interface{}{
+				"token":               "configured-token",
+				"oauth_refresh_token": "refresh-token",
+				"oauth_client_id":     "client-id",
+				"oauth_client_secret": "client-secret",
+				"oauth_endpoint":      server.URL,
+			},
+			token: "access-token",
+		},
+		{
+			name: "refresh expired",
+			config: map[string]interface{}{
+				"oauth_refresh_token": "expired-token",
+				"oauth_client_id":     "client-id",
+				"oauth_client_secret": "client-secret",
+				"oauth_endpoint":      server.URL,
+			},
+			err: "(HTTP 400): invalid_grant refresh token expired",
+		},
+		{
+			name: "refresh wrong client",
+			config: map[string]interface{}{
+				"oauth_refresh_token": "refresh-token",
+				"oauth_client_id":     "client-id",
+				"oauth_client_secret": "wrong",
+				"oauth_endpoint":      server.URL,
+			},
+			err: "(HTTP 401): invalid_client",
+		},
+		{
+			name:   "refresh without endpoint",
+			config: map[string]interface{}{"oauth_refresh_token": "refresh-token"},
+			err:    "oauth_endpoint must be set",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, c.config)
+			token, err := providerOAuthToken(d)
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("got error %v, want one containing %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token != c.token {
+				t.Fatalf("got token %q, want %q", token, c.token)
+			}
+		})
+	}
+}
+
+func TestRefreshOAuthTokenResponse(t *testing.T) {
+	// Responses the token endpoint should never send, but might.
+	cases := []struct {
+		name   string
+		status int
+		body   string
+		err    string
+	}{
+		{"no access token", http.StatusOK, `{"token_type": "Bearer"}`, "did not contain an access_token"},
+		{"error with 200", http.StatusOK, `{"access_token": "access-token", "error": "server_error"}`, "server_error"},
+		{"not json", http.StatusBadGateway, `<html>Bad Gateway</html>`, "Could not decode OAuth token response (HTTP 502)"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.status)
+				fmt.Fprint(w, c.body)
+			}))
+			defer server.Close()
+			_, err := refreshOAuthToken(server.Client(), server.URL, "", "", "refresh-token")
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Fatalf("got error %v, want one containing %q", err, c.err)
+			}
+		})
+	}
+}
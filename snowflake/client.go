@@ -0,0 +1,190 @@
+package snowflake
+
+import (
+	"database/sql"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
+)
+
+/*
+Client is what resources talk to Snowflake through. A Client is opened for a
+single resource operation with newClient and closed when the operation is
+done.
+
+The typed methods look up objects and grants, see the helpers in util.go for
+how they are implemented with SHOW, DESC and information_schema queries, and
+report objects that do not exist with a notFoundError. Changes are made with
+Exec, ExecAll, Grant and Revoke. Resources never see raw rows, which keeps the
+interface small enough to fake.
+
+*session is the implementation talking to Snowflake. Other implementations,
+such as the in-memory fakeClient of the tests, are plugged in through
+providerMeta.openClient.
+*/
+type Client interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	ExecAll(statements []string) error
+	Close() error
+
+	// ObjectExists checks that one and only one object of objectType named
+	// name exists in the container in, see inAccount, inDatabase and
+	// inSchema.
+	ObjectExists(objectType string, name string, in string) (bool, error)
+	ShowDatabase(name string) (showDatabaseRow, error)
+	ShowSchema(database string, name string) (showSchemaRow, error)
+	ShowTable(database string, schema string, name string) (showTableRow, error)
+	DescTable(database string, schema string, name string) ([]descTableRow, error)
+	ReadView(database string, schema string, name string) (infoSchemaView, error)
+	ShowPipe(database string, schema string, name string) (showPipeRow, error)
+	DescStage(database string, schema string, name string) (descStageResult, error)
+	ShowFileFormat(database string, schema string, name string) (showFileFormatRow, error)
+	DescFileFormat(database string, schema string, name string) (descFileFormatResult, error)
+	ShowSequence(database string, schema string, name string) (showSequenceRow, error)
+	DescUser(name string) (descUserResult, error)
+	ShowRole(name string) (showRoleRow, error)
+	ShowWarehouse(name string) (showWarehouseRow, error)
+	ShowWarehouses() ([]showWarehouseRow, error)
+	ShowResourceMonitor(name string) (showResourceMonitorRow, error)
+	// ShowParameter looks up the parameter key of the object in, e.g.
+	// WAREHOUSE W.
+	ShowParameter(key string, in string) (showParameterRow, error)
+	ShowTableGrant(grantee string, database string, schema string, table string) (showTableGrantResult, error)
+	ShowViewGrant(grantee string, database string, schema string, view string) (showViewGrantResult, error)
+	ShowWarehouseGrant(warehouse string, privilege string) (showWarehouseGrantResult, error)
+
+	// Grant grants privileges on an object, e.g. TABLE A.B.C, to a grantee,
+	// e.g. ROLE R, Revoke takes them away again.
+	Grant(privileges []string, on string, to string) error
+	Revoke(privileges []string, on string, from string) error
+}
+
+var _ Client = (*session)(nil)
+
+// newClient opens the Client for an operation of resource d, a session unless
+// the provider meta says otherwise.
+func newClient(meta interface{}, d *schema.ResourceData) (Client, error) {
+	m := meta.(*providerMeta)
+	if m.openClient != nil {
+		return m.openClient(m, d)
+	}
+	s, err := newSession(m, d)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// createThenRead ends the Create of resource d once db executed the statements
+// creating its object, err being their error. db is released before the object
+// is read back with read, the read pins a session of its own. In dry-run mode
+// nothing was created and there is nothing to read back.
+func createThenRead(d *schema.ResourceData, meta interface{}, db Client, id string, err error, read schema.ReadFunc) error {
+	db.Close()
+	if err != nil {
+		return err
+	}
+	d.SetId(id)
+	if meta.(*providerMeta).dryRun {
+		return nil
+	}
+	return read(d, meta)
+}
+
+func (s *session) ObjectExists(objectType string, name string, in string) (bool, error) {
+	return sqlObjExists(s, objectType, name, in)
+}
+
+func (s *session) ShowDatabase(name string) (showDatabaseRow, error) {
+	return showDatabase(s, name)
+}
+
+func (s *session) ShowSchema(database string, name string) (showSchemaRow, error) {
+	return showSchema(s, database, name)
+}
+
+func (s *session) ShowTable(database string, schema string, name string) (showTableRow, error) {
+	return showTable(s, database, schema, name)
+}
+
+func (s *session) DescTable(database string, schema string, name string) ([]descTableRow, error) {
+	return descTable(s, database, schema, name)
+}
+
+func (s *session) ReadView(database string, schema string, name string) (infoSchemaView, error) {
+	return readView(s, database, schema, name)
+}
+
+func (s *session) ShowPipe(database string, schema string, name string) (showPipeRow, error) {
+	return showPipe(s, database, schema, name)
+}
+
+func (s *session) DescStage(database string, schema string, name string) (descStageResult, error) {
+	return descStage(s, database, schema, name)
+}
+
+func (s *session) ShowFileFormat(database string, schema string, name string) (showFileFormatRow, error) {
+	return showFileFormat(s, database, schema, name)
+}
+
+func (s *session) DescFileFormat(database string, schema string, name string) (descFileFormatResult, error) {
+	return descFileFormat(s, database, schema, name)
+}
+
+func (s *session) ShowSequence(database string, schema string, name string) (showSequenceRow, error) {
+	return showSequence(s, database, schema, name)
+}
+
+func (s *session) DescUser(name string) (descUserResult, error) {
+	return descUser(s, name)
+}
+
+func (s *session) ShowRole(name string) (showRoleRow, error) {
+	return showRole(s, name)
+}
+
+func (s *session) ShowWarehouse(name string) (showWarehouseRow, error) {
+	return showWarehouse(s, name)
+}
+
+func (s *session) ShowWarehouses() ([]showWarehouseRow, error) {
+	return showWarehouses(s)
+}
+
+func (s *session) ShowResourceMonitor(name string) (showResourceMonitorRow, error) {
+	return showResourceMonitor(s, name)
+}
+
+func (s *session) ShowParameter(key string, in string) (showParameterRow, error) {
+	return showParameter(s, key, in)
+}
+
+func (s *session) ShowTableGrant(grantee string, database string, schema string, table string) (showTableGrantResult, error) {
+	return showTableGrant(s, grantee, database, schema, table)
+}
+
+func (s *session) ShowViewGrant(grantee string, database string, schema string, view string) (showViewGrantResult, error) {
+	return showViewGrant(s, grantee, database, schema, view)
+}
+
+func (s *session) ShowWarehouseGrant(warehouse string, privilege string) (showWarehouseGrantResult, error) {
+	return showWarehouseGrant(s, warehouse, privilege)
+}
+
+func (s *session) Grant(privileges []string, on string, to string) error {
+	statement, err := snowsql.Grant(privileges, on, to)
+	if err != nil {
+		return err
+	}
+	_, err = s.Exec(statement)
+	return err
+}
+
+func (s *session) Revoke(privileges []string, on string, from string) error {
+	statement, err := snowsql.Revoke(privileges, on, from)
+	if err != nil {
+		return err
+	}
+	_, err = s.Exec(statement)
+	return err
+}
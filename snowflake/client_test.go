@@ -0,0 +1,297 @@
+package snowflake
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
+)
+
+/*
+fakeClient is an in-memory Client for unit tests of resources, which check the
+statements a resource executes rather than their effect. The statements are
+recorded and not interpreted: lookups are answered from what the test put in
+objects and rows, so a test sets up what Snowflake would return after the
+statements, and anything it did not set up does not exist.
+*/
+type fakeClient struct {
+	// statements are the statements executed, in order.
+	statements []string
+	// objects are the objects ObjectExists finds, by object type, name and
+	// container, e.g. "databases D IN ACCOUNT".
+	objects map[string]bool
+	// rows are the results of the typed lookups, by method and arguments,
+	// e.g. "ShowDatabase D".
+	rows map[string]interface{}
+	// execErr, if set, fails executing the statements it returns an error for.
+	execErr func(statement string) error
+	// open counts the clients opened and not closed yet.
+	open int
+}
+
+var _ Client = (*fakeClient)(nil)
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{objects: map[string]bool{}, rows: map[string]interface{}{}}
+}
+
+// testFakeMeta returns the provider meta of resources whose operations talk
+// to c.
+func testFakeMeta(c *fakeClient) *providerMeta {
+	return &providerMeta{
+		openClient: func(m *providerMeta, d *schema.ResourceData) (Client, error) {
+			c.open++
+			return c, nil
+		},
+	}
+}
+
+// takeStatements returns the statements executed since it was last called.
+func (c *fakeClient) takeStatements() []string {
+	statements := c.statements
+	c.statements = nil
+	return statements
+}
+
+// lookup answers a typed lookup with the row set up for method and args, or a
+// notFoundError.
+func (c *fakeClient) lookup(dest interface{}, method string, args ...string) error {
+	key := strings.Join(append([]string{method}, args...), " ")
+	row, ok := c.rows[key]
+	if !ok {
+		return newNotFoundError(method, args...)
+	}
+	reflect.ValueOf(dest).Elem().Set(reflect.ValueOf(row))
+	return nil
+}
+
+func (c *fakeClient) Exec(query string, args ...interface{}) (sql.Result, error) {
+	if len(args) > 0 {
+		return nil, fmt.Errorf("fakeClient does not support arguments: %s", query)
+	}
+	c.statements = append(c.statements, query)
+	if c.execErr != nil {
+		if err := c.execErr(query); err != nil {
+			return nil, err
+		}
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeClient) ExecAll(statements []string) error {
+	for _, statement := range statements {
+		if _, err := c.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *fakeClient) Close() error {
+	c.open--
+	return nil
+}
+
+func (c *fakeClient) ObjectExists(objectType string, name string, in string) (bool, error) {
+	return c.objects[fmt.Sprintf("%s %s IN %s", objectType, name, in)], nil
+}
+
+func (c *fakeClient) ShowDatabase(name string) (r showDatabaseRow, err error) {
+	err = c.lookup(&r, "ShowDatabase", name)
+	return
+}
+
+func (c *fakeClient) ShowSchema(database string, name string) (r showSchemaRow, err error) {
+	err = c.lookup(&r, "ShowSchema", database, name)
+	return
+}
+
+func (c *fakeClient) ShowTable(database string, schema string, name string) (r showTableRow, err error) {
+	err = c.lookup(&r, "ShowTable", database, schema, name)
+	return
+}
+
+func (c *fakeClient) DescTable(database string, schema string, name string) (r []descTableRow, err error) {
+	err = c.lookup(&r, "DescTable", database, schema, name)
+	return
+}
+
+func (c *fakeClient) ReadView(database string, schema string, name string) (r infoSchemaView, err error) {
+	err = c.lookup(&r, "ReadView", database, schema, name)
+	return
+}
+
+func (c *fakeClient) ShowPipe(database string, schema string, name string) (r showPipeRow, err error) {
+	err = c.lookup(&r, "ShowPipe", database, schema, name)
+	return
+}
+
+func (c *fakeClient) DescStage(database string, schema string, name string) (r descStageResult, err error) {
+	err = c.lookup(&r, "DescStage", database, schema, name)
+	return
+}
+
+func (c *fakeClient) ShowFileFormat(database string, schema string, name string) (r showFileFormatRow, err error) {
+	err = c.lookup(&r, "ShowFileFormat", database, schema, name)
+	return
+}
+
+func (c *fakeClient) DescFileFormat(database string, schema string, name string) (r descFileFormatResult, err error) {
+	err = c.lookup(&r, "DescFileFormat", database, schema, name)
+	return
+}
+
+func (c *fakeClient) ShowSequence(database string, schema string, name string) (r showSequenceRow, err error) {
+	err = c.lookup(&r, "ShowSequence", database, schema, name)
+	return
+}
+
+func (c *fakeClient) DescUser(name string) (r descUserResult, err error) {
+	err = c.lookup(&r, "DescUser", name)
+	return
+}
+
+func (c *fakeClient) ShowRole(name string) (r showRoleRow, err error) {
+	err = c.lookup(&r, "ShowRole", name)
+	return
+}
+
+func (c *fakeClient) ShowWarehouse(name string) (r showWarehouseRow, err error) {
+	err = c.lookup(&r, "ShowWarehouse", name)
+	return
+}
+
+func (c *fakeClient) ShowWarehouses() (r []showWarehouseRow, err error) {
+	if err = c.lookup(&r, "ShowWarehouses"); isNotFound(err) {
+		return nil, nil
+	}
+	return
+}
+
+func (c *fakeClient) ShowResourceMonitor(name string) (r showResourceMonitorRow, err error) {
+	err = c.lookup(&r, "ShowResourceMonitor", name)
+	return
+}
+
+func (c *fakeClient) ShowParameter(key string, in string) (r showParameterRow, err error) {
+	err = c.lookup(&r, "ShowParameter", key, in)
+	return
+}
+
+func (c *fakeClient) ShowTableGrant(grantee string, database string, schema string, table string) (r showTableGrantResult, err error) {
+	err = c.lookup(&r, "ShowTableGrant", grantee, database, schema, table)
+	return
+}
+
+func (c *fakeClient) ShowViewGrant(grantee string, database string, schema string, view string) (r showViewGrantResult, err error) {
+	err = c.lookup(&r, "ShowViewGrant", grantee, database, schema, view)
+	return
+}
+
+func (c *fakeClient) ShowWarehouseGrant(warehouse string, privilege string) (r showWarehouseGrantResult, err error) {
+	err = c.lookup(&r, "ShowWarehouseGrant", warehouse, privilege)
+	return
+}
+
+func (c *fakeClient) Grant(privileges []string, on string, to string) error {
+	statement, err := snowsql.Grant(privileges, on, to)
+	if err != nil {
+		return err
+	}
+	_, err = c.Exec(statement)
+	return err
+}
+
+func (c *fakeClient) Revoke(privileges []string, on string, from string) error {
+	statement, err := snowsql.Revoke(privileges, on, from)
+	if err != nil {
+		return err
+	}
+	_, err = c.Exec(statement)
+	return err
+}
+
+// testFakeApply plans the configuration raw of resource r against state, nil
+// for a new resource, and applies the plan the way Terraform would. A nil raw
+// destroys the resource.
+func testFakeApply(t *testing.T, r *schema.Resource, meta interface{}, state *terraform.InstanceState, raw map[string]interface{}) (*terraform.InstanceState, error) {
+	t.Helper()
+	if state == nil {
+		state = &terraform.InstanceState{}
+	}
+	var diff *terraform.InstanceDiff
+	if raw == nil {
+		diff = &terraform.InstanceDiff{Destroy: true}
+	} else {
+		c, err := config.NewRawConfig(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff, err = r.Diff(state, terraform.NewResourceConfig(c), meta); err != nil {
+			return nil, err
+		}
+		if diff == nil {
+			return state, nil
+		}
+	}
+	return r.Apply(state, diff, meta)
+}
+
+// testFakeState returns the state of resource r with id after applying the
+// configuration raw.
+func testFakeState(t *testing.T, r *schema.Resource, id string, raw map[string]interface{}) *terraform.InstanceState {
+	t.Helper()
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	d.SetId(id)
+	return d.State()
+}
+
+// testFakeImport imports the object with id as resource r and refreshes it,
+// as terraform import does.
+func testFakeImport(t *testing.T, r *schema.Resource, meta interface{}, id string) (*terraform.InstanceState, error) {
+	t.Helper()
+	imported, err := r.Importer.State(r.Data(&terraform.InstanceState{ID: id}), meta)
+	if err != nil {
+		return nil, err
+	}
+	if len(imported) != 1 {
+		t.Fatalf("importing %s returned %d resources", id, len(imported))
+	}
+	return r.Refresh(imported[0].State(), meta)
+}
+
+// testCheckStatements fails the test unless c executed want since the last
+// check.
+func testCheckStatements(t *testing.T, c *fakeClient, want ...string) {
+	t.Helper()
+	got := c.takeStatements()
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("executed\n\t%s\nwant\n\t%s", strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+	}
+	if c.open != 0 {
+		t.Fatalf("%d clients were not closed", c.open)
+	}
+}
+
+// testCheckAttributes fails the test unless state has the attributes want.
+func testCheckAttributes(t *testing.T, state *terraform.InstanceState, want map[string]string) {
+	t.Helper()
+	if state == nil {
+		t.Fatal("the resource is not in the state")
+	}
+	for k, v := range want {
+		if got := state.Attributes[k]; got != v {
+			t.Errorf("%s is %q, want %q", k, got, v)
+		}
+	}
+}
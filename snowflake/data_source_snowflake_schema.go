@@ -1,8 +1,6 @@
 package snowflake
 
 import (
-	"database/sql"
-	"fmt"
 	"strconv"
 	"strings"
 
@@ -14,19 +12,16 @@ func dataSourceSnowflakeSchema() *schema.Resource {
 		Read: dataSourceSnowflakeSchemaRead,
 
 		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: identifierStateFunc,
 			},
 			"database": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: identifierStateFunc,
 			},
 			"owner": {
 				Type:     schema.TypeString,
@@ -52,25 +47,29 @@ func dataSourceSnowflakeSchema() *schema.Resource {
 }
 
 func dataSourceSnowflakeSchemaRead(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	database := d.Get("database").(string)
 	name := d.Get("name").(string)
-	schemaInfo, err := showSchema(db, database, name)
+	schemaInfo, err := db.ShowSchema(database, name)
 	if err != nil {
 		return err
 	}
-	d.SetId(strings.ToUpper(fmt.Sprintf("%s.%s", database, name)))
-	d.Set("name", schemaInfo.name)
-	d.Set("database", schemaInfo.databaseName)
-	d.Set("owner", schemaInfo.owner)
-	d.Set("comment", schemaInfo.comment)
-	if schemaInfo.options == "TRANSIENT" {
+	d.SetId(qualifiedName(database, name))
+	d.Set("name", storedIdentifier(schemaInfo.Name))
+	d.Set("database", storedIdentifier(schemaInfo.DatabaseName))
+	d.Set("owner", schemaInfo.Owner)
+	d.Set("comment", schemaInfo.Comment)
+	if schemaInfo.Options == "TRANSIENT" {
 		d.Set("transient", true)
 	} else {
 		d.Set("transient", false)
 	}
-	if schemaInfo.retentionTime != "" {
-		retentionTime, err := strconv.Atoi(schemaInfo.retentionTime)
+	if schemaInfo.RetentionTime != "" {
+		retentionTime, err := strconv.Atoi(schemaInfo.RetentionTime)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccSchemaDataSource(t *testing.T) {
+	database := testAccName()
+	name := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_schema", testAccShowSchema),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSchemaDataSourceConfig(database, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.snowflake_schema.test", "id", database+"."+name),
+					resource.TestCheckResourceAttr("data.snowflake_schema.test", "database", database),
+					resource.TestCheckResourceAttr("data.snowflake_schema.test", "name", name),
+					resource.TestCheckResourceAttr("data.snowflake_schema.test", "comment", "read"),
+					resource.TestCheckResourceAttr("data.snowflake_schema.test", "retention_time", "0"),
+					resource.TestCheckResourceAttr("data.snowflake_schema.test", "transient", "true"),
+					resource.TestCheckResourceAttrPair("data.snowflake_schema.test", "owner", "snowflake_schema.test", "owner"),
+				),
+			},
+			{
+				Config:      testAccSchemaDataSourceMissingConfig(database),
+				ExpectError: regexp.MustCompile("does not exist"),
+			},
+		},
+	})
+}
+
+func testAccSchemaDataSourceConfig(database string, name string) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_schema" "test" {
+  database       = "${snowflake_database.test.name}"
+  name           = "%s"
+  comment        = "read"
+  retention_time = 0
+  transient      = true
+}
+
+data "snowflake_schema" "test" {
+  database = "${snowflake_schema.test.database}"
+  name     = "${snowflake_schema.test.name}"
+}
+`, database, name)
+}
+
+func testAccSchemaDataSourceMissingConfig(database string) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+data "snowflake_schema" "test" {
+  database = "${snowflake_database.test.name}"
+  name     = "MISSING"
+}
+`, database)
+}
@@ -0,0 +1,354 @@
+package fakesnowflake
+
+import (
+	"database/sql/driver"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+kind is a type of object. depth is the number of parts of the qualified name
+of its objects: 1 for objects of the account such as databases and roles, 2
+for schemas and 3 for objects in a schema.
+
+The SHOW output of a kind has columns, the values of an object's row are
+returned by row. Columns the row does not have are NULL.
+*/
+type kind struct {
+	name    string
+	plural  string
+	depth   int
+	columns []string
+	row     func(c *catalog, o *object) map[string]driver.Value
+}
+
+// errorName is how errors about objects of the kind refer to them.
+func (k *kind) errorName() string {
+	switch k.name {
+	case "DATABASE":
+		return "Database"
+	case "SCHEMA":
+		return "Schema"
+	}
+	return "Object"
+}
+
+// kinds lists all kinds, see show.go for their definitions. Kinds with a
+// two word name come first so that parsing tries them first.
+var kinds []*kind
+
+// object is an object in the catalog. properties hold the values set with
+// CREATE and ALTER by key, string literals unquoted and other values as they
+// were written.
+type object struct {
+	kind       *kind
+	path       []string
+	created    time.Time
+	owner      string
+	modifiers  map[string]bool
+	properties map[string]string
+	columns    []column
+	// text is the CREATE statement, query the AS clause of views and pipes.
+	text  string
+	query string
+	// suspended is the state of a warehouse.
+	suspended bool
+	// triggers are the triggers of a resource monitor.
+	triggers []trigger
+}
+
+// trigger is a trigger of a resource monitor, ON threshold PERCENT DO action.
+type trigger struct {
+	threshold int
+	action    string
+}
+
+// column is a column of a table.
+type column struct {
+	name     string
+	typ      string
+	def      string
+	nullable bool
+}
+
+func (o *object) name() string {
+	return o.path[len(o.path)-1]
+}
+
+func (o *object) property(key string) string {
+	return o.properties[key]
+}
+
+// grant is a privilege on an object granted to a role or share.
+type grant struct {
+	privilege   string
+	on          *kind
+	path        []string
+	granteeType string
+	grantee     string
+	grantOption bool
+	grantedBy   string
+	created     time.Time
+}
+
+// catalog holds the objects and grants of an account, and the properties set
+// with ALTER ACCOUNT.
+type catalog struct {
+	mu      sync.Mutex
+	objects map[string]*object
+	grants  []*grant
+	account map[string]string
+}
+
+// systemRoles exist in every account.
+var systemRoles = []string{"ACCOUNTADMIN", "SECURITYADMIN", "USERADMIN", "SYSADMIN", "PUBLIC"}
+
+func newCatalog() *catalog {
+	c := &catalog{objects: map[string]*object{}, account: map[string]string{}}
+	for _, role := range systemRoles {
+		c.objects[objectKey(kindRole, []string{role})] = &object{
+			kind:       kindRole,
+			path:       []string{role},
+			created:    time.Now(),
+			properties: map[string]string{},
+			modifiers:  map[string]bool{},
+		}
+	}
+	return c
+}
+
+func objectKey(k *kind, path []string) string {
+	return k.name + "\x00" + strings.Join(path, "\x00")
+}
+
+// displayPath renders a qualified name the way Snowflake errors do.
+func displayPath(path []string) string {
+	return strings.Join(path, ".")
+}
+
+func samePath(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func hasPrefix(path []string, prefix []string) bool {
+	if len(path) <= len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *catalog) lookup(k *kind, path []string) *object {
+	return c.objects[objectKey(k, path)]
+}
+
+// checkContainer fails unless the database and schema an object at path
+// belongs to exist.
+func (c *catalog) checkContainer(path []string) error {
+	if len(path) > 1 && c.lookup(kindDatabase, path[:1]) == nil {
+		return doesNotExist(kindDatabase, path[:1])
+	}
+	if len(path) > 2 && c.lookup(kindSchema, path[:2]) == nil {
+		return doesNotExist(kindSchema, path[:2])
+	}
+	return nil
+}
+
+// get returns the object of kind k at path.
+func (c *catalog) get(k *kind, path []string) (*object, error) {
+	if err := c.checkContainer(path); err != nil {
+		return nil, err
+	}
+	o := c.lookup(k, path)
+	if o == nil {
+		return nil, doesNotExist(k, path)
+	}
+	return o, nil
+}
+
+// list returns the objects of kind k in the container at prefix, all of them
+// for an empty prefix, ordered by name.
+func (c *catalog) list(k *kind, prefix []string) []*object {
+	var objects []*object
+	for _, o := range c.objects {
+		if o.kind == k && (len(prefix) == 0 || hasPrefix(o.path, prefix)) {
+			objects = append(objects, o)
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return displayPath(objects[i].path) < displayPath(objects[j].path)
+	})
+	return objects
+}
+
+// add adds o, which is owned by role. An existing object is replaced if
+// replace is set.
+func (c *catalog) add(o *object, role string, replace bool) error {
+	if err := c.checkContainer(o.path); err != nil {
+		return err
+	}
+	if existing := c.lookup(o.kind, o.path); existing != nil {
+		if !replace {
+			return alreadyExists(o.kind, o.path)
+		}
+		c.remove(existing)
+	}
+	o.created = time.Now()
+	o.owner = role
+	c.objects[objectKey(o.kind, o.path)] = o
+	c.grants = append(c.grants, &grant{
+		privilege:   "OWNERSHIP",
+		on:          o.kind,
+		path:        o.path,
+		granteeType: "ROLE",
+		grantee:     role,
+		grantOption: true,
+		grantedBy:   role,
+		created:     o.created,
+	})
+	if o.kind == kindDatabase {
+		// Every database comes with a PUBLIC schema.
+		public := &object{
+			kind:       kindSchema,
+			path:       []string{o.name(), "PUBLIC"},
+			properties: map[string]string{},
+			modifiers:  map[string]bool{},
+		}
+		return c.add(public, role, false)
+	}
+	return nil
+}
+
+// contains reports whether o is the database or schema the object of kind k
+// at path is in.
+func (o *object) contains(k *kind, path []string) bool {
+	return (o.kind == kindDatabase || o.kind == kindSchema) && k.depth > o.kind.depth && hasPrefix(path, o.path)
+}
+
+// remove drops o along with the objects it contains and all grants on them.
+func (c *catalog) remove(o *object) {
+	dropped := func(k *kind, path []string) bool {
+		return (k == o.kind && samePath(path, o.path)) || o.contains(k, path)
+	}
+	for key, child := range c.objects {
+		if dropped(child.kind, child.path) {
+			delete(c.objects, key)
+		}
+	}
+	if o.kind == kindResourceMonitor {
+		// Warehouses and the account are no longer monitored.
+		for _, w := range c.list(kindWarehouse, nil) {
+			if w.property("RESOURCE_MONITOR") == o.name() {
+				delete(w.properties, "RESOURCE_MONITOR")
+			}
+		}
+		if c.account["RESOURCE_MONITOR"] == o.name() {
+			delete(c.account, "RESOURCE_MONITOR")
+		}
+	}
+	grants := c.grants[:0]
+	for _, g := range c.grants {
+		if dropped(g.on, g.path) {
+			continue
+		}
+		if o.kind == kindRole && g.granteeType == "ROLE" && g.grantee == o.name() {
+			continue
+		}
+		grants = append(grants, g)
+	}
+	c.grants = grants
+}
+
+// rename moves o and the objects it contains to newPath.
+func (c *catalog) rename(o *object, newPath []string) error {
+	if err := c.checkContainer(newPath); err != nil {
+		return err
+	}
+	if c.lookup(o.kind, newPath) != nil {
+		return alreadyExists(o.kind, newPath)
+	}
+	// o moves along with the others, old remembers where it was.
+	old := *o
+	oldPath := old.path
+	move := func(k *kind, path []string) []string {
+		if k == old.kind && samePath(path, oldPath) {
+			return newPath
+		}
+		if old.contains(k, path) {
+			return append(append([]string{}, newPath...), path[len(oldPath):]...)
+		}
+		return nil
+	}
+	objects := map[string]*object{}
+	for _, child := range c.objects {
+		if p := move(child.kind, child.path); p != nil {
+			child.path = p
+		}
+		objects[objectKey(child.kind, child.path)] = child
+	}
+	c.objects = objects
+	for _, g := range c.grants {
+		if p := move(g.on, g.path); p != nil {
+			g.path = p
+		}
+		if old.kind == kindRole && g.granteeType == "ROLE" && g.grantee == oldPath[0] {
+			g.grantee = newPath[0]
+		}
+	}
+	return nil
+}
+
+// grant grants privilege on the object at path, granting it again only adds
+// the grant option if it is granted now.
+func (c *catalog) grant(g *grant) {
+	for _, existing := range c.grants {
+		if existing.sameAs(g) {
+			existing.grantOption = existing.grantOption || g.grantOption
+			return
+		}
+	}
+	g.created = time.Now()
+	c.grants = append(c.grants, g)
+}
+
+// revoke revokes privilege, revoking a privilege that was not granted does
+// nothing.
+func (c *catalog) revoke(g *grant) {
+	grants := c.grants[:0]
+	for _, existing := range c.grants {
+		if !existing.sameAs(g) {
+			grants = append(grants, existing)
+		}
+	}
+	c.grants = grants
+}
+
+func (g *grant) sameAs(o *grant) bool {
+	return g.privilege == o.privilege && g.on == o.on && samePath(g.path, o.path) &&
+		g.granteeType == o.granteeType && g.grantee == o.grantee
+}
+
+// grantsOn returns the grants on the object of kind k at path.
+func (c *catalog) grantsOn(k *kind, path []string) []*grant {
+	var grants []*grant
+	for _, g := range c.grants {
+		if g.on == k && samePath(g.path, path) {
+			grants = append(grants, g)
+		}
+	}
+	return grants
+}
@@ -0,0 +1,228 @@
+/*
+Package fakesnowflake is an in-process stand-in for Snowflake, registered as a
+database/sql driver named DriverName. It keeps an in-memory catalog of the
+objects and grants the provider manages and understands the subset of SQL the
+provider issues: CREATE, ALTER, DROP, SHOW, DESC, GRANT, REVOKE, USE and the
+information_schema queries, so the provider can be exercised without a
+Snowflake account.
+
+It is not an SQL engine. Statements are interpreted as far as the catalog is
+concerned, e.g. the query of a view and the COPY statement of a pipe are kept
+verbatim but never checked, and anything else fails with an unsupported
+feature error. Errors carry the codes Snowflake uses, so the provider handles
+them the same way, e.g. a missing object is a *gosnowflake.SnowflakeError
+with Number 2003.
+
+All connections of a driver share one catalog, whatever the DSN. Privileges
+are not enforced, every role sees every object.
+*/
+package fakesnowflake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/snowflakedb/gosnowflake"
+)
+
+// DriverName is the name the stand-in is registered as with database/sql.
+const DriverName = "snowflake-fake"
+
+func init() {
+	sql.Register(DriverName, NewDriver())
+}
+
+// Driver is a database/sql driver whose connections share a catalog.
+type Driver struct {
+	catalog *catalog
+}
+
+// NewDriver returns a driver with an empty catalog, i.e. an account that only
+// has the system roles.
+func NewDriver() *Driver {
+	return &Driver{catalog: newCatalog()}
+}
+
+/*
+Open opens a connection. The DSN is only used for the role and warehouse of
+the session, taken from the role and warehouse parameters as in gosnowflake
+DSNs, e.g. user@account/db?role=SYSADMIN. Sessions start out as SYSADMIN
+without a warehouse.
+*/
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	c := &conn{catalog: d.catalog, role: "SYSADMIN"}
+	if i := strings.Index(dsn, "?"); i >= 0 {
+		params, err := url.ParseQuery(dsn[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		if role := params.Get("role"); role != "" {
+			c.role = strings.ToUpper(role)
+		}
+		if warehouse := params.Get("warehouse"); warehouse != "" {
+			c.warehouse = strings.ToUpper(warehouse)
+		}
+	}
+	return c, nil
+}
+
+// conn is a session, it holds the current role and warehouse.
+type conn struct {
+	catalog   *catalog
+	role      string
+	warehouse string
+}
+
+var (
+	_ driver.ExecerContext  = (*conn)(nil)
+	_ driver.QueryerContext = (*conn)(nil)
+)
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{c: c, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, unsupported("transactions")
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if len(args) > 0 {
+		return nil, unsupported("bind variables")
+	}
+	if _, err := c.catalog.execute(c, query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if len(args) > 0 {
+		return nil, unsupported("bind variables")
+	}
+	r, err := c.catalog.execute(c, query)
+	if err != nil {
+		return nil, err
+	}
+	return &rows{result: r}, nil
+}
+
+type stmt struct {
+	c     *conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	if len(args) > 0 {
+		return nil, unsupported("bind variables")
+	}
+	return s.c.ExecContext(context.Background(), s.query, nil)
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	if len(args) > 0 {
+		return nil, unsupported("bind variables")
+	}
+	return s.c.QueryContext(context.Background(), s.query, nil)
+}
+
+// result is the output of a statement. Like gosnowflake, values are strings,
+// time.Time for timestamps and nil for NULL.
+type result struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+// statusResult is the output of statements that do not return rows.
+func statusResult(status string) *result {
+	return &result{columns: []string{"status"}, rows: [][]driver.Value{{status}}}
+}
+
+type rows struct {
+	result *result
+	i      int
+}
+
+func (r *rows) Columns() []string {
+	return r.result.columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.i >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.i])
+	r.i++
+	return nil
+}
+
+// Snowflake error codes.
+const (
+	errCodeUnsupported   = 2
+	errCodeSyntax        = 1003
+	errCodeAlreadyExists = 2002
+	errCodeDoesNotExist  = 2003
+)
+
+func syntaxError(statement string, position int, message string) error {
+	line := strings.Count(statement[:position], "\n") + 1
+	column := position - strings.LastIndex(statement[:position], "\n") - 1
+	return &gosnowflake.SnowflakeError{
+		Number:   errCodeSyntax,
+		SQLState: "42000",
+		Message:  fmt.Sprintf("SQL compilation error:\nsyntax error line %d at position %d %s.", line, column, message),
+	}
+}
+
+func unsupported(feature string) error {
+	return &gosnowflake.SnowflakeError{
+		Number:   errCodeUnsupported,
+		SQLState: "0A000",
+		Message:  fmt.Sprintf("Unsupported feature '%s'.", feature),
+	}
+}
+
+func doesNotExist(k *kind, path []string) error {
+	return &gosnowflake.SnowflakeError{
+		Number:   errCodeDoesNotExist,
+		SQLState: "02000",
+		Message:  fmt.Sprintf("SQL compilation error:\n%s '%s' does not exist or not authorized.", k.errorName(), displayPath(path)),
+	}
+}
+
+func alreadyExists(k *kind, path []string) error {
+	return &gosnowflake.SnowflakeError{
+		Number:   errCodeAlreadyExists,
+		SQLState: "42710",
+		Message:  fmt.Sprintf("SQL compilation error:\nObject '%s' already exists.", displayPath(path)),
+	}
+}
+
+func invalidValue(message string) error {
+	return &gosnowflake.SnowflakeError{
+		Number:   errCodeSyntax,
+		SQLState: "22023",
+		Message:  "SQL compilation error:\n" + message,
+	}
+}
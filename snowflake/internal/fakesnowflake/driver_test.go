@@ -0,0 +1,215 @@
+package fakesnowflake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/snowflakedb/gosnowflake"
+)
+
+// testConnector connects to a driver of its own, so every test starts out
+// with an empty account.
+type testConnector struct {
+	d *Driver
+}
+
+func (c testConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.d.Open("tf@fake/?role=sysadmin")
+}
+
+func (c testConnector) Driver() driver.Driver {
+	return c.d
+}
+
+func testDB(t *testing.T, statements ...string) *sql.DB {
+	t.Helper()
+	db := sql.OpenDB(testConnector{NewDriver()})
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+	return db
+}
+
+// testQuery runs query and returns its columns and rows, with timestamps
+// replaced by "<time>".
+func testQuery(t *testing.T, db *sql.DB, query string) ([]string, [][]interface{}) {
+	t.Helper()
+	rows, err := db.Query(query)
+	if err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values [][]interface{}
+	for rows.Next() {
+		row := make([]interface{}, len(columns))
+		targets := make([]interface{}, len(columns))
+		for i := range row {
+			targets[i] = &row[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			t.Fatal(err)
+		}
+		for i, v := range row {
+			if _, ok := v.(time.Time); ok {
+				row[i] = "<time>"
+			}
+		}
+		values = append(values, row)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return columns, values
+}
+
+func TestDriverErrors(t *testing.T) {
+	db := testDB(t, "CREATE DATABASE D", "CREATE SCHEMA D.S")
+	defer db.Close()
+	cases := []struct {
+		statement string
+		number    int
+	}{
+		{"CREATE DATABASE D", errCodeAlreadyExists},
+		{"CREATE DATABASE IF NOT EXISTS D", 0},
+		{"CREATE SCHEMA D.S", errCodeAlreadyExists},
+		{"CREATE SCHEMA E.S", errCodeDoesNotExist},
+		{"DROP TABLE D.S.T", errCodeDoesNotExist},
+		{"DROP TABLE IF EXISTS D.S.T", 0},
+		{`SHOW TABLES IN SCHEMA D."s"`, errCodeDoesNotExist},
+		{"CREATE SCHEMA S", errCodeUnsupported},
+		{"BEGIN", errCodeSyntax},
+		{"CREATE DATABASE", errCodeSyntax},
+		{"CREATE DATABASE D2 COMMENT", errCodeSyntax},
+		{"SHOW DATABASES LIKE", errCodeSyntax},
+		{"SHOW DATABASES IN ACCOUNT EXTRA", errCodeSyntax},
+		{"SHOW TABLES IN D.S.T", errCodeSyntax},
+		{"CREATE DATABASE 'D'", errCodeSyntax},
+		{"CREATE OR REPLACE DATABASE D", 0},
+		{"DROP SCHEMA D.S", errCodeDoesNotExist},
+	}
+	for _, c := range cases {
+		_, err := db.Exec(c.statement)
+		if c.number == 0 {
+			if err != nil {
+				t.Errorf("%s: %v", c.statement, err)
+			}
+			continue
+		}
+		if e, ok := err.(*gosnowflake.SnowflakeError); !ok || e.Number != c.number {
+			t.Errorf("%s: got error %v, want number %d", c.statement, err, c.number)
+		}
+	}
+
+	if _, err := db.Exec("CREATE DATABASE ?", "D"); err == nil {
+		t.Error("a statement with bind variables succeeded")
+	}
+}
+
+func TestDriverShow(t *testing.T) {
+	db := testDB(t,
+		"CREATE DATABASE D COMMENT = 'it''s'",
+		`CREATE TRANSIENT SCHEMA D."Mixed" DATA_RETENTION_TIME_IN_DAYS = 0`,
+		"CREATE SCHEMA D.S2",
+		"CREATE ROLE R",
+	)
+	defer db.Close()
+
+	columns, rows := testQuery(t, db, "SHOW DATABASES LIKE 'd'")
+	if !reflect.DeepEqual(columns, kindDatabase.columns) {
+		t.Fatalf("SHOW DATABASES columns = %q, want %q", columns, kindDatabase.columns)
+	}
+	want := [][]interface{}{{"<time>", "D", "N", "N", "", "SYSADMIN", "it's", "", "1"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("SHOW DATABASES = %q, want %q", rows, want)
+	}
+
+	// LIKE is case insensitive, names are not.
+	columns, rows = testQuery(t, db, "SHOW TERSE SCHEMAS LIKE 'MIX%' IN DATABASE D")
+	if !reflect.DeepEqual(columns, kindSchema.columns) {
+		t.Fatalf("SHOW SCHEMAS columns = %q, want %q", columns, kindSchema.columns)
+	}
+	want = [][]interface{}{{"<time>", "Mixed", "N", "N", "D", "SYSADMIN", "", "TRANSIENT", "0"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("SHOW SCHEMAS = %q, want %q", rows, want)
+	}
+	// Every database comes with a PUBLIC schema.
+	if _, rows = testQuery(t, db, "SHOW SCHEMAS IN DATABASE D"); len(rows) != 3 {
+		t.Fatalf("SHOW SCHEMAS returned %d schemas, want 3", len(rows))
+	}
+	if _, rows = testQuery(t, db, `SHOW SCHEMAS LIKE 'S\\_' IN DATABASE D`); len(rows) != 0 {
+		t.Fatalf("an escaped _ matched %q", rows)
+	}
+
+	// Every kind shows its columns, also without rows.
+	for _, k := range kinds {
+		columns, _ := testQuery(t, db, "SHOW "+k.plural)
+		if !reflect.DeepEqual(columns, k.columns) {
+			t.Errorf("SHOW %s columns = %q, want %q", k.plural, columns, k.columns)
+		}
+	}
+
+	// The grants of an object include its ownership.
+	columns, rows = testQuery(t, db, "SHOW GRANTS ON ROLE R")
+	if len(rows) != 1 {
+		t.Fatalf("SHOW GRANTS ON ROLE = %q", rows)
+	}
+	grant := map[string]interface{}{}
+	for i, column := range columns {
+		grant[column] = rows[0][i]
+	}
+	if grant["privilege"] != "OWNERSHIP" || grant["granted_on"] != "ROLE" || grant["name"] != "R" || grant["grantee_name"] != "SYSADMIN" {
+		t.Fatalf("SHOW GRANTS ON ROLE = %v", grant)
+	}
+}
+
+func TestDriverDescribe(t *testing.T) {
+	db := testDB(t,
+		"CREATE DATABASE D",
+		"CREATE SCHEMA D.S",
+		`CREATE TABLE D.S.T (ID NUMBER(38,0) NOT NULL, "name" VARCHAR DEFAULT 'X')`,
+		"CREATE USER U LOGIN_NAME = 'u@example.com' COMMENT = 'c'",
+	)
+	defer db.Close()
+
+	columns, rows := testQuery(t, db, "DESC TABLE D.S.T")
+	wantColumns := []string{"name", "type", "kind", "null?", "default", "primary key", "unique key", "check", "expression", "comment", "policy name"}
+	if !reflect.DeepEqual(columns, wantColumns) {
+		t.Fatalf("DESC TABLE columns = %q, want %q", columns, wantColumns)
+	}
+	want := [][]interface{}{
+		{"ID", "NUMBER(38,0)", "COLUMN", "N", nil, "N", "N", nil, nil, nil, nil},
+		{"name", "VARCHAR(16777216)", "COLUMN", "Y", "'X'", "N", "N", nil, nil, nil, nil},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("DESC TABLE = %q, want %q", rows, want)
+	}
+
+	columns, rows = testQuery(t, db, "DESCRIBE USER U")
+	if want := []string{"property", "value", "default", "description"}; !reflect.DeepEqual(columns, want) {
+		t.Fatalf("DESC USER columns = %q, want %q", columns, want)
+	}
+	properties := map[interface{}]interface{}{}
+	for _, row := range rows {
+		properties[row[0]] = row[1]
+	}
+	if properties["LOGIN_NAME"] != "U@EXAMPLE.COM" || properties["COMMENT"] != "c" {
+		t.Fatalf("DESC USER = %q", rows)
+	}
+
+	if _, err := db.Exec("DESC TABLE D.S.T2"); err == nil {
+		t.Fatal("describing a table that does not exist succeeded")
+	}
+	if _, err := db.Exec("DESC DATABASE D"); err == nil {
+		t.Fatal("describing a database succeeded")
+	}
+}
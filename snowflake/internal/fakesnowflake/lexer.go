@@ -0,0 +1,441 @@
+package fakesnowflake
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // keyword or unquoted identifier
+	tokenQuoted                  // double quoted identifier
+	tokenString                  // single quoted string literal
+	tokenNumber
+	tokenPunct
+	tokenEOF
+)
+
+// token is a lexical token of a statement. text holds the unescaped value of
+// quoted identifiers and string literals, and the source text of everything
+// else. start and end are the offsets of the token in the statement.
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+	end   int
+}
+
+func isWordStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isWordPart(c byte) bool {
+	return isWordStart(c) || isDigit(c) || c == '$'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// lex splits a statement into tokens. Characters without a token of their own
+// are returned as single character punctuation, the provider only needs the
+// structure of the parts of a statement that the stand-in interprets.
+func lex(s string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isWordStart(c):
+			j := i
+			for j < len(s) && isWordPart(s[j]) {
+				j++
+			}
+			tokens = append(tokens, token{tokenWord, s[i:j], i, j})
+			i = j
+		case isDigit(c):
+			j := i
+			for j < len(s) && (isDigit(s[j]) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokenNumber, s[i:j], i, j})
+			i = j
+		case c == '"':
+			text, j, err := lexQuoted(s, i, '"')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokenQuoted, text, i, j})
+			i = j
+		case c == '\'':
+			text, j, err := lexQuoted(s, i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokenString, text, i, j})
+			i = j
+		default:
+			tokens = append(tokens, token{tokenPunct, s[i : i+1], i, i + 1})
+			i++
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(s), len(s)}), nil
+}
+
+// lexQuoted reads the quoted identifier or string literal starting at i. The
+// quote is escaped by doubling it, string literals also take backslash
+// escapes.
+func lexQuoted(s string, i int, quote byte) (string, int, error) {
+	var b strings.Builder
+	j := i + 1
+	for j < len(s) {
+		c := s[j]
+		switch {
+		case c == quote && j+1 < len(s) && s[j+1] == quote:
+			b.WriteByte(quote)
+			j += 2
+		case c == quote:
+			return b.String(), j + 1, nil
+		case c == '\\' && quote == '\'' && j+1 < len(s):
+			switch s[j+1] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(s[j+1])
+			}
+			j += 2
+		default:
+			b.WriteByte(c)
+			j++
+		}
+	}
+	return "", 0, syntaxError(s, i, fmt.Sprintf("unterminated %c", quote))
+}
+
+// parser walks the tokens of a statement.
+type parser struct {
+	src    string
+	tokens []token
+	i      int
+}
+
+func newParser(statement string) (*parser, error) {
+	tokens, err := lex(statement)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{src: statement, tokens: tokens}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+// isKeyword reports whether the next tokens are the given keywords.
+func (p *parser) isKeyword(words ...string) bool {
+	for n, w := range words {
+		if p.i+n >= len(p.tokens) {
+			return false
+		}
+		t := p.tokens[p.i+n]
+		if t.kind != tokenWord || !strings.EqualFold(t.text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// keyword consumes the given keywords if they are next.
+func (p *parser) keyword(words ...string) bool {
+	if !p.isKeyword(words...) {
+		return false
+	}
+	p.i += len(words)
+	return true
+}
+
+func (p *parser) expectKeyword(words ...string) error {
+	if !p.keyword(words...) {
+		return p.unexpected()
+	}
+	return nil
+}
+
+// punct consumes the punctuation c if it is next.
+func (p *parser) punct(c string) bool {
+	if t := p.peek(); t.kind == tokenPunct && t.text == c {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectPunct(c string) error {
+	if !p.punct(c) {
+		return p.unexpected()
+	}
+	return nil
+}
+
+// identifier reads an identifier and returns the name it resolves to:
+// unquoted identifiers are upper cased, quoted ones kept as is.
+func (p *parser) identifier() (string, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenWord:
+		p.i++
+		return strings.ToUpper(t.text), nil
+	case tokenQuoted:
+		p.i++
+		return t.text, nil
+	}
+	return "", p.unexpected()
+}
+
+// qualifiedName reads a dot separated name of up to max identifiers.
+func (p *parser) qualifiedName(max int) ([]string, error) {
+	var names []string
+	for {
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if len(names) == max || !p.punct(".") {
+			return names, nil
+		}
+	}
+}
+
+// stringLiteral reads a string literal.
+func (p *parser) stringLiteral() (string, error) {
+	t := p.peek()
+	if t.kind != tokenString {
+		return "", p.unexpected()
+	}
+	p.i++
+	return t.text, nil
+}
+
+// parenthesized reads a parenthesized clause and returns its source text
+// without the parentheses.
+func (p *parser) parenthesized() (string, error) {
+	open := p.peek()
+	if err := p.expectPunct("("); err != nil {
+		return "", err
+	}
+	depth := 1
+	for {
+		t := p.next()
+		switch {
+		case t.kind == tokenEOF:
+			return "", p.unexpected()
+		case t.kind == tokenPunct && t.text == "(":
+			depth++
+		case t.kind == tokenPunct && t.text == ")":
+			depth--
+			if depth == 0 {
+				return strings.TrimSpace(p.src[open.end:t.start]), nil
+			}
+		}
+	}
+}
+
+// rest consumes and returns the source text of the remaining statement.
+func (p *parser) rest() string {
+	rest := p.src[p.peek().start:]
+	p.i = len(p.tokens) - 1
+	return rest
+}
+
+// end fails unless the whole statement has been read.
+func (p *parser) end() error {
+	p.punct(";")
+	if p.peek().kind != tokenEOF {
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return syntaxError(p.src, t.start, "unexpected end of statement")
+	}
+	return syntaxError(p.src, t.start, fmt.Sprintf("unexpected '%s'", p.src[t.start:t.end]))
+}
+
+// properties reads KEY = value pairs, optionally preceded by WITH and
+// separated by commas, into values until something else is next. See value
+// for how values are kept.
+func (p *parser) properties(values map[string]string) error {
+	for {
+		p.keyword("WITH")
+		t := p.peek()
+		if t.kind != tokenWord || p.isKeyword("AS") {
+			return nil
+		}
+		if n := p.tokens[p.i+1]; n.kind != tokenPunct || n.text != "=" {
+			return nil
+		}
+		p.i += 2
+		value, err := p.value()
+		if err != nil {
+			return err
+		}
+		values[strings.ToUpper(t.text)] = value
+		p.punct(",")
+	}
+}
+
+// value reads the value of a property: string literals are unquoted, names
+// resolved, see identifier, and parenthesized options kept as they were
+// written.
+func (p *parser) value() (string, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenString:
+		p.i++
+		return t.text, nil
+	case t.kind == tokenNumber:
+		p.i++
+		return t.text, nil
+	case t.kind == tokenPunct && t.text == "-":
+		p.i++
+		if n := p.peek(); n.kind == tokenNumber {
+			p.i++
+			return "-" + n.text, nil
+		}
+	case t.kind == tokenPunct && t.text == "(":
+		return p.parenthesized()
+	case t.kind == tokenWord || t.kind == tokenQuoted:
+		names, err := p.qualifiedName(3)
+		if err != nil {
+			return "", err
+		}
+		return displayPath(names), nil
+	}
+	return "", p.unexpected()
+}
+
+// keys reads the comma separated property names of UNSET.
+func (p *parser) keys() ([]string, error) {
+	var keys []string
+	for {
+		t := p.peek()
+		if t.kind != tokenWord {
+			return nil, p.unexpected()
+		}
+		p.i++
+		keys = append(keys, strings.ToUpper(t.text))
+		if !p.punct(",") {
+			return keys, nil
+		}
+	}
+}
+
+// privileges reads the comma separated privileges of GRANT and REVOKE, e.g.
+// SELECT, INSERT or ALL PRIVILEGES.
+func (p *parser) privileges() ([]string, error) {
+	var privileges []string
+	for {
+		var words []string
+		for p.peek().kind == tokenWord && !p.isKeyword("ON") {
+			words = append(words, strings.ToUpper(p.next().text))
+		}
+		if len(words) == 0 {
+			return nil, p.unexpected()
+		}
+		privileges = append(privileges, strings.Join(words, " "))
+		if !p.punct(",") {
+			return privileges, nil
+		}
+	}
+}
+
+// dataType reads the data type of a column and returns it the way DESC TABLE
+// shows it, e.g. VARCHAR becomes VARCHAR(16777216).
+func (p *parser) dataType() (string, error) {
+	t := p.peek()
+	if t.kind != tokenWord {
+		return "", p.unexpected()
+	}
+	p.i++
+	name := strings.ToUpper(t.text)
+	if name == "DOUBLE" {
+		p.keyword("PRECISION")
+	}
+	args := ""
+	if p.peek().kind == tokenPunct && p.peek().text == "(" {
+		var err error
+		if args, err = p.parenthesized(); err != nil {
+			return "", err
+		}
+		args = strings.Join(strings.Fields(args), "")
+	}
+	or := func(def string) string {
+		if args == "" {
+			return def
+		}
+		return args
+	}
+	switch name {
+	case "INT", "INTEGER", "BIGINT", "SMALLINT", "TINYINT", "BYTEINT":
+		return "NUMBER(38,0)", nil
+	case "NUMBER", "DECIMAL", "NUMERIC":
+		if !strings.Contains(or("38"), ",") {
+			return "NUMBER(" + or("38") + ",0)", nil
+		}
+		return "NUMBER(" + args + ")", nil
+	case "VARCHAR", "STRING", "TEXT":
+		return "VARCHAR(" + or("16777216") + ")", nil
+	case "CHAR", "CHARACTER":
+		return "VARCHAR(" + or("1") + ")", nil
+	case "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE", "REAL":
+		return "FLOAT", nil
+	case "TIMESTAMP", "DATETIME":
+		return "TIMESTAMP_NTZ(" + or("9") + ")", nil
+	case "TIMESTAMP_NTZ", "TIMESTAMP_LTZ", "TIMESTAMP_TZ", "TIME":
+		return name + "(" + or("9") + ")", nil
+	case "BINARY", "VARBINARY":
+		return "BINARY(" + or("8388608") + ")", nil
+	}
+	if args != "" {
+		return name + "(" + args + ")", nil
+	}
+	return name, nil
+}
+
+// expression consumes and returns the source text of an expression, up to the
+// next comma outside of parentheses.
+func (p *parser) expression() string {
+	start := p.peek().start
+	depth := 0
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokenEOF:
+			return strings.TrimSpace(p.src[start:t.start])
+		case t.kind == tokenPunct && t.text == "(":
+			depth++
+		case t.kind == tokenPunct && t.text == ")":
+			depth--
+		case t.kind == tokenPunct && t.text == "," && depth == 0:
+			return strings.TrimSpace(p.src[start:t.start])
+		}
+		p.i++
+	}
+}
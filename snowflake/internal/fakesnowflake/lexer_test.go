@@ -0,0 +1,103 @@
+package fakesnowflake
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	tokens, err := lex(`CREATE TABLE db."My ""T""" (ID number(38,0) DEFAULT 'it''s \\ a\n')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []token{
+		{tokenWord, "CREATE", 0, 6},
+		{tokenWord, "TABLE", 7, 12},
+		{tokenWord, "db", 13, 15},
+		{tokenPunct, ".", 15, 16},
+		{tokenQuoted, `My "T"`, 16, 26},
+		{tokenPunct, "(", 27, 28},
+		{tokenWord, "ID", 28, 30},
+		{tokenWord, "number", 31, 37},
+		{tokenPunct, "(", 37, 38},
+		{tokenNumber, "38", 38, 40},
+		{tokenPunct, ",", 40, 41},
+		{tokenNumber, "0", 41, 42},
+		{tokenPunct, ")", 42, 43},
+		{tokenWord, "DEFAULT", 44, 51},
+		{tokenString, "it's \\ a\n", 52, 66},
+		{tokenPunct, ")", 66, 67},
+		{tokenEOF, "", 67, 67},
+	}
+	if !reflect.DeepEqual(tokens, want) {
+		t.Fatalf("lex =\n%v\nwant\n%v", tokens, want)
+	}
+
+	for _, s := range []string{`SHOW TABLES IN "D`, `CREATE DATABASE D COMMENT = 'c`, `COMMENT = 'c\'`} {
+		if _, err := lex(s); err == nil {
+			t.Errorf("lex(%q) succeeded", s)
+		}
+	}
+}
+
+func TestParserQualifiedName(t *testing.T) {
+	cases := []struct {
+		s    string
+		max  int
+		want []string
+		err  bool
+	}{
+		{s: "d", max: 3, want: []string{"D"}},
+		{s: `d.s."t"`, max: 3, want: []string{"D", "S", "t"}},
+		{s: `"a.b"."C d"`, max: 2, want: []string{"a.b", "C d"}},
+		{s: "d.s.t", max: 2, err: true},
+		{s: "d..t", max: 3, err: true},
+		{s: "'d'", max: 1, err: true},
+	}
+	for _, c := range cases {
+		p, err := newParser(c.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := p.qualifiedName(c.max)
+		if err == nil {
+			err = p.end()
+		}
+		if c.err {
+			if err == nil {
+				t.Errorf("qualifiedName(%q) = %q, want an error", c.s, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("qualifiedName(%q): %v", c.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("qualifiedName(%q) = %q, want %q", c.s, got, c.want)
+		}
+	}
+}
+
+func TestParserProperties(t *testing.T) {
+	p, err := newParser(`WITH COMMENT = 'it''s', retention_time = 1, file_format = (TYPE = CSV NULL_IF = ('a')), owner = sysadmin AS SELECT`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]string{}
+	if err := p.properties(values); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"COMMENT":        "it's",
+		"RETENTION_TIME": "1",
+		"FILE_FORMAT":    "TYPE = CSV NULL_IF = ('a')",
+		"OWNER":          "SYSADMIN",
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("properties = %q, want %q", values, want)
+	}
+	if !p.keyword("AS", "SELECT") {
+		t.Fatalf("properties read past its end, next is %q", p.peek().text)
+	}
+}
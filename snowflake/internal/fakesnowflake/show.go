@@ -0,0 +1,972 @@
+package fakesnowflake
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The kinds of objects the stand-in knows, with the columns of their SHOW
+// output as of this writing.
+var (
+	kindDatabase = &kind{name: "DATABASE", plural: "DATABASES", depth: 1, columns: []string{
+		"created_on", "name", "is_default", "is_current", "origin", "owner", "comment", "options", "retention_time",
+	}}
+	kindSchema = &kind{name: "SCHEMA", plural: "SCHEMAS", depth: 2, columns: []string{
+		"created_on", "name", "is_default", "is_current", "database_name", "owner", "comment", "options", "retention_time",
+	}}
+	kindTable = &kind{name: "TABLE", plural: "TABLES", depth: 3, columns: []string{
+		"created_on", "name", "database_name", "schema_name", "kind", "comment", "cluster_by", "rows", "bytes",
+		"owner", "retention_time", "automatic_clustering", "change_tracking",
+	}}
+	kindView = &kind{name: "VIEW", plural: "VIEWS", depth: 3, columns: []string{
+		"created_on", "name", "reserved", "database_name", "schema_name", "owner", "comment", "text", "is_secure",
+		"is_materialized",
+	}}
+	kindStage = &kind{name: "STAGE", plural: "STAGES", depth: 3, columns: []string{
+		"created_on", "name", "database_name", "schema_name", "url", "has_credentials", "has_encryption_key",
+		"owner", "comment", "region", "type", "cloud", "notification_channel", "storage_integration",
+	}}
+	kindPipe = &kind{name: "PIPE", plural: "PIPES", depth: 3, columns: []string{
+		"created_on", "name", "database_name", "schema_name", "definition", "owner", "notification_channel",
+		"comment", "integration", "pattern",
+	}}
+	kindUser = &kind{name: "USER", plural: "USERS", depth: 1, columns: []string{
+		"name", "created_on", "login_name", "display_name", "first_name", "last_name", "email", "mins_to_unlock",
+		"days_to_expiry", "comment", "disabled", "must_change_password", "snowflake_lock", "default_warehouse",
+		"default_namespace", "default_role", "ext_authn_duo", "ext_authn_uid", "mins_to_bypass_mfa", "owner",
+		"last_success_login", "expires_at_time", "locked_until_time", "has_password", "has_rsa_public_key",
+	}}
+	kindRole = &kind{name: "ROLE", plural: "ROLES", depth: 1, columns: []string{
+		"created_on", "name", "is_default", "is_current", "is_inherited", "assigned_to_users", "granted_to_roles",
+		"granted_roles", "owner", "comment",
+	}}
+	kindWarehouse = &kind{name: "WAREHOUSE", plural: "WAREHOUSES", depth: 1, columns: []string{
+		"name", "state", "type", "size", "min_cluster_count", "max_cluster_count", "started_clusters", "running",
+		"queued", "is_default", "is_current", "auto_suspend", "auto_resume", "available", "provisioning",
+		"quiescing", "other", "created_on", "resumed_on", "updated_on", "owner", "comment", "resource_monitor",
+		"actives", "pendings", "failed", "suspended", "uuid", "scaling_policy",
+	}}
+	kindShare = &kind{name: "SHARE", plural: "SHARES", depth: 1, columns: []string{
+		"created_on", "kind", "name", "database_name", "to", "owner", "comment",
+	}}
+	kindResourceMonitor = &kind{name: "RESOURCE MONITOR", plural: "RESOURCE MONITORS", depth: 1, columns: []string{
+		"name", "credit_quota", "used_credits", "remaining_credits", "level", "frequency", "start_time", "end_time",
+		"notify_at", "suspend_at", "suspend_immediately_at", "created_on", "owner", "comment", "notify_users",
+	}}
+	kindFileFormat = &kind{name: "FILE FORMAT", plural: "FILE FORMATS", depth: 3, columns: []string{
+		"created_on", "name", "database_name", "schema_name", "type", "owner", "comment", "format_options",
+	}}
+	kindSequence = &kind{name: "SEQUENCE", plural: "SEQUENCES", depth: 3, columns: []string{
+		"name", "database_name", "schema_name", "next_value", "interval", "created_on", "owner", "comment",
+	}}
+)
+
+func init() {
+	kindDatabase.row = databaseRow
+	kindSchema.row = schemaRow
+	kindTable.row = tableRow
+	kindView.row = viewRow
+	kindStage.row = stageRow
+	kindPipe.row = pipeRow
+	kindUser.row = userRow
+	kindRole.row = roleRow
+	kindWarehouse.row = warehouseRow
+	kindShare.row = shareRow
+	kindResourceMonitor.row = resourceMonitorRow
+	kindFileFormat.row = fileFormatRow
+	kindSequence.row = sequenceRow
+	kinds = []*kind{
+		kindResourceMonitor, kindFileFormat, kindDatabase, kindSchema, kindTable, kindView, kindStage, kindPipe,
+		kindUser, kindRole, kindWarehouse, kindShare, kindSequence,
+	}
+}
+
+// showColumnsGrants are the columns of SHOW GRANTS.
+var showColumnsGrants = []string{
+	"created_on", "privilege", "granted_on", "name", "granted_to", "grantee_name", "grant_option", "granted_by",
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Y"
+	}
+	return "N"
+}
+
+func trueFalse(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+// nullIfEmpty returns NULL for unset values.
+func nullIfEmpty(s string) driver.Value {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// propertyOr returns the property key of o or def if it is not set.
+func propertyOr(o *object, key string, def string) string {
+	if v, ok := o.properties[key]; ok {
+		return v
+	}
+	return def
+}
+
+func isNull(value string) bool {
+	return strings.EqualFold(value, "NULL")
+}
+
+func isTrue(value string) bool {
+	return strings.EqualFold(value, "TRUE")
+}
+
+func options(o *object) string {
+	if o.modifiers["TRANSIENT"] {
+		return "TRANSIENT"
+	}
+	return ""
+}
+
+func databaseRow(c *catalog, o *object) map[string]driver.Value {
+	return map[string]driver.Value{
+		"created_on":     o.created,
+		"name":           o.name(),
+		"is_default":     "N",
+		"is_current":     "N",
+		"origin":         "",
+		"owner":          o.owner,
+		"comment":        o.property("COMMENT"),
+		"options":        options(o),
+		"retention_time": propertyOr(o, "DATA_RETENTION_TIME_IN_DAYS", "1"),
+	}
+}
+
+func schemaRow(c *catalog, o *object) map[string]driver.Value {
+	return map[string]driver.Value{
+		"created_on":     o.created,
+		"name":           o.name(),
+		"is_default":     "N",
+		"is_current":     "N",
+		"database_name":  o.path[0],
+		"owner":          o.owner,
+		"comment":        o.property("COMMENT"),
+		"options":        options(o),
+		"retention_time": propertyOr(o, "DATA_RETENTION_TIME_IN_DAYS", "1"),
+	}
+}
+
+func tableRow(c *catalog, o *object) map[string]driver.Value {
+	kind := "TABLE"
+	if o.modifiers["TRANSIENT"] {
+		kind = "TRANSIENT"
+	}
+	return map[string]driver.Value{
+		"created_on":           o.created,
+		"name":                 o.name(),
+		"database_name":        o.path[0],
+		"schema_name":          o.path[1],
+		"kind":                 kind,
+		"comment":              o.property("COMMENT"),
+		"cluster_by":           "",
+		"rows":                 "0",
+		"bytes":                "0",
+		"owner":                o.owner,
+		"retention_time":       propertyOr(o, "DATA_RETENTION_TIME_IN_DAYS", "1"),
+		"automatic_clustering": "OFF",
+		"change_tracking":      "OFF",
+	}
+}
+
+func viewRow(c *catalog, o *object) map[string]driver.Value {
+	return map[string]driver.Value{
+		"created_on":      o.created,
+		"name":            o.name(),
+		"reserved":        "",
+		"database_name":   o.path[0],
+		"schema_name":     o.path[1],
+		"owner":           o.owner,
+		"comment":         o.property("COMMENT"),
+		"text":            o.text,
+		"is_secure":       trueFalse(o.modifiers["SECURE"]),
+		"is_materialized": "false",
+	}
+}
+
+func stageRow(c *catalog, o *object) map[string]driver.Value {
+	row := map[string]driver.Value{
+		"created_on":         o.created,
+		"name":               o.name(),
+		"database_name":      o.path[0],
+		"schema_name":        o.path[1],
+		"url":                o.property("URL"),
+		"has_credentials":    yesNo(o.property("CREDENTIALS") != ""),
+		"has_encryption_key": yesNo(o.property("ENCRYPTION") != ""),
+		"owner":              o.owner,
+		"comment":            o.property("COMMENT"),
+		"type":               "INTERNAL",
+	}
+	if o.property("URL") != "" {
+		row["type"] = "EXTERNAL"
+		row["cloud"] = "AWS"
+	}
+	return row
+}
+
+func pipeRow(c *catalog, o *object) map[string]driver.Value {
+	return map[string]driver.Value{
+		"created_on":           o.created,
+		"name":                 o.name(),
+		"database_name":        o.path[0],
+		"schema_name":          o.path[1],
+		"definition":           o.query,
+		"owner":                o.owner,
+		"notification_channel": nullIfEmpty(notificationChannel(o)),
+		"comment":              o.property("COMMENT"),
+	}
+}
+
+// notificationChannel is the queue Snowflake creates for auto-ingest pipes.
+func notificationChannel(o *object) string {
+	if !isTrue(o.property("AUTO_INGEST")) {
+		return ""
+	}
+	return "arn:aws:sqs:us-west-2:000000000000:sf-snowpipe-FAKE-" + o.name()
+}
+
+// userProperties are the properties of DESC USER, in order.
+var userProperties = []string{
+	"NAME", "COMMENT", "DISPLAY_NAME", "LOGIN_NAME", "FIRST_NAME", "MIDDLE_NAME", "LAST_NAME", "EMAIL",
+	"PASSWORD", "MUST_CHANGE_PASSWORD", "DISABLED", "SNOWFLAKE_LOCK", "SNOWFLAKE_SUPPORT", "DAYS_TO_EXPIRY",
+	"MINS_TO_UNLOCK", "DEFAULT_WAREHOUSE", "DEFAULT_NAMESPACE", "DEFAULT_ROLE", "EXT_AUTHN_DUO",
+	"EXT_AUTHN_UID", "MINS_TO_BYPASS_MFA", "MINS_TO_BYPASS_NETWORK_POLICY", "RSA_PUBLIC_KEY_FP",
+	"RSA_PUBLIC_KEY_2_FP",
+}
+
+// userProperty returns the value of a DESC USER property of o.
+func userProperty(o *object, property string) string {
+	switch property {
+	case "NAME":
+		return o.name()
+	case "LOGIN_NAME":
+		return strings.ToUpper(propertyOr(o, "LOGIN_NAME", o.name()))
+	case "DISPLAY_NAME":
+		return propertyOr(o, "DISPLAY_NAME", o.name())
+	case "PASSWORD":
+		if o.property("PASSWORD") != "" {
+			return "********"
+		}
+		return ""
+	case "MUST_CHANGE_PASSWORD", "DISABLED", "SNOWFLAKE_LOCK", "SNOWFLAKE_SUPPORT", "EXT_AUTHN_DUO":
+		return strings.ToLower(propertyOr(o, property, "false"))
+	case "RSA_PUBLIC_KEY_FP":
+		return keyFingerprint(o.property("RSA_PUBLIC_KEY"))
+	case "RSA_PUBLIC_KEY_2_FP":
+		return keyFingerprint(o.property("RSA_PUBLIC_KEY_2"))
+	}
+	return o.property(property)
+}
+
+// keyFingerprint returns the SHA256 fingerprint Snowflake shows for a public
+// key.
+func keyFingerprint(key string) string {
+	if key == "" {
+		return ""
+	}
+	der, _ := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(key), ""))
+	sum := sha256.Sum256(der)
+	return "SHA256:" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func userRow(c *catalog, o *object) map[string]driver.Value {
+	return map[string]driver.Value{
+		"name":                 o.name(),
+		"created_on":           o.created,
+		"login_name":           userProperty(o, "LOGIN_NAME"),
+		"display_name":         userProperty(o, "DISPLAY_NAME"),
+		"first_name":           nullIfEmpty(o.property("FIRST_NAME")),
+		"last_name":            nullIfEmpty(o.property("LAST_NAME")),
+		"email":                nullIfEmpty(o.property("EMAIL")),
+		"comment":              o.property("COMMENT"),
+		"disabled":             userProperty(o, "DISABLED"),
+		"must_change_password": userProperty(o, "MUST_CHANGE_PASSWORD"),
+		"snowflake_lock":       userProperty(o, "SNOWFLAKE_LOCK"),
+		"default_warehouse":    nullIfEmpty(o.property("DEFAULT_WAREHOUSE")),
+		"default_namespace":    nullIfEmpty(o.property("DEFAULT_NAMESPACE")),
+		"default_role":         nullIfEmpty(o.property("DEFAULT_ROLE")),
+		"ext_authn_duo":        userProperty(o, "EXT_AUTHN_DUO"),
+		"owner":                o.owner,
+		"has_password":         trueFalse(o.property("PASSWORD") != ""),
+		"has_rsa_public_key":   trueFalse(o.property("RSA_PUBLIC_KEY") != ""),
+	}
+}
+
+func roleRow(c *catalog, o *object) map[string]driver.Value {
+	return map[string]driver.Value{
+		"created_on":        o.created,
+		"name":              o.name(),
+		"is_default":        "N",
+		"is_current":        "N",
+		"is_inherited":      "N",
+		"assigned_to_users": "0",
+		"granted_to_roles":  "0",
+		"granted_roles":     "0",
+		"owner":             o.owner,
+		"comment":           o.property("COMMENT"),
+	}
+}
+
+// warehouseSizes maps the sizes CREATE and ALTER WAREHOUSE accept to how SHOW
+// WAREHOUSES displays them.
+var warehouseSizes = map[string]string{
+	"XSMALL":   "X-Small",
+	"X-SMALL":  "X-Small",
+	"SMALL":    "Small",
+	"MEDIUM":   "Medium",
+	"LARGE":    "Large",
+	"XLARGE":   "X-Large",
+	"X-LARGE":  "X-Large",
+	"XXLARGE":  "2X-Large",
+	"X2LARGE":  "2X-Large",
+	"2X-LARGE": "2X-Large",
+	"XXXLARGE": "3X-Large",
+	"X3LARGE":  "3X-Large",
+	"3X-LARGE": "3X-Large",
+	"X4LARGE":  "4X-Large",
+	"4X-LARGE": "4X-Large",
+	"X5LARGE":  "5X-Large",
+	"5X-LARGE": "5X-Large",
+	"X6LARGE":  "6X-Large",
+	"6X-LARGE": "6X-Large",
+}
+
+// checkWarehouse fails if one of the properties of a warehouse has an invalid
+// value.
+func (c *catalog) checkWarehouse(properties map[string]string) error {
+	if size, ok := properties["WAREHOUSE_SIZE"]; ok {
+		if _, ok := warehouseSizes[strings.ToUpper(size)]; !ok {
+			return invalidValue(fmt.Sprintf("invalid value [%s] for parameter 'WAREHOUSE_SIZE'", size))
+		}
+	}
+	return c.checkResourceMonitor(properties)
+}
+
+// checkResourceMonitor fails unless the resource monitor in properties, if
+// any, exists.
+func (c *catalog) checkResourceMonitor(properties map[string]string) error {
+	monitor, ok := properties["RESOURCE_MONITOR"]
+	if !ok || isNull(monitor) {
+		return nil
+	}
+	_, err := c.get(kindResourceMonitor, []string{monitor})
+	return err
+}
+
+func warehouseRow(c *catalog, o *object) map[string]driver.Value {
+	state := "STARTED"
+	if o.suspended {
+		state = "SUSPENDED"
+	}
+	// Warehouses that never suspend show NULL.
+	var autoSuspend driver.Value = propertyOr(o, "AUTO_SUSPEND", "600")
+	if autoSuspend == "0" {
+		autoSuspend = nil
+	}
+	return map[string]driver.Value{
+		"name":              o.name(),
+		"state":             state,
+		"type":              "STANDARD",
+		"size":              warehouseSizes[strings.ToUpper(propertyOr(o, "WAREHOUSE_SIZE", "XSMALL"))],
+		"min_cluster_count": propertyOr(o, "MIN_CLUSTER_COUNT", "1"),
+		"max_cluster_count": propertyOr(o, "MAX_CLUSTER_COUNT", "1"),
+		"auto_suspend":      autoSuspend,
+		"auto_resume":       strings.ToLower(propertyOr(o, "AUTO_RESUME", "true")),
+		"created_on":        o.created,
+		"owner":             o.owner,
+		"comment":           o.property("COMMENT"),
+		"resource_monitor":  propertyOr(o, "RESOURCE_MONITOR", "null"),
+		"scaling_policy":    strings.ToUpper(propertyOr(o, "SCALING_POLICY", "STANDARD")),
+	}
+}
+
+// parameter is an object parameter, set like a property.
+type parameter struct {
+	key         string
+	def         string
+	typ         string
+	description string
+}
+
+// showColumnsParameters are the columns of SHOW PARAMETERS.
+var showColumnsParameters = []string{"key", "value", "default", "level", "description", "type"}
+
+// parameters are the parameters of objects by kind.
+var parameters = map[*kind][]parameter{
+	kindWarehouse: {
+		{"MAX_CONCURRENCY_LEVEL", "8", "NUMBER", "Concurrency level for SQL statements executed by a warehouse cluster."},
+		{"STATEMENT_QUEUED_TIMEOUT_IN_SECONDS", "0", "NUMBER", "Timeout in seconds for queued statements."},
+		{"STATEMENT_TIMEOUT_IN_SECONDS", "172800", "NUMBER", "Timeout in seconds for statements."},
+	},
+}
+
+// parameterRows returns the SHOW PARAMETERS output for o.
+func parameterRows(o *object, pattern string) *result {
+	var rows []map[string]driver.Value
+	for _, param := range parameters[o.kind] {
+		if pattern != "" && !like(pattern, param.key) {
+			continue
+		}
+		value, level := param.def, ""
+		if v, ok := o.properties[param.key]; ok {
+			value, level = v, o.kind.name
+		}
+		rows = append(rows, map[string]driver.Value{
+			"key":         param.key,
+			"value":       value,
+			"default":     param.def,
+			"level":       level,
+			"description": param.description,
+			"type":        param.typ,
+		})
+	}
+	return project(showColumnsParameters, rows)
+}
+
+// resourceMonitorLevel is what a resource monitor is assigned to.
+func (c *catalog) resourceMonitorLevel(o *object) driver.Value {
+	if c.account["RESOURCE_MONITOR"] == o.name() {
+		return "ACCOUNT"
+	}
+	for _, w := range c.list(kindWarehouse, nil) {
+		if w.property("RESOURCE_MONITOR") == o.name() {
+			return "WAREHOUSE"
+		}
+	}
+	return nil
+}
+
+// thresholds returns the thresholds of the triggers of o with action, e.g.
+// 50%,100%.
+func thresholds(o *object, action string) driver.Value {
+	var percents []string
+	for _, t := range o.triggers {
+		if t.action == action {
+			percents = append(percents, fmt.Sprintf("%d%%", t.threshold))
+		}
+	}
+	return nullIfEmpty(strings.Join(percents, ","))
+}
+
+// notifyUsers renders the NOTIFY_USERS of o, which holds the names the way
+// they were written.
+func notifyUsers(o *object) string {
+	users, _ := splitNames(o.property("NOTIFY_USERS"))
+	return strings.Join(users, ", ")
+}
+
+// showTimestampLayout is how SHOW commands render timestamps.
+const showTimestampLayout = "2006-01-02 15:04:05.000 -0700"
+
+// showTimestamp renders a timestamp the way it was set as SHOW commands do, in
+// the time zone of the account, which is the local one of the stand-in.
+// Timestamps without a time zone are in that of the account.
+func showTimestamp(s string) string {
+	layouts := []string{"2006-01-02 15:04:05 -0700", time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t.Local().Format(showTimestampLayout)
+		}
+	}
+	return s
+}
+
+func resourceMonitorRow(c *catalog, o *object) map[string]driver.Value {
+	var quota driver.Value
+	if q, err := strconv.ParseFloat(o.property("CREDIT_QUOTA"), 64); err == nil {
+		quota = fmt.Sprintf("%.2f", q)
+	}
+	start := o.created.Format(showTimestampLayout)
+	if s := o.property("START_TIMESTAMP"); s != "" && !strings.EqualFold(s, "IMMEDIATELY") {
+		start = showTimestamp(s)
+	}
+	return map[string]driver.Value{
+		"name":                   o.name(),
+		"credit_quota":           quota,
+		"used_credits":           "0.00",
+		"remaining_credits":      quota,
+		"level":                  c.resourceMonitorLevel(o),
+		"frequency":              strings.ToUpper(propertyOr(o, "FREQUENCY", "MONTHLY")),
+		"start_time":             start,
+		"end_time":               nullIfEmpty(showTimestamp(o.property("END_TIMESTAMP"))),
+		"notify_at":              thresholds(o, "NOTIFY"),
+		"suspend_at":             thresholds(o, "SUSPEND"),
+		"suspend_immediately_at": thresholds(o, "SUSPEND_IMMEDIATE"),
+		"created_on":             o.created,
+		"owner":                  o.owner,
+		"comment":                nullIfEmpty(o.property("COMMENT")),
+		"notify_users":           notifyUsers(o),
+	}
+}
+
+// fileFormatOption is an option of a file format type, with the property
+// type DESC FILE FORMAT shows and its default, kept like properties are.
+type fileFormatOption struct {
+	key string
+	typ string
+	def string
+}
+
+// fileFormatOptions are the options the stand-in knows of each file format
+// type, in the order DESC FILE FORMAT shows them.
+var fileFormatOptions = map[string][]fileFormatOption{
+	"CSV": {
+		{"RECORD_DELIMITER", "String", "\n"},
+		{"FIELD_DELIMITER", "String", ","},
+		{"FILE_EXTENSION", "String", ""},
+		{"SKIP_HEADER", "Integer", "0"},
+		{"FIELD_OPTIONALLY_ENCLOSED_BY", "String", "NONE"},
+		{"NULL_IF", "List", `'\\N'`},
+		{"COMPRESSION", "String", "AUTO"},
+		{"TRIM_SPACE", "Boolean", "false"},
+	},
+	"JSON": {
+		{"FILE_EXTENSION", "String", ""},
+		{"COMPRESSION", "String", "AUTO"},
+		{"STRIP_OUTER_ARRAY", "Boolean", "false"},
+	},
+	"AVRO": {
+		{"COMPRESSION", "String", "AUTO"},
+		{"TRIM_SPACE", "Boolean", "false"},
+	},
+	"ORC": {
+		{"TRIM_SPACE", "Boolean", "false"},
+	},
+	"PARQUET": {
+		{"COMPRESSION", "String", "AUTO"},
+		{"TRIM_SPACE", "Boolean", "false"},
+	},
+	"XML": {
+		{"FILE_EXTENSION", "String", ""},
+		{"COMPRESSION", "String", "AUTO"},
+	},
+}
+
+// descEscaper renders string values the way DESC FILE FORMAT does, with
+// backslash escapes.
+var descEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\t", `\t`, "\r", `\r`, `'`, `\'`, `"`, `\"`)
+
+// checkFileFormat fails unless typ is a file format type and properties only
+// has options of that type.
+func checkFileFormat(typ string, properties map[string]string) error {
+	options, ok := fileFormatOptions[typ]
+	if !ok {
+		return invalidValue(fmt.Sprintf("invalid value ['%s'] for parameter 'TYPE'", typ))
+	}
+	for key := range properties {
+		if key == "TYPE" || key == "COMMENT" {
+			continue
+		}
+		known := false
+		for _, option := range options {
+			known = known || option.key == key
+		}
+		if !known {
+			return invalidValue(fmt.Sprintf("invalid property '%s' for 'FILE_FORMAT' of TYPE %s", key, typ))
+		}
+	}
+	return nil
+}
+
+// checkFormatName fails unless the file format the FORMAT_NAME of a stage's
+// FILE_FORMAT options names, if any, exists.
+func (c *catalog) checkFormatName(fileFormat string) error {
+	name, ok := parseOptions(fileFormat)["FORMAT_NAME"]
+	if !ok {
+		return nil
+	}
+	path := strings.Split(name, ".")
+	if len(path) < kindFileFormat.depth {
+		return unsupported("unqualified object names")
+	}
+	_, err := c.get(kindFileFormat, path)
+	return err
+}
+
+// listValues returns the strings of a list option such as NULL_IF, kept as
+// the literals it was set to.
+func listValues(value string) []string {
+	values := []string{}
+	if p, err := newParser(value); err == nil {
+		for p.peek().kind == tokenString {
+			v, _ := p.stringLiteral()
+			values = append(values, v)
+			p.punct(",")
+		}
+	}
+	return values
+}
+
+// fileFormatValue renders the value of a file format option as in DESC FILE
+// FORMAT, lists as [a, b].
+func fileFormatValue(option fileFormatOption, value string) string {
+	switch option.typ {
+	case "String":
+		return descEscaper.Replace(value)
+	case "List":
+		var values []string
+		for _, v := range listValues(value) {
+			values = append(values, descEscaper.Replace(v))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	}
+	return strings.ToLower(value)
+}
+
+// formatOptions renders the options of a file format as the JSON object in
+// the format_options column of SHOW FILE FORMATS.
+func formatOptions(o *object) string {
+	typ := o.property("TYPE")
+	options := map[string]interface{}{"TYPE": typ}
+	for _, option := range fileFormatOptions[typ] {
+		value := propertyOr(o, option.key, option.def)
+		switch option.typ {
+		case "Integer":
+			n, _ := strconv.Atoi(value)
+			options[option.key] = n
+		case "Boolean":
+			options[option.key] = isTrue(value)
+		case "List":
+			options[option.key] = listValues(value)
+		default:
+			options[option.key] = value
+		}
+	}
+	b, _ := json.Marshal(options)
+	return string(b)
+}
+
+func fileFormatRow(c *catalog, o *object) map[string]driver.Value {
+	return map[string]driver.Value{
+		"created_on":     o.created,
+		"name":           o.name(),
+		"database_name":  o.path[0],
+		"schema_name":    o.path[1],
+		"type":           o.property("TYPE"),
+		"owner":          o.owner,
+		"comment":        o.property("COMMENT"),
+		"format_options": formatOptions(o),
+	}
+}
+
+// descFileFormat renders the options of a file format as in DESC FILE FORMAT.
+func descFileFormat(o *object) *result {
+	typ := o.property("TYPE")
+	rows := []map[string]driver.Value{{
+		"property":         "TYPE",
+		"property_type":    "String",
+		"property_value":   typ,
+		"property_default": "CSV",
+	}}
+	for _, option := range fileFormatOptions[typ] {
+		rows = append(rows, map[string]driver.Value{
+			"property":         option.key,
+			"property_type":    option.typ,
+			"property_value":   fileFormatValue(option, propertyOr(o, option.key, option.def)),
+			"property_default": fileFormatValue(option, option.def),
+		})
+	}
+	return project([]string{"property", "property_type", "property_value", "property_default"}, rows)
+}
+
+// sequenceRow shows the START of a sequence as its next value, the stand-in
+// does not evaluate NEXTVAL.
+func sequenceRow(c *catalog, o *object) map[string]driver.Value {
+	return map[string]driver.Value{
+		"name":          o.name(),
+		"database_name": o.path[0],
+		"schema_name":   o.path[1],
+		"next_value":    propertyOr(o, "START", "1"),
+		"interval":      propertyOr(o, "INCREMENT", "1"),
+		"created_on":    o.created,
+		"owner":         o.owner,
+		"comment":       o.property("COMMENT"),
+	}
+}
+
+func shareRow(c *catalog, o *object) map[string]driver.Value {
+	return map[string]driver.Value{
+		"created_on": o.created,
+		"kind":       "OUTBOUND",
+		"name":       o.name(),
+		"owner":      o.owner,
+		"comment":    o.property("COMMENT"),
+	}
+}
+
+// project renders rows onto columns.
+func project(columns []string, rows []map[string]driver.Value) *result {
+	r := &result{columns: columns}
+	for _, row := range rows {
+		values := make([]driver.Value, len(columns))
+		for i, column := range columns {
+			values[i] = row[column]
+		}
+		r.rows = append(r.rows, values)
+	}
+	return r
+}
+
+// like reports whether s matches the LIKE pattern, case insensitively as in
+// SHOW commands.
+func like(pattern string, s string) bool {
+	var re strings.Builder
+	re.WriteString("(?is)^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case c == '%':
+			re.WriteString(".*")
+		case c == '_':
+			re.WriteString(".")
+		default:
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	re.WriteString("$")
+	return regexp.MustCompile(re.String()).MatchString(s)
+}
+
+// grantRows renders grants as in SHOW GRANTS.
+func grantRows(grants []*grant) *result {
+	var rows []map[string]driver.Value
+	for _, g := range grants {
+		rows = append(rows, map[string]driver.Value{
+			"created_on":   g.created,
+			"privilege":    g.privilege,
+			"granted_on":   g.on.name,
+			"name":         displayPath(g.path),
+			"granted_to":   g.granteeType,
+			"grantee_name": g.grantee,
+			"grant_option": trueFalse(g.grantOption),
+			"granted_by":   g.grantedBy,
+		})
+	}
+	return project(showColumnsGrants, rows)
+}
+
+// descTable renders the columns of a table as in DESC TABLE.
+func descTable(o *object) *result {
+	var rows []map[string]driver.Value
+	for _, col := range o.columns {
+		rows = append(rows, map[string]driver.Value{
+			"name":        col.name,
+			"type":        col.typ,
+			"kind":        "COLUMN",
+			"null?":       yesNo(col.nullable),
+			"default":     nullIfEmpty(col.def),
+			"primary key": "N",
+			"unique key":  "N",
+		})
+	}
+	return project([]string{
+		"name", "type", "kind", "null?", "default", "primary key", "unique key", "check", "expression", "comment",
+		"policy name",
+	}, rows)
+}
+
+// descUser renders the properties of a user as in DESC USER.
+func descUser(o *object) *result {
+	var rows []map[string]driver.Value
+	for _, property := range userProperties {
+		rows = append(rows, map[string]driver.Value{
+			"property": property,
+			"value":    nullIfEmpty(userProperty(o, property)),
+		})
+	}
+	return project([]string{"property", "value", "default", "description"}, rows)
+}
+
+// descStage renders the properties of a stage as in DESC STAGE. The AWS
+// external ID and IAM user Snowflake generates for stages assuming a role are
+// derived from the stage name.
+func descStage(o *object) *result {
+	credentials := parseOptions(o.property("CREDENTIALS"))
+	url := ""
+	if o.property("URL") != "" {
+		url = `["` + o.property("URL") + `"]`
+	}
+	externalID, iamUser := "", ""
+	if credentials["AWS_ROLE"] != "" {
+		externalID = "FAKE_SFCRole=1_" + o.name()
+		iamUser = "arn:aws:iam::000000000000:user/fake"
+	}
+	properties := [][]string{
+		{"STAGE_LOCATION", "URL", url},
+		{"STAGE_CREDENTIALS", "AWS_KEY_ID", credentials["AWS_KEY_ID"]},
+		{"STAGE_CREDENTIALS", "AWS_ROLE", credentials["AWS_ROLE"]},
+		{"STAGE_CREDENTIALS", "AWS_EXTERNAL_ID", externalID},
+		{"STAGE_CREDENTIALS", "SNOWFLAKE_IAM_USER", iamUser},
+	}
+	var rows []map[string]driver.Value
+	for _, p := range properties {
+		rows = append(rows, map[string]driver.Value{
+			"parent_property":  p[0],
+			"property":         p[1],
+			"property_type":    "String",
+			"property_value":   p[2],
+			"property_default": "",
+		})
+	}
+	return project([]string{"parent_property", "property", "property_type", "property_value", "property_default"}, rows)
+}
+
+// infoSchemaViews are the information_schema views the stand-in has, by name.
+// rows returns the rows of a database.
+var infoSchemaViews map[string]struct {
+	columns []string
+	rows    func(c *catalog, database string) []map[string]driver.Value
+}
+
+func init() {
+	infoSchemaViews = map[string]struct {
+		columns []string
+		rows    func(c *catalog, database string) []map[string]driver.Value
+	}{
+		"SCHEMATA": {
+			columns: []string{
+				"CATALOG_NAME", "SCHEMA_NAME", "SCHEMA_OWNER", "IS_TRANSIENT", "RETENTION_TIME",
+				"DEFAULT_CHARACTER_SET_CATALOG", "DEFAULT_CHARACTER_SET_SCHEMA", "DEFAULT_CHARACTER_SET_NAME",
+				"SQL_PATH", "CREATED", "LAST_ALTERED", "COMMENT",
+			},
+			rows: infoSchemaSchemata,
+		},
+		"TABLES": {
+			columns: []string{
+				"TABLE_CATALOG", "TABLE_SCHEMA", "TABLE_NAME", "TABLE_OWNER", "TABLE_TYPE", "IS_TRANSIENT",
+				"CLUSTERING_KEY", "ROW_COUNT", "BYTES", "RETENTION_TIME", "SELF_REFERENCING_COLUMN_NAME",
+				"REFERENCE_GENERATION", "USER_DEFINED_TYPE_CATALOG", "USER_DEFINED_TYPE_SCHEMA",
+				"USER_DEFINED_TYPE_NAME", "IS_INSERTABLE_INTO", "IS_TYPED", "COMMIT_ACTION", "CREATED",
+				"LAST_ALTERED", "AUTO_CLUSTERING_ON", "COMMENT",
+			},
+			rows: infoSchemaTables,
+		},
+		"VIEWS": {
+			columns: []string{
+				"TABLE_CATALOG", "TABLE_SCHEMA", "TABLE_NAME", "TABLE_OWNER", "VIEW_DEFINITION", "CHECK_OPTION",
+				"IS_UPDATABLE", "INSERTABLE_INTO", "IS_SECURE", "CREATED", "LAST_ALTERED", "COMMENT",
+			},
+			rows: infoSchemaViewRows,
+		},
+		"OBJECT_PRIVILEGES": {
+			columns: []string{
+				"GRANTOR", "GRANTEE", "PRIVILEGE_TYPE", "IS_GRANTABLE", "OBJECT_CATALOG", "OBJECT_SCHEMA",
+				"OBJECT_NAME", "OBJECT_TYPE", "CREATED",
+			},
+			rows: infoSchemaObjectPrivileges,
+		},
+	}
+}
+
+func infoSchemaSchemata(c *catalog, database string) []map[string]driver.Value {
+	var rows []map[string]driver.Value
+	for _, o := range c.list(kindSchema, []string{database}) {
+		rows = append(rows, map[string]driver.Value{
+			"CATALOG_NAME":   database,
+			"SCHEMA_NAME":    o.name(),
+			"SCHEMA_OWNER":   o.owner,
+			"IS_TRANSIENT":   yesNo(o.modifiers["TRANSIENT"]),
+			"RETENTION_TIME": propertyOr(o, "DATA_RETENTION_TIME_IN_DAYS", "1"),
+			"CREATED":        o.created,
+			"LAST_ALTERED":   o.created,
+			"COMMENT":        nullIfEmpty(o.property("COMMENT")),
+		})
+	}
+	return rows
+}
+
+func infoSchemaTables(c *catalog, database string) []map[string]driver.Value {
+	var rows []map[string]driver.Value
+	for _, k := range []*kind{kindTable, kindView} {
+		for _, o := range c.list(k, []string{database}) {
+			row := map[string]driver.Value{
+				"TABLE_CATALOG": database,
+				"TABLE_SCHEMA":  o.path[1],
+				"TABLE_NAME":    o.name(),
+				"TABLE_OWNER":   o.owner,
+				"TABLE_TYPE":    "VIEW",
+				"CREATED":       o.created,
+				"LAST_ALTERED":  o.created,
+				"COMMENT":       nullIfEmpty(o.property("COMMENT")),
+			}
+			if k == kindTable {
+				row["TABLE_TYPE"] = "BASE TABLE"
+				row["IS_TRANSIENT"] = yesNo(o.modifiers["TRANSIENT"])
+				row["ROW_COUNT"] = "0"
+				row["BYTES"] = "0"
+				row["RETENTION_TIME"] = propertyOr(o, "DATA_RETENTION_TIME_IN_DAYS", "1")
+				row["IS_INSERTABLE_INTO"] = "YES"
+				row["IS_TYPED"] = "YES"
+				row["AUTO_CLUSTERING_ON"] = "NO"
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func infoSchemaViewRows(c *catalog, database string) []map[string]driver.Value {
+	var rows []map[string]driver.Value
+	for _, o := range c.list(kindView, []string{database}) {
+		rows = append(rows, map[string]driver.Value{
+			"TABLE_CATALOG":   database,
+			"TABLE_SCHEMA":    o.path[1],
+			"TABLE_NAME":      o.name(),
+			"TABLE_OWNER":     o.owner,
+			"VIEW_DEFINITION": o.text,
+			"CHECK_OPTION":    "NONE",
+			"IS_UPDATABLE":    "NO",
+			"INSERTABLE_INTO": "NO",
+			"IS_SECURE":       map[bool]string{true: "YES", false: "NO"}[o.modifiers["SECURE"]],
+			"CREATED":         o.created,
+			"LAST_ALTERED":    o.created,
+			"COMMENT":         nullIfEmpty(o.property("COMMENT")),
+		})
+	}
+	return rows
+}
+
+func infoSchemaObjectPrivileges(c *catalog, database string) []map[string]driver.Value {
+	var rows []map[string]driver.Value
+	for _, g := range c.grants {
+		if len(g.path) == 0 || g.path[0] != database || g.on == kindDatabase {
+			continue
+		}
+		row := map[string]driver.Value{
+			"GRANTOR":        g.grantedBy,
+			"GRANTEE":        g.grantee,
+			"PRIVILEGE_TYPE": g.privilege,
+			"IS_GRANTABLE":   yesNo(g.grantOption),
+			"OBJECT_CATALOG": database,
+			"OBJECT_NAME":    g.path[len(g.path)-1],
+			"OBJECT_TYPE":    g.on.name,
+			"CREATED":        g.created,
+		}
+		if len(g.path) == 3 {
+			row["OBJECT_SCHEMA"] = g.path[1]
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,946 @@
+package fakesnowflake
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// execution is a statement being executed on a connection.
+type execution struct {
+	c         *catalog
+	cn        *conn
+	p         *parser
+	statement string
+}
+
+// execute executes a statement on behalf of the connection cn.
+func (c *catalog) execute(cn *conn, statement string) (*result, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, err := newParser(statement)
+	if err != nil {
+		return nil, err
+	}
+	e := &execution{c: c, cn: cn, p: p, statement: statement}
+	switch {
+	case p.keyword("CREATE"):
+		return e.create()
+	case p.keyword("ALTER"):
+		return e.alter()
+	case p.keyword("DROP"):
+		return e.drop()
+	case p.keyword("SHOW"):
+		return e.show()
+	case p.keyword("DESC"), p.keyword("DESCRIBE"):
+		return e.describe()
+	case p.keyword("GRANT"):
+		return e.grant(true)
+	case p.keyword("REVOKE"):
+		return e.grant(false)
+	case p.keyword("USE"):
+		return e.use()
+	case p.keyword("SELECT"):
+		return e.query()
+	}
+	return nil, p.unexpected()
+}
+
+// kind reads the name of a kind, e.g. TABLE, or returns nil if none is next.
+func (e *execution) kind() *kind {
+	for _, k := range kinds {
+		if e.p.keyword(strings.Fields(k.name)...) {
+			return k
+		}
+	}
+	return nil
+}
+
+// pluralKind reads the plural name of a kind, e.g. TABLES, or returns nil if
+// none is next.
+func (e *execution) pluralKind() *kind {
+	for _, k := range kinds {
+		if e.p.keyword(strings.Fields(k.plural)...) {
+			return k
+		}
+	}
+	return nil
+}
+
+// path reads the name of an object of kind k, which has to be fully
+// qualified as the stand-in has no notion of a current database or schema.
+func (e *execution) path(k *kind) ([]string, error) {
+	path, err := e.p.qualifiedName(k.depth)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) < k.depth {
+		return nil, unsupported("unqualified object names")
+	}
+	return path, nil
+}
+
+func (e *execution) done(status string) (*result, error) {
+	if err := e.p.end(); err != nil {
+		return nil, err
+	}
+	return statusResult(status), nil
+}
+
+func title(k *kind) string {
+	return strings.ToUpper(k.name[:1]) + strings.ToLower(k.name[1:])
+}
+
+// modifiers are the keywords that can precede the kind in a CREATE statement.
+var modifiers = []string{"TRANSIENT", "TEMPORARY", "SECURE"}
+
+func (e *execution) create() (*result, error) {
+	p := e.p
+	replace := p.keyword("OR", "REPLACE")
+	o := &object{modifiers: map[string]bool{}, properties: map[string]string{}, text: e.statement}
+	for _, m := range modifiers {
+		if p.keyword(m) {
+			o.modifiers[m] = true
+		}
+	}
+	if o.kind = e.kind(); o.kind == nil {
+		return nil, p.unexpected()
+	}
+	ifNotExists := p.keyword("IF", "NOT", "EXISTS")
+	var err error
+	if o.path, err = e.path(o.kind); err != nil {
+		return nil, err
+	}
+	if o.kind == kindTable {
+		body, err := p.parenthesized()
+		if err != nil {
+			return nil, err
+		}
+		if o.columns, err = parseColumns(body); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.properties(o.properties); err != nil {
+		return nil, err
+	}
+	if o.kind == kindResourceMonitor && p.keyword("TRIGGERS") {
+		if o.triggers, err = p.triggers(); err != nil {
+			return nil, err
+		}
+	}
+	if (o.kind == kindView || o.kind == kindPipe) && p.keyword("AS") {
+		o.query = strings.TrimSpace(p.rest())
+	}
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+	switch o.kind {
+	case kindWarehouse:
+		if err := e.c.checkWarehouse(o.properties); err != nil {
+			return nil, err
+		}
+		o.suspended = isTrue(o.property("INITIALLY_SUSPENDED"))
+	case kindResourceMonitor:
+		if err := e.c.checkNotifyUsers(o.property("NOTIFY_USERS")); err != nil {
+			return nil, err
+		}
+	case kindFileFormat:
+		o.properties["TYPE"] = strings.ToUpper(propertyOr(o, "TYPE", "CSV"))
+		if err := checkFileFormat(o.property("TYPE"), o.properties); err != nil {
+			return nil, err
+		}
+	case kindStage:
+		if err := e.c.checkFormatName(o.property("FILE_FORMAT")); err != nil {
+			return nil, err
+		}
+	}
+	if ifNotExists && e.c.lookup(o.kind, o.path) != nil {
+		return statusResult(fmt.Sprintf("%s already exists, statement succeeded.", o.name())), nil
+	}
+	if err := e.c.add(o, e.cn.role, replace); err != nil {
+		return nil, err
+	}
+	return statusResult(fmt.Sprintf("%s %s successfully created.", title(o.kind), o.name())), nil
+}
+
+func (e *execution) alter() (*result, error) {
+	p := e.p
+	if p.keyword("SESSION") {
+		// Session parameters such as QUERY_TAG are accepted and ignored.
+		switch {
+		case p.keyword("SET"):
+			if err := p.properties(map[string]string{}); err != nil {
+				return nil, err
+			}
+		case p.keyword("UNSET"):
+			if _, err := p.keys(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, p.unexpected()
+		}
+		return e.done("Statement executed successfully.")
+	}
+	if p.keyword("ACCOUNT") {
+		return e.alterAccount()
+	}
+	k := e.kind()
+	if k == nil {
+		return nil, p.unexpected()
+	}
+	ifExists := p.keyword("IF", "EXISTS")
+	path, err := e.path(k)
+	if err != nil {
+		return nil, err
+	}
+	o, err := e.c.get(k, path)
+	if err != nil && !ifExists {
+		return nil, err
+	}
+	switch {
+	case p.keyword("RENAME", "TO"):
+		newPath, err := e.path(k)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.end(); err != nil {
+			return nil, err
+		}
+		if o == nil {
+			break
+		}
+		if err := e.c.rename(o, newPath); err != nil {
+			return nil, err
+		}
+	case k == kindResourceMonitor && !p.isKeyword("RENAME") && !p.isKeyword("UNSET"):
+		// SET is followed by NOTIFY_USERS and TRIGGERS, either of which
+		// can also come on its own.
+		properties := map[string]string{}
+		set := p.keyword("SET")
+		if err := p.properties(properties); err != nil {
+			return nil, err
+		}
+		var triggers []trigger
+		setTriggers := p.keyword("TRIGGERS")
+		if setTriggers {
+			if triggers, err = p.triggers(); err != nil {
+				return nil, err
+			}
+		}
+		if !set && len(properties) == 0 && !setTriggers {
+			return nil, p.unexpected()
+		}
+		if err := p.end(); err != nil {
+			return nil, err
+		}
+		if o == nil {
+			break
+		}
+		if err := e.c.checkNotifyUsers(properties["NOTIFY_USERS"]); err != nil {
+			return nil, err
+		}
+		setProperties(o.properties, properties)
+		if setTriggers {
+			o.triggers = triggers
+		}
+	case k == kindWarehouse && p.keyword("SUSPEND"):
+		if err := p.end(); err != nil {
+			return nil, err
+		}
+		if o == nil {
+			break
+		}
+		if o.suspended {
+			return nil, invalidValue(fmt.Sprintf("Invalid state. Warehouse '%s' cannot be suspended.", o.name()))
+		}
+		o.suspended = true
+	case k == kindWarehouse && p.keyword("RESUME"):
+		ifSuspended := p.keyword("IF", "SUSPENDED")
+		if err := p.end(); err != nil {
+			return nil, err
+		}
+		if o == nil {
+			break
+		}
+		if !o.suspended && !ifSuspended {
+			return nil, invalidValue(fmt.Sprintf("Invalid state. Warehouse '%s' cannot be resumed.", o.name()))
+		}
+		o.suspended = false
+	case p.keyword("SET"):
+		properties := map[string]string{}
+		secure := p.keyword("SECURE")
+		if err := p.properties(properties); err != nil {
+			return nil, err
+		}
+		if err := p.end(); err != nil {
+			return nil, err
+		}
+		if o == nil {
+			break
+		}
+		if k == kindWarehouse {
+			if err := e.c.checkWarehouse(properties); err != nil {
+				return nil, err
+			}
+		}
+		if k == kindFileFormat {
+			// The type of a file format cannot be changed.
+			if _, ok := properties["TYPE"]; ok {
+				return nil, invalidValue("invalid property 'TYPE' for 'ALTER FILE FORMAT'")
+			}
+			if err := checkFileFormat(o.property("TYPE"), properties); err != nil {
+				return nil, err
+			}
+		}
+		if secure {
+			o.modifiers["SECURE"] = true
+		}
+		setProperties(o.properties, properties)
+	case p.keyword("UNSET"):
+		keys, err := p.keys()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.end(); err != nil {
+			return nil, err
+		}
+		if o == nil {
+			break
+		}
+		for _, key := range keys {
+			if key == "SECURE" {
+				delete(o.modifiers, key)
+			}
+			delete(o.properties, key)
+		}
+	default:
+		return nil, p.unexpected()
+	}
+	return statusResult("Statement executed successfully."), nil
+}
+
+// setProperties sets the properties of an ALTER ... SET, setting one to NULL
+// resets it.
+func setProperties(properties map[string]string, set map[string]string) {
+	for key, value := range set {
+		if isNull(value) {
+			delete(properties, key)
+		} else {
+			properties[key] = value
+		}
+	}
+}
+
+// alterAccount executes ALTER ACCOUNT SET and UNSET, of the account
+// properties only RESOURCE_MONITOR has an effect.
+func (e *execution) alterAccount() (*result, error) {
+	p := e.p
+	switch {
+	case p.keyword("SET"):
+		properties := map[string]string{}
+		if err := p.properties(properties); err != nil {
+			return nil, err
+		}
+		if err := p.end(); err != nil {
+			return nil, err
+		}
+		if err := e.c.checkResourceMonitor(properties); err != nil {
+			return nil, err
+		}
+		setProperties(e.c.account, properties)
+	case p.keyword("UNSET"):
+		keys, err := p.keys()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.end(); err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			delete(e.c.account, key)
+		}
+	default:
+		return nil, p.unexpected()
+	}
+	return statusResult("Statement executed successfully."), nil
+}
+
+func (e *execution) drop() (*result, error) {
+	p := e.p
+	if p.keyword("ACCOUNT") {
+		return e.alterAccount()
+	}
+	k := e.kind()
+	if k == nil {
+		return nil, p.unexpected()
+	}
+	ifExists := p.keyword("IF", "EXISTS")
+	path, err := e.path(k)
+	if err != nil {
+		return nil, err
+	}
+	if !p.keyword("CASCADE") {
+		p.keyword("RESTRICT")
+	}
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+	o, err := e.c.get(k, path)
+	if err != nil {
+		if ifExists {
+			return statusResult("Drop statement executed successfully (" + displayPath(path) + " already dropped)."), nil
+		}
+		return nil, err
+	}
+	e.c.remove(o)
+	return statusResult(fmt.Sprintf("%s successfully dropped.", o.name())), nil
+}
+
+func (e *execution) show() (*result, error) {
+	p := e.p
+	// TERSE output has the same columns here.
+	p.keyword("TERSE")
+	if p.keyword("GRANTS") {
+		return e.showGrants()
+	}
+	if p.keyword("PARAMETERS") {
+		return e.showParameters()
+	}
+	k := e.pluralKind()
+	if k == nil {
+		return nil, p.unexpected()
+	}
+	pattern := ""
+	if p.keyword("LIKE") {
+		var err error
+		if pattern, err = p.stringLiteral(); err != nil {
+			return nil, err
+		}
+	}
+	var in []string
+	if p.keyword("IN") {
+		var err error
+		switch {
+		case p.keyword("ACCOUNT"):
+		case p.keyword("DATABASE"):
+			in, err = e.path(kindDatabase)
+		case p.keyword("SCHEMA"):
+			in, err = e.path(kindSchema)
+		default:
+			in, err = p.qualifiedName(2)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+	if len(in) >= k.depth {
+		return nil, invalidValue(fmt.Sprintf("%s cannot be listed in %s.", k.plural, displayPath(in)))
+	}
+	if len(in) > 0 {
+		container := kindDatabase
+		if len(in) == 2 {
+			container = kindSchema
+		}
+		if _, err := e.c.get(container, in); err != nil {
+			return nil, err
+		}
+	}
+	var rows []map[string]driver.Value
+	for _, o := range e.c.list(k, in) {
+		if pattern == "" || like(pattern, o.name()) {
+			rows = append(rows, k.row(e.c, o))
+		}
+	}
+	return project(k.columns, rows), nil
+}
+
+func (e *execution) showGrants() (*result, error) {
+	p := e.p
+	var match func(g *grant) bool
+	switch {
+	case p.keyword("ON"):
+		k := e.kind()
+		if k == nil {
+			return nil, p.unexpected()
+		}
+		path, err := e.path(k)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := e.c.get(k, path); err != nil {
+			return nil, err
+		}
+		match = func(g *grant) bool {
+			return g.on == k && samePath(g.path, path)
+		}
+	case p.keyword("TO"):
+		granteeType, grantee, err := e.grantee()
+		if err != nil {
+			return nil, err
+		}
+		match = func(g *grant) bool {
+			return g.granteeType == granteeType && g.grantee == grantee
+		}
+	default:
+		return nil, unsupported("SHOW GRANTS without ON or TO")
+	}
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+	var grants []*grant
+	for _, g := range e.c.grants {
+		if match(g) {
+			grants = append(grants, g)
+		}
+	}
+	return grantRows(grants), nil
+}
+
+// showParameters executes SHOW PARAMETERS [LIKE] IN <kind> <name>, the
+// parameters of sessions and the account are not supported.
+func (e *execution) showParameters() (*result, error) {
+	p := e.p
+	pattern := ""
+	if p.keyword("LIKE") {
+		var err error
+		if pattern, err = p.stringLiteral(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectKeyword("IN"); err != nil {
+		return nil, err
+	}
+	k := e.kind()
+	if k == nil || parameters[k] == nil {
+		return nil, unsupported("SHOW PARAMETERS without an object")
+	}
+	path, err := e.path(k)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+	o, err := e.c.get(k, path)
+	if err != nil {
+		return nil, err
+	}
+	return parameterRows(o, pattern), nil
+}
+
+func (e *execution) describe() (*result, error) {
+	p := e.p
+	k := e.kind()
+	if k == nil {
+		return nil, p.unexpected()
+	}
+	path, err := e.path(k)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+	o, err := e.c.get(k, path)
+	if err != nil {
+		return nil, err
+	}
+	switch k {
+	case kindTable:
+		return descTable(o), nil
+	case kindUser:
+		return descUser(o), nil
+	case kindStage:
+		return descStage(o), nil
+	case kindFileFormat:
+		return descFileFormat(o), nil
+	}
+	return nil, unsupported("DESC " + k.name)
+}
+
+// grantee reads ROLE or SHARE followed by its name, which has to exist.
+func (e *execution) grantee() (string, string, error) {
+	k := kindRole
+	if e.p.keyword("SHARE") {
+		k = kindShare
+	} else if err := e.p.expectKeyword("ROLE"); err != nil {
+		return "", "", err
+	}
+	path, err := e.path(k)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := e.c.get(k, path); err != nil {
+		return "", "", err
+	}
+	return k.name, path[0], nil
+}
+
+// grant executes GRANT if granting is set, REVOKE otherwise.
+func (e *execution) grant(granting bool) (*result, error) {
+	p := e.p
+	privileges, err := p.privileges()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("ON"); err != nil {
+		return nil, err
+	}
+	var objects []*object
+	switch {
+	case p.keyword("FUTURE"):
+		return nil, unsupported("future grants")
+	case p.keyword("ALL"):
+		k := e.pluralKind()
+		if k == nil {
+			return nil, p.unexpected()
+		}
+		if err := p.expectKeyword("IN", "SCHEMA"); err != nil {
+			return nil, err
+		}
+		schema, err := e.path(kindSchema)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := e.c.get(kindSchema, schema); err != nil {
+			return nil, err
+		}
+		objects = e.c.list(k, schema)
+	default:
+		k := e.kind()
+		named := k != nil
+		if !named {
+			// Tables and views can be granted on without naming their kind.
+			k = kindTable
+		}
+		path, err := e.path(k)
+		if err != nil {
+			return nil, err
+		}
+		if !named && e.c.lookup(kindTable, path) == nil && e.c.lookup(kindView, path) != nil {
+			k = kindView
+		}
+		o, err := e.c.get(k, path)
+		if err != nil {
+			return nil, err
+		}
+		objects = []*object{o}
+	}
+	if granting {
+		err = p.expectKeyword("TO")
+	} else {
+		err = p.expectKeyword("FROM")
+	}
+	if err != nil {
+		return nil, err
+	}
+	granteeType, grantee, err := e.grantee()
+	if err != nil {
+		return nil, err
+	}
+	grantOption := granting && p.keyword("WITH", "GRANT", "OPTION")
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+	for _, o := range objects {
+		for _, privilege := range privileges {
+			g := &grant{
+				privilege:   privilege,
+				on:          o.kind,
+				path:        o.path,
+				granteeType: granteeType,
+				grantee:     grantee,
+				grantOption: grantOption,
+				grantedBy:   e.cn.role,
+			}
+			if granting {
+				e.c.grant(g)
+			} else {
+				e.c.revoke(g)
+			}
+		}
+	}
+	return statusResult("Statement executed successfully."), nil
+}
+
+func (e *execution) use() (*result, error) {
+	p := e.p
+	k := e.kind()
+	if k == nil {
+		return nil, p.unexpected()
+	}
+	path, err := e.path(k)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+	if _, err := e.c.get(k, path); err != nil {
+		return nil, err
+	}
+	switch k {
+	case kindRole:
+		e.cn.role = path[0]
+	case kindWarehouse:
+		e.cn.warehouse = path[0]
+	case kindDatabase, kindSchema:
+		// Names are always fully qualified, there is nothing to keep.
+	default:
+		return nil, p.unexpected()
+	}
+	return statusResult("Statement executed successfully."), nil
+}
+
+// query executes a SELECT, either of context functions such as
+// CURRENT_ROLE() or of an information_schema view.
+func (e *execution) query() (*result, error) {
+	p := e.p
+	if p.i+1 < len(p.tokens) && p.tokens[p.i+1].kind == tokenPunct && p.tokens[p.i+1].text == "(" {
+		return e.selectFunctions()
+	}
+	var columns []string
+	if !p.punct("*") {
+		for {
+			column, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, column)
+			if !p.punct(",") {
+				break
+			}
+		}
+	}
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	from, err := p.qualifiedName(3)
+	if err != nil {
+		return nil, err
+	}
+	if len(from) != 3 || from[1] != "INFORMATION_SCHEMA" {
+		return nil, unsupported("queries other than of information_schema")
+	}
+	view, ok := infoSchemaViews[from[2]]
+	if !ok {
+		return nil, unsupported("information_schema." + from[2])
+	}
+	if columns == nil {
+		columns = view.columns
+	}
+	for _, column := range columns {
+		if !contains(view.columns, column) {
+			return nil, invalidValue(fmt.Sprintf("invalid identifier '%s'", column))
+		}
+	}
+	conditions := map[string]string{}
+	if p.keyword("WHERE") {
+		for {
+			column, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			if !contains(view.columns, column) {
+				return nil, invalidValue(fmt.Sprintf("invalid identifier '%s'", column))
+			}
+			if err := p.expectPunct("="); err != nil {
+				return nil, err
+			}
+			if conditions[column], err = p.stringLiteral(); err != nil {
+				return nil, err
+			}
+			if !p.keyword("AND") {
+				break
+			}
+		}
+	}
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+	if _, err := e.c.get(kindDatabase, from[:1]); err != nil {
+		return nil, err
+	}
+	var rows []map[string]driver.Value
+	for _, row := range view.rows(e.c, from[0]) {
+		matches := true
+		for column, value := range conditions {
+			if s, _ := row[column].(string); s != value {
+				matches = false
+			}
+		}
+		if matches {
+			rows = append(rows, row)
+		}
+	}
+	return project(columns, rows), nil
+}
+
+// selectFunctions executes a SELECT of context functions.
+func (e *execution) selectFunctions() (*result, error) {
+	p := e.p
+	r := &result{rows: [][]driver.Value{nil}}
+	for {
+		t := p.next()
+		if t.kind != tokenWord {
+			return nil, syntaxError(e.statement, t.start, "unexpected '"+t.text+"'")
+		}
+		if err := p.expectPunct("("); err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		var value driver.Value
+		switch name := strings.ToUpper(t.text); name {
+		case "CURRENT_ROLE":
+			value = e.cn.role
+		case "CURRENT_WAREHOUSE":
+			value = nullIfEmpty(e.cn.warehouse)
+		case "CURRENT_ACCOUNT":
+			value = "FAKE"
+		case "CURRENT_VERSION":
+			value = "0.0.0"
+		default:
+			return nil, unsupported(name)
+		}
+		r.columns = append(r.columns, strings.ToUpper(t.text)+"()")
+		r.rows[0] = append(r.rows[0], value)
+		if !p.punct(",") {
+			break
+		}
+	}
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// parseColumns parses the column definitions of CREATE TABLE.
+func parseColumns(body string) ([]column, error) {
+	p, err := newParser(body)
+	if err != nil {
+		return nil, err
+	}
+	var columns []column
+	for {
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		typ, err := p.dataType()
+		if err != nil {
+			return nil, err
+		}
+		col := column{name: name, typ: typ, nullable: true}
+	constraints:
+		for {
+			switch {
+			case p.keyword("NOT", "NULL"):
+				col.nullable = false
+			case p.keyword("NULL"):
+			case p.keyword("DEFAULT"):
+				col.def = p.expression()
+			default:
+				break constraints
+			}
+		}
+		columns = append(columns, col)
+		if !p.punct(",") {
+			return columns, p.end()
+		}
+	}
+}
+
+// parseOptions parses a parenthesized list of options such as the
+// CREDENTIALS of a stage, leaving out anything it cannot make sense of.
+func parseOptions(options string) map[string]string {
+	values := map[string]string{}
+	if p, err := newParser(options); err == nil {
+		p.properties(values)
+	}
+	return values
+}
+
+// triggers reads the trigger definitions following TRIGGERS, ON <threshold>
+// PERCENT DO <action>.
+func (p *parser) triggers() ([]trigger, error) {
+	var triggers []trigger
+	for p.keyword("ON") {
+		t := p.peek()
+		if t.kind != tokenNumber {
+			return nil, p.unexpected()
+		}
+		p.i++
+		threshold, err := strconv.Atoi(t.text)
+		if err != nil {
+			return nil, invalidValue(fmt.Sprintf("invalid threshold %s", t.text))
+		}
+		if err := p.expectKeyword("PERCENT", "DO"); err != nil {
+			return nil, err
+		}
+		var action string
+		for _, a := range []string{"SUSPEND_IMMEDIATE", "SUSPEND", "NOTIFY"} {
+			if p.keyword(a) {
+				action = a
+				break
+			}
+		}
+		if action == "" {
+			return nil, p.unexpected()
+		}
+		triggers = append(triggers, trigger{threshold: threshold, action: action})
+	}
+	return triggers, nil
+}
+
+// splitNames resolves the comma separated names in text, e.g. the
+// NOTIFY_USERS of a resource monitor.
+func splitNames(text string) ([]string, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	p, err := newParser(text)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for {
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.punct(",") {
+			return names, p.end()
+		}
+	}
+}
+
+// checkNotifyUsers fails unless the users in the NOTIFY_USERS of a resource
+// monitor exist.
+func (c *catalog) checkNotifyUsers(notifyUsers string) error {
+	users, err := splitNames(notifyUsers)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if _, err := c.get(kindUser, []string{u}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
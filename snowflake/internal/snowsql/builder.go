@@ -0,0 +1,301 @@
+package snowsql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+CreateBuilder builds a CREATE statement:
+
+	CREATE [modifiers] <object type> <name> [body] [properties] [AS <query>]
+
+Properties are rendered in the order they are set. Optional properties are
+left out when empty, so callers can set every attribute of a resource without
+checking for unset values first.
+*/
+type CreateBuilder struct {
+	objectType string
+	name       string
+	modifiers  []string
+	body       string
+	properties []string
+	as         string
+}
+
+// NewCreateBuilder returns a builder creating the object of objectType, e.g.
+// STAGE, named name. name is the rendered, possibly qualified, name of the
+// object, see Identifier.String and QualifiedName.
+func NewCreateBuilder(objectType string, name string) *CreateBuilder {
+	return &CreateBuilder{objectType: objectType, name: name}
+}
+
+// Modifier adds a modifier such as TRANSIENT or SECURE before the object type
+// if enabled.
+func (b *CreateBuilder) Modifier(modifier string, enabled bool) *CreateBuilder {
+	if enabled {
+		b.modifiers = append(b.modifiers, modifier)
+	}
+	return b
+}
+
+// Body sets a raw clause following the name, e.g. the column definitions of a
+// table.
+func (b *CreateBuilder) Body(body string) *CreateBuilder {
+	b.body = body
+	return b
+}
+
+// As sets the query of a view or the COPY statement of a pipe. It is rendered
+// on a line of its own, which is how Snowflake returns view definitions.
+func (b *CreateBuilder) As(query string) *CreateBuilder {
+	b.as = query
+	return b
+}
+
+// SetString sets key to the string literal value unless value is empty.
+func (b *CreateBuilder) SetString(key string, value string) *CreateBuilder {
+	if value != "" {
+		b.set(key, Literal(value))
+	}
+	return b
+}
+
+// SetRaw sets key to value as is unless value is empty. value must already be
+// safe to splice into a statement, e.g. a rendered identifier.
+func (b *CreateBuilder) SetRaw(key string, value string) *CreateBuilder {
+	if value != "" {
+		b.set(key, value)
+	}
+	return b
+}
+
+// SetOptions sets key to the parenthesized options, e.g. FILE_FORMAT =
+// (TYPE = CSV), unless options is empty.
+func (b *CreateBuilder) SetOptions(key string, options string) *CreateBuilder {
+	if options != "" {
+		b.set(key, "("+options+")")
+	}
+	return b
+}
+
+// SetInt sets key to value.
+func (b *CreateBuilder) SetInt(key string, value int) *CreateBuilder {
+	b.set(key, strconv.Itoa(value))
+	return b
+}
+
+// SetBool sets key to value.
+func (b *CreateBuilder) SetBool(key string, value bool) *CreateBuilder {
+	b.set(key, renderBool(value))
+	return b
+}
+
+// SetStringList sets key to the list of string literals values unless values
+// is empty.
+func (b *CreateBuilder) SetStringList(key string, values []string) *CreateBuilder {
+	if len(values) > 0 {
+		b.set(key, renderStringList(values))
+	}
+	return b
+}
+
+// Clause adds a clause that is not a KEY = value property, e.g. the TRIGGERS
+// of a resource monitor, after the properties set so far unless clause is
+// empty.
+func (b *CreateBuilder) Clause(clause string) *CreateBuilder {
+	if clause != "" {
+		b.properties = append(b.properties, clause)
+	}
+	return b
+}
+
+func (b *CreateBuilder) set(key string, value string) {
+	b.properties = append(b.properties, fmt.Sprintf("%s = %s", key, value))
+}
+
+// Statement renders the CREATE statement.
+func (b *CreateBuilder) Statement() string {
+	parts := []string{"CREATE"}
+	parts = append(parts, b.modifiers...)
+	parts = append(parts, b.objectType, b.name)
+	if b.body != "" {
+		parts = append(parts, b.body)
+	}
+	parts = append(parts, b.properties...)
+	statement := strings.Join(parts, " ")
+	if b.as != "" {
+		statement += " AS\n" + b.as
+	}
+	return statement
+}
+
+/*
+AlterBuilder builds the ALTER statements changing the properties of an object.
+Setting a property to an empty value unsets it, so the changes of a resource
+can be passed on without distinguishing between changed and removed values.
+Snowflake does not allow SET and UNSET in the same statement, so a builder
+renders up to two statements.
+*/
+type AlterBuilder struct {
+	objectType string
+	name       string
+	set        []string
+	unset      []string
+}
+
+// NewAlterBuilder returns a builder altering the object of objectType named
+// name, see NewCreateBuilder.
+func NewAlterBuilder(objectType string, name string) *AlterBuilder {
+	return &AlterBuilder{objectType: objectType, name: name}
+}
+
+// SetString sets key to the string literal value, or unsets key if value is
+// empty.
+func (b *AlterBuilder) SetString(key string, value string) *AlterBuilder {
+	if value == "" {
+		return b.Unset(key)
+	}
+	return b.setValue(key, Literal(value))
+}
+
+// SetRaw sets key to value as is, or unsets key if value is empty.
+func (b *AlterBuilder) SetRaw(key string, value string) *AlterBuilder {
+	if value == "" {
+		return b.Unset(key)
+	}
+	return b.setValue(key, value)
+}
+
+// SetOptions sets key to the parenthesized options, or unsets key if options
+// is empty.
+func (b *AlterBuilder) SetOptions(key string, options string) *AlterBuilder {
+	if options == "" {
+		return b.Unset(key)
+	}
+	return b.setValue(key, "("+options+")")
+}
+
+// SetInt sets key to value.
+func (b *AlterBuilder) SetInt(key string, value int) *AlterBuilder {
+	return b.setValue(key, strconv.Itoa(value))
+}
+
+// SetBool sets key to value.
+func (b *AlterBuilder) SetBool(key string, value bool) *AlterBuilder {
+	return b.setValue(key, renderBool(value))
+}
+
+// SetStringList sets key to the list of string literals values, or unsets key
+// if values is empty.
+func (b *AlterBuilder) SetStringList(key string, values []string) *AlterBuilder {
+	if len(values) == 0 {
+		return b.Unset(key)
+	}
+	return b.setValue(key, renderStringList(values))
+}
+
+// Unset resets key to its default.
+func (b *AlterBuilder) Unset(key string) *AlterBuilder {
+	b.unset = append(b.unset, key)
+	return b
+}
+
+func (b *AlterBuilder) setValue(key string, value string) *AlterBuilder {
+	b.set = append(b.set, fmt.Sprintf("%s = %s", key, value))
+	return b
+}
+
+// Statements renders the ALTER statements, none if nothing was set or unset.
+func (b *AlterBuilder) Statements() []string {
+	var statements []string
+	if len(b.set) > 0 {
+		statements = append(statements, fmt.Sprintf("ALTER %s %s SET %s", b.objectType, b.name, strings.Join(b.set, " ")))
+	}
+	if len(b.unset) > 0 {
+		statements = append(statements, fmt.Sprintf("ALTER %s %s UNSET %s", b.objectType, b.name, strings.Join(b.unset, ", ")))
+	}
+	return statements
+}
+
+// Clause renders the statement altering the object with a clause other than
+// SET, UNSET and RENAME TO, e.g. SUSPEND.
+func (b *AlterBuilder) Clause(clause string) string {
+	return fmt.Sprintf("ALTER %s %s %s", b.objectType, b.name, clause)
+}
+
+// Rename renders the statement renaming the object to newName.
+func (b *AlterBuilder) Rename(newName string) string {
+	return fmt.Sprintf("ALTER %s %s RENAME TO %s", b.objectType, b.name, newName)
+}
+
+// Drop renders the statement dropping the object of objectType named name,
+// see NewCreateBuilder.
+func Drop(objectType string, name string) string {
+	return fmt.Sprintf("DROP %s %s", objectType, name)
+}
+
+// Grant renders the statement granting privileges on an object to a grantee,
+// e.g. Grant([]string{"select"}, "ALL TABLES IN SCHEMA A.B", "ROLE R").
+// Privileges are keywords and rendered in upper case.
+func Grant(privileges []string, on string, to string) (string, error) {
+	p, err := renderPrivileges(privileges)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("GRANT %s ON %s TO %s", p, on, to), nil
+}
+
+// Revoke renders the statement revoking privileges, see Grant.
+func Revoke(privileges []string, on string, from string) (string, error) {
+	p, err := renderPrivileges(privileges)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("REVOKE %s ON %s FROM %s", p, on, from), nil
+}
+
+// rePrivilege matches a privilege in upper case, e.g. SELECT, IMPORTED
+// PRIVILEGES or CREATE_TABLE: words of letters and underscores separated by
+// single spaces, each starting with a letter.
+var rePrivilege = regexp.MustCompile(`^[A-Z][A-Z_]*( [A-Z][A-Z_]*)*$`)
+
+// ValidatePrivilege checks that privilege is a keyword. Unlike names and
+// values, keywords cannot be quoted or escaped, so anything else is rejected.
+func ValidatePrivilege(privilege string) error {
+	if !rePrivilege.MatchString(strings.ToUpper(privilege)) {
+		return fmt.Errorf("invalid privilege %q, privileges are keywords such as SELECT or IMPORTED PRIVILEGES", privilege)
+	}
+	return nil
+}
+
+func renderPrivileges(privileges []string) (string, error) {
+	if len(privileges) == 0 {
+		return "", fmt.Errorf("no privileges to grant or revoke")
+	}
+	upper := make([]string, len(privileges))
+	for i, p := range privileges {
+		if err := ValidatePrivilege(p); err != nil {
+			return "", err
+		}
+		upper[i] = strings.ToUpper(p)
+	}
+	return strings.Join(upper, ", "), nil
+}
+
+func renderBool(value bool) string {
+	if value {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func renderStringList(values []string) string {
+	literals := make([]string, len(values))
+	for i, v := range values {
+		literals[i] = Literal(v)
+	}
+	return "(" + strings.Join(literals, ", ") + ")"
+}
@@ -0,0 +1,249 @@
+package snowsql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCreateBuilder(t *testing.T) {
+	cases := []struct {
+		name string
+		b    *CreateBuilder
+		want string
+	}{
+		{
+			"empty",
+			NewCreateBuilder("DATABASE", "D"),
+			"CREATE DATABASE D",
+		},
+		{
+			"string",
+			NewCreateBuilder("DATABASE", "D").SetString("COMMENT", "it's"),
+			"CREATE DATABASE D COMMENT = 'it''s'",
+		},
+		{
+			"empty string",
+			NewCreateBuilder("DATABASE", "D").SetString("COMMENT", ""),
+			"CREATE DATABASE D",
+		},
+		{
+			"raw",
+			NewCreateBuilder("WAREHOUSE", "W").SetRaw("WAREHOUSE_SIZE", "XSMALL").SetRaw("RESOURCE_MONITOR", ""),
+			"CREATE WAREHOUSE W WAREHOUSE_SIZE = XSMALL",
+		},
+		{
+			"options",
+			NewCreateBuilder("STAGE", "D.S.S").SetOptions("FILE_FORMAT", "TYPE = CSV").SetOptions("CREDENTIALS", ""),
+			"CREATE STAGE D.S.S FILE_FORMAT = (TYPE = CSV)",
+		},
+		{
+			"int",
+			NewCreateBuilder("DATABASE", "D").SetInt("DATA_RETENTION_TIME_IN_DAYS", 0),
+			"CREATE DATABASE D DATA_RETENTION_TIME_IN_DAYS = 0",
+		},
+		{
+			"bool",
+			NewCreateBuilder("USER", "U").SetBool("MUST_CHANGE_PASSWORD", false).SetBool("DISABLED", true),
+			"CREATE USER U MUST_CHANGE_PASSWORD = FALSE DISABLED = TRUE",
+		},
+		{
+			"string list",
+			NewCreateBuilder("FILE FORMAT", "D.S.F").SetStringList("NULL_IF", []string{"", "NULL", `\N`, "a, b"}),
+			`CREATE FILE FORMAT D.S.F NULL_IF = ('', 'NULL', '\\N', 'a, b')`,
+		},
+		{
+			"empty string list",
+			NewCreateBuilder("FILE FORMAT", "D.S.F").SetStringList("NULL_IF", nil),
+			"CREATE FILE FORMAT D.S.F",
+		},
+		{
+			"modifiers",
+			NewCreateBuilder("VIEW", "D.S.V").Modifier("OR REPLACE", true).Modifier("SECURE", false).Modifier("RECURSIVE", true),
+			"CREATE OR REPLACE RECURSIVE VIEW D.S.V",
+		},
+		{
+			"body",
+			NewCreateBuilder("TABLE", "D.S.T").Body("(ID NUMBER(38,0))").SetString("COMMENT", "c"),
+			"CREATE TABLE D.S.T (ID NUMBER(38,0)) COMMENT = 'c'",
+		},
+		{
+			"clause",
+			NewCreateBuilder("RESOURCE MONITOR", "M").SetInt("CREDIT_QUOTA", 10).Clause("TRIGGERS ON 100 PERCENT DO SUSPEND").Clause("").SetString("COMMENT", "c"),
+			"CREATE RESOURCE MONITOR M CREDIT_QUOTA = 10 TRIGGERS ON 100 PERCENT DO SUSPEND COMMENT = 'c'",
+		},
+		{
+			"as",
+			NewCreateBuilder("VIEW", "D.S.V").Modifier("SECURE", true).SetString("COMMENT", "c").As("SELECT 1"),
+			"CREATE SECURE VIEW D.S.V COMMENT = 'c' AS\nSELECT 1",
+		},
+		{
+			"set order",
+			NewCreateBuilder("USER", "U").SetString("LOGIN_NAME", "L").SetInt("DAYS_TO_EXPIRY", 1).SetString("EMAIL", "E"),
+			"CREATE USER U LOGIN_NAME = 'L' DAYS_TO_EXPIRY = 1 EMAIL = 'E'",
+		},
+	}
+	for _, c := range cases {
+		if got := c.b.Statement(); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestAlterBuilder(t *testing.T) {
+	cases := []struct {
+		name string
+		b    *AlterBuilder
+		want []string
+	}{
+		{
+			"empty",
+			NewAlterBuilder("DATABASE", "D"),
+			nil,
+		},
+		{
+			"string",
+			NewAlterBuilder("DATABASE", "D").SetString("COMMENT", "it's"),
+			[]string{"ALTER DATABASE D SET COMMENT = 'it''s'"},
+		},
+		{
+			"empty string",
+			NewAlterBuilder("DATABASE", "D").SetString("COMMENT", ""),
+			[]string{"ALTER DATABASE D UNSET COMMENT"},
+		},
+		{
+			"raw",
+			NewAlterBuilder("WAREHOUSE", "W").SetRaw("WAREHOUSE_SIZE", "SMALL"),
+			[]string{"ALTER WAREHOUSE W SET WAREHOUSE_SIZE = SMALL"},
+		},
+		{
+			"empty raw",
+			NewAlterBuilder("WAREHOUSE", "W").SetRaw("RESOURCE_MONITOR", ""),
+			[]string{"ALTER WAREHOUSE W UNSET RESOURCE_MONITOR"},
+		},
+		{
+			"options",
+			NewAlterBuilder("STAGE", "D.S.S").SetOptions("FILE_FORMAT", "TYPE = JSON"),
+			[]string{"ALTER STAGE D.S.S SET FILE_FORMAT = (TYPE = JSON)"},
+		},
+		{
+			"empty options",
+			NewAlterBuilder("STAGE", "D.S.S").SetOptions("FILE_FORMAT", ""),
+			[]string{"ALTER STAGE D.S.S UNSET FILE_FORMAT"},
+		},
+		{
+			"int and bool",
+			NewAlterBuilder("WAREHOUSE", "W").SetInt("AUTO_SUSPEND", 0).SetBool("AUTO_RESUME", false),
+			[]string{"ALTER WAREHOUSE W SET AUTO_SUSPEND = 0 AUTO_RESUME = FALSE"},
+		},
+		{
+			"string list",
+			NewAlterBuilder("FILE FORMAT", "D.S.F").SetStringList("NULL_IF", []string{"NULL", "it's"}),
+			[]string{"ALTER FILE FORMAT D.S.F SET NULL_IF = ('NULL', 'it''s')"},
+		},
+		{
+			"empty string list",
+			NewAlterBuilder("FILE FORMAT", "D.S.F").SetStringList("NULL_IF", []string{}),
+			[]string{"ALTER FILE FORMAT D.S.F UNSET NULL_IF"},
+		},
+		{
+			"unset",
+			NewAlterBuilder("USER", "U").Unset("EMAIL").Unset("DEFAULT_ROLE"),
+			[]string{"ALTER USER U UNSET EMAIL, DEFAULT_ROLE"},
+		},
+		{
+			"set and unset",
+			NewAlterBuilder("USER", "U").SetString("EMAIL", "").SetString("LOGIN_NAME", "L").SetString("DEFAULT_ROLE", "").SetBool("DISABLED", true),
+			[]string{
+				"ALTER USER U SET LOGIN_NAME = 'L' DISABLED = TRUE",
+				"ALTER USER U UNSET EMAIL, DEFAULT_ROLE",
+			},
+		},
+	}
+	for _, c := range cases {
+		if got := c.b.Statements(); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestAlterBuilderClauses(t *testing.T) {
+	b := NewAlterBuilder("WAREHOUSE", `"w"`)
+	if got, want := b.Clause("SUSPEND"), `ALTER WAREHOUSE "w" SUSPEND`; got != want {
+		t.Errorf("Clause: got %q, want %q", got, want)
+	}
+	if got, want := b.Rename("W2"), `ALTER WAREHOUSE "w" RENAME TO W2`; got != want {
+		t.Errorf("Rename: got %q, want %q", got, want)
+	}
+	// Clauses are rendered on their own, not with what was set.
+	b.SetString("COMMENT", "c")
+	if got, want := b.Clause("RESUME"), `ALTER WAREHOUSE "w" RESUME`; got != want {
+		t.Errorf("Clause: got %q, want %q", got, want)
+	}
+}
+
+func TestDrop(t *testing.T) {
+	if got, want := Drop("FILE FORMAT", `D.S."f"`), `DROP FILE FORMAT D.S."f"`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestGrant(t *testing.T) {
+	cases := []struct {
+		privileges []string
+		want       string
+	}{
+		{[]string{"select"}, "GRANT SELECT ON TABLE D.S.T TO ROLE R"},
+		{[]string{"Select", "INSERT", "update"}, "GRANT SELECT, INSERT, UPDATE ON TABLE D.S.T TO ROLE R"},
+		{[]string{"imported privileges"}, "GRANT IMPORTED PRIVILEGES ON TABLE D.S.T TO ROLE R"},
+		{[]string{"IMPORTED_PRIVILEGES"}, "GRANT IMPORTED_PRIVILEGES ON TABLE D.S.T TO ROLE R"},
+		{[]string{"create_table", "USAGE"}, "GRANT CREATE_TABLE, USAGE ON TABLE D.S.T TO ROLE R"},
+		{[]string{"APPLY MASKING_POLICY"}, "GRANT APPLY MASKING_POLICY ON TABLE D.S.T TO ROLE R"},
+	}
+	for _, c := range cases {
+		got, err := Grant(c.privileges, "TABLE D.S.T", "ROLE R")
+		if err != nil {
+			t.Errorf("Grant(%q): %v", c.privileges, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("Grant(%q) = %s, want %s", c.privileges, got, c.want)
+		}
+	}
+
+	got, err := Revoke([]string{"select", "insert"}, "VIEW D.S.V", "SHARE S")
+	if want := "REVOKE SELECT, INSERT ON VIEW D.S.V FROM SHARE S"; err != nil || got != want {
+		t.Errorf("Revoke = %s, %v, want %s", got, err, want)
+	}
+}
+
+func TestGrantInvalidPrivileges(t *testing.T) {
+	invalid := [][]string{
+		nil,
+		{},
+		{""},
+		{" "},
+		{"SELECT", ""},
+		{" SELECT"},
+		{"SELECT "},
+		{"IMPORTED  PRIVILEGES"},
+		{"SELECT, INSERT"},
+		{"SELECT ON TABLE X TO ROLE ACCOUNTADMIN --"},
+		{"SELECT; DROP DATABASE D"},
+		{"SELECT\nON"},
+		{"_"},
+		{"_SELECT"},
+		{"IMPORTED _PRIVILEGES"},
+		{"SELECT-INSERT"},
+		{"'SELECT'"},
+		{`"SELECT"`},
+		{"SÉLECT"},
+	}
+	for _, privileges := range invalid {
+		if got, err := Grant(privileges, "TABLE D.S.T", "ROLE R"); err == nil {
+			t.Errorf("Grant(%q) = %s, want an error", privileges, got)
+		}
+		if got, err := Revoke(privileges, "TABLE D.S.T", "ROLE R"); err == nil {
+			t.Errorf("Revoke(%q) = %s, want an error", privileges, got)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Package snowsql renders identifiers and literals for the SQL statements the
+provider generates, so that names and values from configuration can never
+change the structure of a statement.
+*/
+package snowsql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxIdentifierLength is the maximum length of a Snowflake identifier.
+const maxIdentifierLength = 255
+
+var reUnquotedIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// reservedKeywords cannot be used as unquoted identifiers, see
+// https://docs.snowflake.net/manuals/sql-reference/reserved-keywords.html
+var reservedKeywords = map[string]bool{
+	"ALL": true, "ALTER": true, "AND": true, "ANY": true, "AS": true,
+	"BETWEEN": true, "BY": true, "CHECK": true, "COLUMN": true,
+	"CONNECT": true, "CONNECTION": true, "CONSTRAINT": true, "CREATE": true,
+	"CROSS": true, "CURRENT": true, "CURRENT_DATE": true,
+	"CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "CURRENT_USER": true,
+	"DATABASE": true, "DELETE": true, "DISTINCT": true, "DROP": true,
+	"ELSE": true, "EXISTS": true, "FALSE": true, "FOLLOWING": true,
+	"FOR": true, "FROM": true, "FULL": true, "GRANT": true, "GROUP": true,
+	"GSCLUSTER": true, "HAVING": true, "ILIKE": true, "IN": true,
+	"INCREMENT": true, "INNER": true, "INSERT": true, "INTERSECT": true,
+	"INTO": true, "IS": true, "ISSUE": true, "JOIN": true, "LATERAL": true,
+	"LEFT": true, "LIKE": true, "LOCALTIME": true, "LOCALTIMESTAMP": true,
+	"MINUS": true, "NATURAL": true, "NOT": true, "NULL": true, "OF": true,
+	"ON": true, "OR": true, "ORDER": true, "ORGANIZATION": true,
+	"QUALIFY": true, "REGEXP": true, "REVOKE": true, "RIGHT": true,
+	"RLIKE": true, "ROW": true, "ROWS": true, "SAMPLE": true, "SCHEMA": true,
+	"SELECT": true, "SET": true, "SOME": true, "START": true, "TABLE": true,
+	"TABLESAMPLE": true, "THEN": true, "TO": true, "TRIGGER": true,
+	"TRUE": true, "TRY_CAST": true, "UNION": true, "UNIQUE": true,
+	"UPDATE": true, "USING": true, "VALUES": true, "VIEW": true,
+	"WHEN": true, "WHENEVER": true, "WHERE": true, "WITH": true,
+}
+
+/*
+Identifier is the name of a Snowflake object. Unquoted identifiers are case
+insensitive and resolve to their upper case form, quoted identifiers are case
+sensitive and may contain any character, see
+https://docs.snowflake.net/manuals/sql-reference/identifiers-syntax.html
+*/
+type Identifier struct {
+	name   string
+	quoted bool
+}
+
+// NewIdentifier returns the unquoted, case insensitive, identifier name.
+func NewIdentifier(name string) Identifier {
+	return Identifier{name: name}
+}
+
+// NewQuotedIdentifier returns the quoted, case sensitive, identifier name.
+func NewQuotedIdentifier(name string) Identifier {
+	return Identifier{name: name, quoted: true}
+}
+
+// ParseIdentifier parses a single identifier as it would be written in a
+// statement, either bare or enclosed in double quotes.
+func ParseIdentifier(s string) (Identifier, error) {
+	ids, err := ParseQualifiedName(s)
+	if err != nil {
+		return Identifier{}, err
+	}
+	if len(ids) != 1 {
+		return Identifier{}, fmt.Errorf("%q is a qualified name, expected a single identifier", s)
+	}
+	return ids[0], nil
+}
+
+// Name returns the name as Snowflake stores it, which is what SHOW commands
+// and information_schema return.
+func (i Identifier) Name() string {
+	if i.quoted {
+		return i.name
+	}
+	return strings.ToUpper(i.name)
+}
+
+// Quoted reports whether the identifier is case sensitive.
+func (i Identifier) Quoted() bool {
+	return i.quoted
+}
+
+// Validate checks that the identifier can be used to name an object.
+func (i Identifier) Validate() error {
+	if i.name == "" {
+		return fmt.Errorf("identifier must not be empty")
+	}
+	if len(i.name) > maxIdentifierLength {
+		return fmt.Errorf("identifier %q is longer than %d characters", i.name, maxIdentifierLength)
+	}
+	if !i.quoted && !reUnquotedIdentifier.MatchString(i.name) {
+		return fmt.Errorf("unquoted identifier %q must start with a letter or underscore and contain only letters, digits, underscores and dollar signs", i.name)
+	}
+	return nil
+}
+
+// String renders the identifier for use in a statement. Names that resolve to
+// the same object whether quoted or not are rendered bare, everything else is
+// quoted, so the rendering is always safe to splice into a statement.
+func (i Identifier) String() string {
+	name := i.Name()
+	if reUnquotedIdentifier.MatchString(name) && name == strings.ToUpper(name) && !reservedKeywords[name] {
+		return name
+	}
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// QualifiedName renders the dot separated name of an object, e.g.
+// database.schema.table.
+func QualifiedName(ids ...Identifier) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = id.String()
+	}
+	return strings.Join(parts, ".")
+}
+
+// ParseQualifiedName splits a dot separated name into its identifiers,
+// respecting dots and escaped quotes within quoted identifiers.
+func ParseQualifiedName(s string) ([]Identifier, error) {
+	var ids []Identifier
+	for i := 0; ; {
+		var id Identifier
+		if i < len(s) && s[i] == '"' {
+			var name strings.Builder
+			j := i + 1
+			for {
+				if j >= len(s) {
+					return nil, fmt.Errorf("unterminated quoted identifier in %q", s)
+				}
+				if s[j] == '"' {
+					if j+1 < len(s) && s[j+1] == '"' {
+						name.WriteByte('"')
+						j += 2
+						continue
+					}
+					break
+				}
+				name.WriteByte(s[j])
+				j++
+			}
+			id = NewQuotedIdentifier(name.String())
+			i = j + 1
+		} else {
+			j := i
+			for j < len(s) && s[j] != '.' {
+				if s[j] == '"' {
+					return nil, fmt.Errorf("unexpected quote in %q", s)
+				}
+				j++
+			}
+			id = NewIdentifier(s[i:j])
+			i = j
+		}
+		if id.name == "" {
+			return nil, fmt.Errorf("empty identifier in %q", s)
+		}
+		ids = append(ids, id)
+		if i == len(s) {
+			return ids, nil
+		}
+		if s[i] != '.' {
+			return nil, fmt.Errorf("expected . after identifier in %q", s)
+		}
+		i++
+	}
+}
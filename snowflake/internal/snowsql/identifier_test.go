@@ -0,0 +1,136 @@
+package snowsql
+
+import (
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+func TestIdentifierString(t *testing.T) {
+	cases := []struct {
+		id   Identifier
+		want string
+	}{
+		{NewIdentifier("analyst"), "ANALYST"},
+		{NewIdentifier("My_Table$1"), "MY_TABLE$1"},
+		{NewIdentifier("table"), `"TABLE"`},
+		{NewQuotedIdentifier("ANALYST"), "ANALYST"},
+		{NewQuotedIdentifier("analyst"), `"analyst"`},
+		{NewQuotedIdentifier("TABLE"), `"TABLE"`},
+		{NewQuotedIdentifier("1ST"), `"1ST"`},
+		{NewQuotedIdentifier("a.b"), `"a.b"`},
+		{NewQuotedIdentifier("with space"), `"with space"`},
+		{NewQuotedIdentifier(`say "hi"`), `"say ""hi"""`},
+		{NewQuotedIdentifier(`"`), `""""`},
+		{NewQuotedIdentifier(`it's`), `"it's"`},
+		{NewQuotedIdentifier(`back\slash`), `"back\slash"`},
+		{NewQuotedIdentifier("100%_done"), `"100%_done"`},
+		{NewQuotedIdentifier("Grüße"), `"Grüße"`},
+		{NewQuotedIdentifier("日本"), `"日本"`},
+	}
+	for _, c := range cases {
+		if got := c.id.String(); got != c.want {
+			t.Errorf("%#v.String() = %s, want %s", c.id, got, c.want)
+		}
+	}
+}
+
+func TestParseQualifiedName(t *testing.T) {
+	cases := []struct {
+		s    string
+		want []Identifier
+	}{
+		{"a", []Identifier{NewIdentifier("a")}},
+		{"a.b.c", []Identifier{NewIdentifier("a"), NewIdentifier("b"), NewIdentifier("c")}},
+		{`"a.b".c`, []Identifier{NewQuotedIdentifier("a.b"), NewIdentifier("c")}},
+		{`"say ""hi""".""""`, []Identifier{NewQuotedIdentifier(`say "hi"`), NewQuotedIdentifier(`"`)}},
+		{`"it's".x`, []Identifier{NewQuotedIdentifier("it's"), NewIdentifier("x")}},
+		{`"日本"`, []Identifier{NewQuotedIdentifier("日本")}},
+	}
+	for _, c := range cases {
+		got, err := ParseQualifiedName(c.s)
+		if err != nil {
+			t.Errorf("ParseQualifiedName(%q): %v", c.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseQualifiedName(%q) = %#v, want %#v", c.s, got, c.want)
+		}
+	}
+
+	for _, s := range []string{"", ".", "a.", ".a", "a..b", `"`, `"a`, `a"b`, `"a"b`, `""`, `"a".""`} {
+		if ids, err := ParseQualifiedName(s); err == nil {
+			t.Errorf("ParseQualifiedName(%q) = %#v, want an error", s, ids)
+		}
+	}
+}
+
+func TestIdentifierValidate(t *testing.T) {
+	valid := []Identifier{
+		NewIdentifier("a"),
+		NewIdentifier("_a1$"),
+		NewQuotedIdentifier("any thing.at all"),
+		NewQuotedIdentifier(string(make([]byte, maxIdentifierLength))),
+	}
+	for _, id := range valid {
+		if err := id.Validate(); err != nil {
+			t.Errorf("%#v.Validate(): %v", id, err)
+		}
+	}
+	invalid := []Identifier{
+		NewIdentifier(""),
+		NewQuotedIdentifier(""),
+		NewIdentifier("1a"),
+		NewIdentifier("a-b"),
+		NewIdentifier("a b"),
+		NewIdentifier(`a"b`),
+		NewIdentifier("Grüße"),
+		NewQuotedIdentifier(string(make([]byte, maxIdentifierLength+1))),
+	}
+	for _, id := range invalid {
+		if err := id.Validate(); err == nil {
+			t.Errorf("%#v.Validate() succeeded, want an error", id)
+		}
+	}
+}
+
+// Any name, quoted, renders to an identifier that parses back to the name.
+func TestIdentifierRoundTrip(t *testing.T) {
+	roundTrip := func(names []string) bool {
+		var ids []Identifier
+		for _, name := range names {
+			if name != "" {
+				ids = append(ids, NewQuotedIdentifier(name))
+			}
+		}
+		if len(ids) == 0 {
+			return true
+		}
+		parsed, err := ParseQualifiedName(QualifiedName(ids...))
+		if err != nil || len(parsed) != len(ids) {
+			t.Logf("%q: %v", QualifiedName(ids...), err)
+			return false
+		}
+		for i := range ids {
+			if parsed[i].Name() != ids[i].Name() {
+				t.Logf("%q parses to %q, want %q", QualifiedName(ids...), parsed[i].Name(), ids[i].Name())
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	for _, names := range [][]string{
+		{`"`, `""`, `a"b`, `"a"`},
+		{"'", `\`, `\"`, "%", "_"},
+		{".", "a.b", "..", `"."`},
+		{"Grüße", "日本", "🎉", " "},
+		{"TABLE", "table", "ANALYST", "analyst", "1ST"},
+	} {
+		if !roundTrip(names) {
+			t.Errorf("%q do not round trip", names)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package snowsql
+
+import (
+	"strings"
+)
+
+var literalReplacer = strings.NewReplacer(`\`, `\\`, `'`, `''`)
+
+var likePatternReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Literal renders s as a single quoted string literal.
+func Literal(s string) string {
+	return "'" + literalReplacer.Replace(s) + "'"
+}
+
+// LikePattern renders a string literal for LIKE that matches s exactly, with
+// the % and _ wildcards escaped.
+func LikePattern(s string) string {
+	return Literal(likePatternReplacer.Replace(s))
+}
@@ -0,0 +1,157 @@
+package snowsql
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// unquoteLiteral parses a single quoted string literal the way Snowflake does
+// and fails if anything follows the closing quote.
+func unquoteLiteral(s string) (string, error) {
+	if !strings.HasPrefix(s, "'") {
+		return "", fmt.Errorf("%q does not start with a quote", s)
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 == len(s) {
+				return "", fmt.Errorf("%q ends in an escape", s)
+			}
+			i++
+			b.WriteByte(s[i])
+		case '\'':
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++
+				b.WriteByte('\'')
+				continue
+			}
+			if i+1 != len(s) {
+				return "", fmt.Errorf("%q continues after the closing quote: %s", s, s[i+1:])
+			}
+			return b.String(), nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", fmt.Errorf("%q is not terminated", s)
+}
+
+// unescapeLikePattern returns the string a LIKE pattern matches, or an error
+// if the pattern contains a wildcard and so matches more than one string.
+func unescapeLikePattern(p string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		switch p[i] {
+		case '\\':
+			if i+1 == len(p) {
+				return "", fmt.Errorf("%q ends in an escape", p)
+			}
+			i++
+			b.WriteByte(p[i])
+		case '%', '_':
+			return "", fmt.Errorf("%q contains the wildcard %c", p, p[i])
+		default:
+			b.WriteByte(p[i])
+		}
+	}
+	return b.String(), nil
+}
+
+var literalStrings = []string{
+	"",
+	"plain",
+	"it's",
+	"''",
+	`\`,
+	`\'`,
+	`'\`,
+	`ends in \`,
+	`"quoted"`,
+	"100%_done",
+	`\%\_`,
+	"'; DROP TABLE T; --",
+	"line\nbreak\ttab",
+	"Grüße 日本 🎉",
+}
+
+func TestLiteral(t *testing.T) {
+	cases := []struct {
+		s    string
+		want string
+	}{
+		{"", "''"},
+		{"it's", "'it''s'"},
+		{`a\b`, `'a\\b'`},
+		{`\'`, `'\\'''`},
+		{"100%_done", "'100%_done'"},
+		{"日本", "'日本'"},
+	}
+	for _, c := range cases {
+		if got := Literal(c.s); got != c.want {
+			t.Errorf("Literal(%q) = %s, want %s", c.s, got, c.want)
+		}
+	}
+}
+
+func TestLiteralRoundTrip(t *testing.T) {
+	roundTrip := func(s string) bool {
+		got, err := unquoteLiteral(Literal(s))
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		return got == s
+	}
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range literalStrings {
+		if !roundTrip(s) {
+			t.Errorf("%q does not round trip", s)
+		}
+	}
+}
+
+func TestLikePattern(t *testing.T) {
+	cases := []struct {
+		s    string
+		want string
+	}{
+		{"TABLE", "'TABLE'"},
+		{"MY_TABLE", `'MY\\_TABLE'`},
+		{"100%", `'100\\%'`},
+		{`a\b`, `'a\\\\b'`},
+		{"it's", "'it''s'"},
+	}
+	for _, c := range cases {
+		if got := LikePattern(c.s); got != c.want {
+			t.Errorf("LikePattern(%q) = %s, want %s", c.s, got, c.want)
+		}
+	}
+}
+
+// The pattern of any string matches that string only.
+func TestLikePatternRoundTrip(t *testing.T) {
+	roundTrip := func(s string) bool {
+		p, err := unquoteLiteral(LikePattern(s))
+		if err == nil {
+			p, err = unescapeLikePattern(p)
+		}
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		return p == s
+	}
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range literalStrings {
+		if !roundTrip(s) {
+			t.Errorf("%q does not round trip", s)
+		}
+	}
+}
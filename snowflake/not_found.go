@@ -0,0 +1,56 @@
+package snowflake
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/snowflakedb/gosnowflake"
+)
+
+// notFoundError is returned by the show, desc and read helpers when the object
+// does not exist.
+type notFoundError struct {
+	objectType string
+	name       string
+}
+
+func newNotFoundError(objectType string, names ...string) error {
+	return &notFoundError{objectType: objectType, name: strings.Join(names, ".")}
+}
+
+func (e *notFoundError) Error() string {
+	return fmt.Sprintf("%s %s does not exist", e.objectType, e.name)
+}
+
+/*
+isNotFound reports whether err says that an object does not exist, either
+because a helper found no such object or because Snowflake failed a statement
+on it. Snowflake uses the same error for objects the current role is not
+allowed to see, which is indistinguishable from a dropped object.
+*/
+func isNotFound(err error) bool {
+	switch e := originalError(err).(type) {
+	case *notFoundError:
+		return true
+	case *gosnowflake.SnowflakeError:
+		return e.Number == errCodeObjectNotFound
+	}
+	return false
+}
+
+/*
+removeIfNotFound handles err returned while reading the object of resource d.
+An object dropped outside of Terraform is drift rather than a failure: the
+resource is removed from the state so that the plan proposes to create it
+again, and nil is returned. Any other error is returned as is.
+*/
+func removeIfNotFound(d *schema.ResourceData, err error) error {
+	if !isNotFound(err) {
+		return err
+	}
+	log.Printf("[WARN] %v, removing %s from the state", err, d.Id())
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,111 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/snowflakedb/gosnowflake"
+)
+
+// An object dropped outside of Terraform is removed from the state on
+// refresh, and deleting it succeeds without dropping anything.
+func TestResourcesNotFound(t *testing.T) {
+	cases := map[string]struct {
+		id  string
+		raw map[string]interface{}
+	}{
+		"snowflake_database": {"D", map[string]interface{}{"name": "D"}},
+		"snowflake_schema":   {"D.S", map[string]interface{}{"database": "D", "name": "S"}},
+		"snowflake_table": {"D.S.T", map[string]interface{}{
+			"database": "D",
+			"schema":   "S",
+			"name":     "T",
+			"columns":  []interface{}{map[string]interface{}{"name": "ID", "type": "NUMBER"}},
+		}},
+		"snowflake_pipe": {"D.S.P", map[string]interface{}{
+			"database":       "D",
+			"schema":         "S",
+			"name":           "P",
+			"copy_statement": "COPY INTO D.S.T FROM @D.S.STAGE",
+		}},
+		"snowflake_sequence": {"D.S.Q", map[string]interface{}{"database": "D", "schema": "S", "name": "Q"}},
+		"snowflake_view": {"D.S.V", map[string]interface{}{
+			"database":        "D",
+			"schema":          "S",
+			"name":            "V",
+			"view_definition": "SELECT 1",
+		}},
+		"snowflake_user":        {"U", map[string]interface{}{"name": "U"}},
+		"snowflake_stage":       {"D.S.STAGE", map[string]interface{}{"database": "D", "schema": "S", "name": "STAGE"}},
+		"snowflake_file_format": {"D.S.F", map[string]interface{}{"database": "D", "schema": "S", "name": "F", "type": "CSV"}},
+		"snowflake_table_grant": {"R.D.S.T", map[string]interface{}{
+			"database":     "D",
+			"schema":       "S",
+			"table":        "T",
+			"privileges":   []interface{}{"SELECT"},
+			"grantee_role": "R",
+		}},
+		"snowflake_view_grant": {"R.D.S.V", map[string]interface{}{
+			"database":     "D",
+			"schema":       "S",
+			"view":         "V",
+			"privileges":   []interface{}{"SELECT"},
+			"grantee_role": "R",
+		}},
+		"snowflake_role":             {"R", map[string]interface{}{"name": "R"}},
+		"snowflake_warehouse":        {"W", map[string]interface{}{"name": "W"}},
+		"snowflake_resource_monitor": {"M", map[string]interface{}{"name": "M", "credit_quota": 10}},
+		"snowflake_warehouse_grant": {"W.USAGE", map[string]interface{}{
+			"warehouse_name": "W",
+			"privilege":      "USAGE",
+			"roles":          []interface{}{"R"},
+		}},
+	}
+	// Revoking from an object that was dropped fails with object not found.
+	revokes := map[string][]string{
+		"snowflake_table_grant":     {"REVOKE SELECT ON D.S.T FROM ROLE R"},
+		"snowflake_view_grant":      {"REVOKE SELECT ON D.S.V FROM ROLE R"},
+		"snowflake_warehouse_grant": {"REVOKE USAGE ON WAREHOUSE W FROM ROLE R"},
+	}
+
+	for name, r := range Provider().(*schema.Provider).ResourcesMap {
+		tc, ok := cases[name]
+		if !ok {
+			t.Errorf("%s: no test case", name)
+			continue
+		}
+		c := newFakeClient()
+		c.execErr = func(statement string) error {
+			return &gosnowflake.SnowflakeError{Number: errCodeObjectNotFound, Message: "Object does not exist"}
+		}
+		meta := testFakeMeta(c)
+		state := testFakeState(t, r, tc.id, tc.raw)
+
+		refreshed, err := r.Refresh(state, meta)
+		if err != nil {
+			t.Errorf("%s: refresh: %v", name, err)
+		} else if refreshed != nil {
+			t.Errorf("%s: refresh kept %s in the state", name, refreshed.ID)
+		}
+		testCheckStatements(t, c)
+
+		if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+			t.Errorf("%s: delete: %v", name, err)
+		}
+		testCheckStatements(t, c, revokes[name]...)
+	}
+}
+
+// A data source fails when the object does not exist, there is nothing to
+// remove from the state.
+func TestDataSourcesNotFound(t *testing.T) {
+	c := newFakeClient()
+	r := Provider().(*schema.Provider).DataSourcesMap["snowflake_schema"]
+	d := r.TestResourceData()
+	d.Set("database", "D")
+	d.Set("name", "S")
+	if err := r.Read(d, testFakeMeta(c)); !isNotFound(err) {
+		t.Fatalf("reading a missing schema returned %v, want a notFoundError", err)
+	}
+	testCheckStatements(t, c)
+}
@@ -0,0 +1,42 @@
+package snowflake
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// withOperations wraps the functions of r so the meta they are handed knows
+// the resource type and operation they run for, which is used to tag queries
+// and to fail changes in read-only mode.
+func withOperations(resourceType string, r *schema.Resource) {
+	r.Create = withOperation(resourceType, "create", r.Create)
+	r.Read = withOperation(resourceType, "read", r.Read)
+	r.Update = withOperation(resourceType, "update", r.Update)
+	r.Delete = withOperation(resourceType, "delete", r.Delete)
+	if r.Importer != nil && r.Importer.State != nil {
+		state := r.Importer.State
+		r.Importer.State = func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			return state(d, meta.(*providerMeta).forOperation(resourceType, "import"))
+		}
+	}
+}
+
+func withOperation(resourceType string, operation string, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	if f == nil {
+		return nil
+	}
+	return func(d *schema.ResourceData, meta interface{}) error {
+		m := meta.(*providerMeta)
+		if err := m.checkReadOnlyOperation(resourceType, operation, d.Id()); err != nil {
+			return err
+		}
+		return f(d, m.forOperation(resourceType, operation))
+	}
+}
+
+// forOperation returns a copy of the provider meta for a single operation.
+func (m *providerMeta) forOperation(resourceType string, operation string) *providerMeta {
+	c := *m
+	c.resourceType = resourceType
+	c.operation = operation
+	return &c
+}
@@ -2,57 +2,400 @@ package snowflake
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
-	// Snowflake SQL DB
-	_ "github.com/snowflakedb/gosnowflake"
+	"github.com/snowflakedb/gosnowflake"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 )
 
+// sqlDriverName is the database/sql driver the provider connects with. Tests
+// switch it to an in-process stand-in, see internal/fakesnowflake.
+var sqlDriverName = "snowflake"
+
+// connectionAttributes are the structured provider attributes that are
+// assembled into a gosnowflake.Config. They all conflict with dsn.
+var connectionAttributes = []string{
+	"account",
+	"user",
+	"password",
+	"region",
+	"host",
+	"port",
+	"protocol",
+	"database",
+	"schema",
+	"params",
+	"private_key",
+	"private_key_path",
+	"private_key_passphrase",
+	"authenticator",
+	"token",
+	"oauth_client_id",
+	"oauth_client_secret",
+	"oauth_refresh_token",
+	"oauth_endpoint",
+}
+
 // Provider returns a terraform.ResourceProvider.
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"dsn": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_DSN", nil),
+				ConflictsWith: connectionAttributes,
+			},
+			"account": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_ACCOUNT", nil),
+				ConflictsWith: []string{"dsn"},
+			},
+			"user": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_USER", nil),
+				ConflictsWith: []string{"dsn"},
+			},
+			"password": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_PASSWORD", nil),
+				ConflictsWith: []string{"dsn", "private_key", "private_key_path"},
+			},
+			"private_key": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_PRIVATE_KEY", nil),
+				ConflictsWith: []string{"dsn", "password", "private_key_path"},
+			},
+			"private_key_path": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_PRIVATE_KEY_PATH", nil),
+				ConflictsWith: []string{"dsn", "password", "private_key"},
+			},
+			"private_key_passphrase": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_PRIVATE_KEY_PASSPHRASE", nil),
+				ConflictsWith: []string{"dsn", "password"},
+			},
+			"authenticator": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_AUTHENTICATOR", nil),
+				ValidateFunc:  validation.StringInSlice([]string{"snowflake", "oauth"}, true),
+				ConflictsWith: []string{"dsn"},
+			},
+			"token": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_TOKEN", nil),
+				ConflictsWith: []string{"dsn", "password", "oauth_refresh_token"},
+			},
+			"oauth_client_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_OAUTH_CLIENT_ID", nil),
+				ConflictsWith: []string{"dsn", "token"},
+			},
+			"oauth_client_secret": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_OAUTH_CLIENT_SECRET", nil),
+				ConflictsWith: []string{"dsn", "token"},
+			},
+			"oauth_refresh_token": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_OAUTH_REFRESH_TOKEN", nil),
+				ConflictsWith: []string{"dsn", "password", "token"},
+			},
+			"oauth_endpoint": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_OAUTH_ENDPOINT", nil),
+				ConflictsWith: []string{"dsn", "token"},
+			},
+			"region": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_REGION", nil),
+				ConflictsWith: []string{"dsn"},
+			},
+			"host": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_HOST", nil),
+				ConflictsWith: []string{"dsn"},
+			},
+			"port": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_PORT", nil),
+				ConflictsWith: []string{"dsn"},
+			},
+			"protocol": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_PROTOCOL", nil),
+				ConflictsWith: []string{"dsn"},
+			},
+			"role": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("SNOWFLAKE_ROLE", nil),
+			},
+			"warehouse": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("SNOWFLAKE_WAREHOUSE", nil),
+			},
+			"database": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_DATABASE", nil),
+				ConflictsWith: []string{"dsn"},
+			},
+			"schema": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("SNOWFLAKE_SCHEMA", nil),
+				ConflictsWith: []string{"dsn"},
+			},
+			"params": &schema.Schema{
+				Type:          schema.TypeMap,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"dsn"},
+			},
+			"max_open_connections": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_idle_connections": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"connection_max_lifetime": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of seconds a connection may be reused, 0 reuses connections forever.",
+			},
+			"max_retries": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Number of times a statement failing with a transient error is retried.",
+			},
+			"retry_min_backoff": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Seconds to wait before the first retry, doubled on every further retry.",
+			},
+			"retry_max_backoff": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of seconds to wait between retries.",
+			},
+			"tag_queries": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Set QUERY_TAG to a JSON description of the resource operation issuing each statement.",
+			},
+			"query_tag_prefix": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("SNOWFLAKE_QUERY_TAG_PREFIX", nil),
+				Description: "Added to the query tag as prefix, for example to identify the Terraform run.",
+			},
+			"sql_log_path": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("SNOWFLAKE_DSN", nil),
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("SNOWFLAKE_SQL_LOG_PATH", nil),
+				Description: "File every executed statement is appended to, with secrets redacted.",
+			},
+			"dry_run": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Write the statements of creates, updates and deletes to dry_run_script_path instead of executing them.",
+			},
+			"dry_run_script_path": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "snowflake_dry_run.sql",
+				Description: "File the statements of a dry run are appended to.",
+			},
+			"read_only": &schema.Schema{
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ConflictsWith: []string{"dry_run"},
+				Description:   "Fail any operation or statement that could change Snowflake.",
 			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"snowflake_schema": dataSourceSnowflakeSchema(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"snowflake_database":    resourceSnowflakeDatabase(),
-			"snowflake_schema":      resourceSnowflakeSchema(),
-			"snowflake_table":       resourceSnowflakeTable(),
-			"snowflake_pipe":        resourceSnowflakePipe(),
-			"snowflake_view":        resourceSnowflakeView(),
-			"snowflake_user":        resourceSnowflakeUser(),
-			"snowflake_stage":       resourceSnowflakeStage(),
-			"snowflake_table_grant": resourceSnowflakeTableGrant(),
-			"snowflake_view_grant":  resourceSnowflakeViewGrant(),
-			"snowflake_role":        resourceSnowflakeRole(),
+			"snowflake_database":         resourceSnowflakeDatabase(),
+			"snowflake_schema":           resourceSnowflakeSchema(),
+			"snowflake_table":            resourceSnowflakeTable(),
+			"snowflake_pipe":             resourceSnowflakePipe(),
+			"snowflake_sequence":         resourceSnowflakeSequence(),
+			"snowflake_view":             resourceSnowflakeView(),
+			"snowflake_user":             resourceSnowflakeUser(),
+			"snowflake_stage":            resourceSnowflakeStage(),
+			"snowflake_file_format":      resourceSnowflakeFileFormat(),
+			"snowflake_table_grant":      resourceSnowflakeTableGrant(),
+			"snowflake_view_grant":       resourceSnowflakeViewGrant(),
+			"snowflake_role":             resourceSnowflakeRole(),
+			"snowflake_warehouse":        resourceSnowflakeWarehouse(),
+			"snowflake_resource_monitor": resourceSnowflakeResourceMonitor(),
+			"snowflake_warehouse_grant":  resourceSnowflakeWarehouseGrant(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
+	for name, r := range provider.DataSourcesMap {
+		withOperations("data."+name, r)
+	}
+	for name, r := range provider.ResourcesMap {
+		withOperations(name, r)
+	}
+	return provider
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	dsn := d.Get("dsn").(string)
-	log.Printf("dsn: %q", dsn)
-	db, err := sql.Open("snowflake", dsn)
+	dsn, err := providerDSN(d)
 	if err != nil {
 		return nil, err
 	}
-	db.SetMaxIdleConns(1)
-	db.SetMaxOpenConns(1)
+	log.Printf("dsn: %q", redactDSN(dsn))
+	db, err := sql.Open(sqlDriverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxIdleConns(d.Get("max_idle_connections").(int))
+	db.SetMaxOpenConns(d.Get("max_open_connections").(int))
+	db.SetConnMaxLifetime(time.Duration(d.Get("connection_max_lifetime").(int)) * time.Second)
 	err = db.Ping()
 	// _, err = db.Exec("select 1")
 	if err != nil {
 		return nil, err
 	}
-	return db, nil
+	m := &providerMeta{
+		db:        db,
+		role:      d.Get("role").(string),
+		warehouse: d.Get("warehouse").(string),
+		retry: retryPolicy{
+			maxRetries: d.Get("max_retries").(int),
+			minBackoff: time.Duration(d.Get("retry_min_backoff").(int)) * time.Second,
+			maxBackoff: time.Duration(d.Get("retry_max_backoff").(int)) * time.Second,
+		},
+		tagQueries:     d.Get("tag_queries").(bool),
+		queryTagPrefix: d.Get("query_tag_prefix").(string),
+		showCache:      newShowCache(),
+	}
+	if path, ok := d.GetOk("sql_log_path"); ok {
+		m.sqlLog, err = openSQLLog(path.(string))
+		if err != nil {
+			return nil, err
+		}
+	}
+	m.readOnly = d.Get("read_only").(bool)
+	if d.Get("dry_run").(bool) {
+		m.dryRun = true
+		m.dryRunScript, err = openSQLLog(d.Get("dry_run_script_path").(string))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := m.loadSessionDefaults(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// providerDSN returns the raw dsn if one was given, otherwise it assembles one
+// from the structured connection attributes.
+func providerDSN(d *schema.ResourceData) (string, error) {
+	if dsn, ok := d.GetOk("dsn"); ok {
+		return dsn.(string), nil
+	}
+	if _, ok := d.GetOk("account"); !ok {
+		return "", fmt.Errorf("One of dsn or account must be set")
+	}
+	cfg, err := providerConfig(d)
+	if err != nil {
+		return "", err
+	}
+	return gosnowflake.DSN(cfg)
+}
+
+// providerConfig maps the structured connection attributes onto a
+// gosnowflake.Config.
+func providerConfig(d *schema.ResourceData) (*gosnowflake.Config, error) {
+	cfg := &gosnowflake.Config{
+		Account:   d.Get("account").(string),
+		User:      d.Get("user").(string),
+		Password:  d.Get("password").(string),
+		Region:    d.Get("region").(string),
+		Host:      d.Get("host").(string),
+		Port:      d.Get("port").(int),
+		Protocol:  d.Get("protocol").(string),
+		Role:      d.Get("role").(string),
+		Warehouse: d.Get("warehouse").(string),
+		Database:  d.Get("database").(string),
+		Schema:    d.Get("schema").(string),
+	}
+	if params, ok := d.GetOk("params"); ok {
+		cfg.Params = map[string]*string{}
+		for k, v := range params.(map[string]interface{}) {
+			value := v.(string)
+			cfg.Params[k] = &value
+		}
+	}
+	privateKey, err := providerPrivateKey(d)
+	if err != nil {
+		return nil, err
+	}
+	if privateKey != nil {
+		cfg.Authenticator = gosnowflake.AuthTypeJwt
+		cfg.PrivateKey = privateKey
+	}
+	if strings.EqualFold(d.Get("authenticator").(string), "oauth") {
+		token, err := providerOAuthToken(d)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Authenticator = gosnowflake.AuthTypeOAuth
+		cfg.Token = token
+	}
+	return cfg, nil
 }
@@ -0,0 +1,313 @@
+package snowflake
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/fakesnowflake"
+	"github.com/snowflakedb/gosnowflake"
+)
+
+/*
+The acceptance tests run against the in-process stand-in of
+internal/fakesnowflake by default, so they are part of every go test run.
+Setting SNOWFLAKE_TEST_BACKEND=account runs them against the account the
+provider is configured for by the environment, e.g. SNOWFLAKE_ACCOUNT,
+SNOWFLAKE_USER and SNOWFLAKE_PASSWORD, which like any acceptance test also
+requires TF_ACC. The tests create objects with random names prefixed with
+TF_ACC_ and drop them again.
+*/
+const testAccBackendEnv = "SNOWFLAKE_TEST_BACKEND"
+
+// testAccFakeDSN is the DSN the provider is configured with for the stand-in,
+// which only looks at the role.
+const testAccFakeDSN = "tf_acc@fake/?role=SYSADMIN"
+
+var testAccProviders map[string]terraform.ResourceProvider
+var testAccProvider *schema.Provider
+
+func init() {
+	testAccProvider = Provider().(*schema.Provider)
+	testAccProviders = map[string]terraform.ResourceProvider{
+		"snowflake": testAccProvider,
+	}
+	if !testAccUseAccount() {
+		sqlDriverName = fakesnowflake.DriverName
+	}
+}
+
+func testAccUseAccount() bool {
+	return os.Getenv(testAccBackendEnv) == "account"
+}
+
+func TestProvider(t *testing.T) {
+	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestProviderDSN checks that a provider configured with only a DSN is
+// valid, none of the attributes that conflict with dsn may have a default.
+func TestProviderDSN(t *testing.T) {
+	defer testUnsetEnv()()
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"dsn": "user:password@account/?role=SYSADMIN",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, errs := Provider().Validate(terraform.NewResourceConfig(raw))
+	if len(errs) > 0 {
+		t.Fatalf("a configuration with only dsn is invalid: %v", errs)
+	}
+}
+
+// TestProviderConnection checks the DSN assembled from the structured
+// connection attributes and that they conflict with dsn.
+func TestProviderConnection(t *testing.T) {
+	defer testUnsetEnv()()
+	p := Provider().(*schema.Provider)
+
+	// An empty err means providerDSN returns a DSN for want.
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		want   gosnowflake.Config
+		err    string
+	}{
+		{
+			name: "password",
+			config: map[string]interface{}{
+				"account":   "xy12345",
+				"user":      "terraform",
+				"password":  "p@ss/word",
+				"region":    "eu-central-1",
+				"role":      "SYSADMIN",
+				"warehouse": "LOAD_WH",
+			},
+			want: gosnowflake.Config{
+				Account:   "xy12345",
+				User:      "terraform",
+				Password:  "p@ss/word",
+				Region:    "eu-central-1",
+				Host:      "xy12345.eu-central-1.snowflakecomputing.com",
+				Role:      "SYSADMIN",
+				Warehouse: "LOAD_WH",
+			},
+		},
+		{
+			name: "database and schema",
+			config: map[string]interface{}{
+				"account":  "xy12345.eu-central-1",
+				"user":     "terraform",
+				"password": "password",
+				"database": "ANALYTICS",
+				"schema":   "PUBLIC",
+			},
+			want: gosnowflake.Config{
+				Account:  "xy12345",
+				User:     "terraform",
+				Password: "password",
+				Region:   "eu-central-1",
+				Host:     "xy12345.eu-central-1.snowflakecomputing.com",
+				Database: "ANALYTICS",
+				Schema:   "PUBLIC",
+			},
+		},
+		{
+			name:   "nothing",
+			config: map[string]interface{}{"role": "SYSADMIN"},
+			err:    "One of dsn or account must be set",
+		},
+		{
+			name:   "no user",
+			config: map[string]interface{}{"account": "xy12345", "password": "password"},
+			err:    "user is empty",
+		},
+		{
+			name:   "invalid private key",
+			config: map[string]interface{}{"account": "xy12345", "user": "terraform", "private_key": "not a key"},
+			err:    "Could not decode private key",
+		},
+		{
+			name:   "oauth without token",
+			config: map[string]interface{}{"account": "xy12345", "user": "terraform", "authenticator": "oauth"},
+			err:    "One of token or oauth_refresh_token must be set",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dsn, err := providerDSN(schema.TestResourceDataRaw(t, p.Schema, c.config))
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("got error %v, want one containing %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := gosnowflake.ParseDSN(dsn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := gosnowflake.Config{
+				Account:   cfg.Account,
+				User:      cfg.User,
+				Password:  cfg.Password,
+				Region:    cfg.Region,
+				Host:      cfg.Host,
+				Role:      cfg.Role,
+				Warehouse: cfg.Warehouse,
+				Database:  cfg.Database,
+				Schema:    cfg.Schema,
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("DSN %q is for\n%+v\nwant\n%+v", dsn, got, c.want)
+			}
+		})
+	}
+
+	// A dsn is used as is, other than role and warehouse it conflicts with
+	// the connection attributes.
+	dsn := "user:password@account/?role=SYSADMIN"
+	got, err := providerDSN(schema.TestResourceDataRaw(t, p.Schema, map[string]interface{}{"dsn": dsn, "role": "R"}))
+	if err != nil || got != dsn {
+		t.Fatalf("providerDSN = %q, %v, want %q", got, err, dsn)
+	}
+	for _, k := range []string{"account", "user", "password", "region", "database", "schema"} {
+		raw, err := config.NewRawConfig(map[string]interface{}{"dsn": dsn, k: "x"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, errs := p.Validate(terraform.NewResourceConfig(raw)); len(errs) == 0 {
+			t.Errorf("a configuration with dsn and %s is valid", k)
+		}
+	}
+	raw, err := config.NewRawConfig(map[string]interface{}{"dsn": dsn, "role": "R", "warehouse": "W"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errs := p.Validate(terraform.NewResourceConfig(raw)); len(errs) > 0 {
+		t.Fatalf("a configuration with dsn, role and warehouse is invalid: %v", errs)
+	}
+}
+
+// testUnsetEnv unsets the SNOWFLAKE_ environment variables, which would fill
+// in the provider attributes, and returns a func restoring them.
+func testUnsetEnv() func() {
+	var env []string
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "SNOWFLAKE_") {
+			env = append(env, kv)
+			os.Unsetenv(strings.SplitN(kv, "=", 2)[0])
+		}
+	}
+	return func() {
+		for _, kv := range env {
+			kv := strings.SplitN(kv, "=", 2)
+			os.Setenv(kv[0], kv[1])
+		}
+	}
+}
+
+// testAccTest runs an acceptance test against the backend selected by
+// SNOWFLAKE_TEST_BACKEND.
+func testAccTest(t *testing.T, c resource.TestCase) {
+	c.Providers = testAccProviders
+	if testAccUseAccount() {
+		c.PreCheck = func() {
+			if os.Getenv("SNOWFLAKE_ACCOUNT") == "" && os.Getenv("SNOWFLAKE_DSN") == "" {
+				t.Fatal("SNOWFLAKE_ACCOUNT or SNOWFLAKE_DSN must be set for acceptance tests against an account")
+			}
+		}
+		resource.Test(t, c)
+		return
+	}
+	c.PreCheck = func() {
+		os.Setenv("SNOWFLAKE_DSN", testAccFakeDSN)
+	}
+	resource.UnitTest(t, c)
+}
+
+// testAccName returns a random name for an object created by a test.
+func testAccName() string {
+	return "TF_ACC_" + strings.ToUpper(acctest.RandString(10))
+}
+
+// testAccClient opens a Client with the configuration of the provider under
+// test, to look at objects and change them behind Terraform's back.
+func testAccClient() (Client, error) {
+	meta, ok := testAccProvider.Meta().(*providerMeta)
+	if !ok {
+		return nil, fmt.Errorf("The provider is not configured")
+	}
+	return newClient(meta, resourceSnowflakeRole().TestResourceData())
+}
+
+func testAccWithClient(f func(c Client) error) error {
+	c, err := testAccClient()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return f(c)
+}
+
+// testAccShowFunc looks up the object of a resource by ID and returns a
+// notFoundError if it does not exist.
+type testAccShowFunc func(c Client, id string) error
+
+// testAccCheckExists checks that the object of the resource name in the state
+// exists.
+func testAccCheckExists(name string, show testAccShowFunc) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s is not in the state", name)
+		}
+		return testAccWithClient(func(c Client) error {
+			return show(c, rs.Primary.ID)
+		})
+	}
+}
+
+// testAccCheckDestroyed checks that the objects of all resources of
+// resourceType in the state are gone.
+func testAccCheckDestroyed(resourceType string, show testAccShowFunc) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		return testAccWithClient(func(c Client) error {
+			for _, rs := range s.RootModule().Resources {
+				if rs.Type != resourceType {
+					continue
+				}
+				err := show(c, rs.Primary.ID)
+				if err == nil {
+					return fmt.Errorf("%s %s still exists", resourceType, rs.Primary.ID)
+				}
+				if !isNotFound(err) {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// testAccExec executes statements outside of Terraform, e.g. to drop an
+// object Terraform manages.
+func testAccExec(statements ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		return testAccWithClient(func(c Client) error {
+			return c.ExecAll(statements)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
+)
+
+// Version is the provider version reported in query tags.
+var Version = "dev"
+
+// queryTag is the QUERY_TAG set on every session when tag_queries is enabled,
+// it lets QUERY_HISTORY be traced back to the resource that issued a
+// statement.
+type queryTag struct {
+	Prefix          string `json:"prefix,omitempty"`
+	ResourceType    string `json:"resource_type"`
+	ResourceID      string `json:"resource_id,omitempty"`
+	Operation       string `json:"operation"`
+	ProviderVersion string `json:"provider_version"`
+}
+
+// queryTagStatement returns the ALTER SESSION statement tagging the queries of
+// the operation on d.
+func (m *providerMeta) queryTagStatement(d *schema.ResourceData) (string, error) {
+	tag, err := json.Marshal(queryTag{
+		Prefix:          m.queryTagPrefix,
+		ResourceType:    m.resourceType,
+		ResourceID:      d.Id(),
+		Operation:       m.operation,
+		ProviderVersion: Version,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ALTER SESSION SET QUERY_TAG = %s", snowsql.Literal(string(tag))), nil
+}
@@ -0,0 +1,79 @@
+package snowflake
+
+import "testing"
+
+func TestQueryTagStatement(t *testing.T) {
+	defer func(v string) { Version = v }(Version)
+	Version = "1.0.0"
+
+	cases := []struct {
+		name      string
+		prefix    string
+		operation string
+		id        string
+		want      string
+	}{
+		{
+			name:      "create",
+			operation: "create",
+			want:      `ALTER SESSION SET QUERY_TAG = '{"resource_type":"snowflake_table","operation":"create","provider_version":"1.0.0"}'`,
+		},
+		{
+			name:      "prefix",
+			prefix:    "ci-run-1234",
+			operation: "read",
+			id:        "D.S.T",
+			want:      `ALTER SESSION SET QUERY_TAG = '{"prefix":"ci-run-1234","resource_type":"snowflake_table","resource_id":"D.S.T","operation":"read","provider_version":"1.0.0"}'`,
+		},
+		{
+			// JSON escapes the double quotes, the literal the single quote
+			// and the backslashes JSON added.
+			name:      "quoted name",
+			operation: "delete",
+			id:        `D.S."it's ""T"""`,
+			want:      `ALTER SESSION SET QUERY_TAG = '{"resource_type":"snowflake_table","resource_id":"D.S.\\"it''s \\"\\"T\\"\\"\\"","operation":"delete","provider_version":"1.0.0"}'`,
+		},
+		{
+			name:      "prefix to escape",
+			prefix:    `team's "etl" \ <run>`,
+			operation: "update",
+			id:        "D.S.T",
+			want:      `ALTER SESSION SET QUERY_TAG = '{"prefix":"team''s \\"etl\\" \\\\ \\u003crun\\u003e","resource_type":"snowflake_table","resource_id":"D.S.T","operation":"update","provider_version":"1.0.0"}'`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &providerMeta{queryTagPrefix: c.prefix, resourceType: "snowflake_table", operation: c.operation}
+			d := resourceSnowflakeTable().TestResourceData()
+			d.SetId(c.id)
+			statement, err := m.queryTagStatement(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if statement != c.want {
+				t.Fatalf("queryTagStatement =\n%s\nwant\n%s", statement, c.want)
+			}
+		})
+	}
+}
+
+// TestSessionQueryTag checks that sessions only tag their queries with
+// tag_queries enabled.
+func TestSessionQueryTag(t *testing.T) {
+	defer func(v string) { Version = v }(Version)
+	Version = "1.0.0"
+
+	r := newRecordingDriver()
+	m := testSessionMeta(r)
+	for _, tagQueries := range []bool{false, true} {
+		m.tagQueries = tagQueries
+		s, err := newSession(m, resourceSnowflakeDatabase().TestResourceData())
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Close()
+	}
+	testCheckCalls(t, r,
+		`1 exec ALTER SESSION SET QUERY_TAG = '{"resource_type":"snowflake_database","operation":"create","provider_version":"1.0.0"}'`,
+	)
+}
@@ -0,0 +1,49 @@
+package snowflake
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// readOnlyOperations are the resource operations allowed in read-only mode.
+var readOnlyOperations = map[string]bool{
+	"read":   true,
+	"import": true,
+}
+
+// reReadOnlyStatement matches the statements allowed in read-only mode,
+// leading whitespace and comments aside.
+var reReadOnlyStatement = regexp.MustCompile(`(?i)^(SHOW|DESC|DESCRIBE|SELECT|WITH)\b`)
+
+var reLeadingComments = regexp.MustCompile(`^(\s+|--[^\n]*(\n|$)|/\*(?s:.*?)\*/)+`)
+
+// reSystemFunction matches calls of system functions, some of which change
+// Snowflake although they are called with SELECT, e.g.
+// SELECT SYSTEM$PIPE_FORCE_RESUME('...') or SYSTEM$ABORT_SESSION.
+var reSystemFunction = regexp.MustCompile(`(?i)\bSYSTEM\$`)
+
+// checkReadOnlyOperation fails operations that would change Snowflake when
+// the provider is in read-only mode.
+func (m *providerMeta) checkReadOnlyOperation(resourceType string, operation string, id string) error {
+	if !m.readOnly || readOnlyOperations[operation] {
+		return nil
+	}
+	if id == "" {
+		return fmt.Errorf("Cannot %s %s, the provider is in read-only mode", operation, resourceType)
+	}
+	return fmt.Errorf("Cannot %s %s.%s, the provider is in read-only mode", operation, resourceType, id)
+}
+
+// checkReadOnlyStatement enforces the read-only statement allowlist. Checking
+// operations should already prevent any other statement from being issued,
+// this is the last line of defense.
+func (s *session) checkReadOnlyStatement(statement string) error {
+	if !s.meta.readOnly {
+		return nil
+	}
+	if reReadOnlyStatement.MatchString(reLeadingComments.ReplaceAllString(statement, "")) && !reSystemFunction.MatchString(statement) {
+		return nil
+	}
+	return fmt.Errorf("%s: refusing to execute %q, the provider is in read-only mode", s.address(), redactSQL(strings.TrimSpace(statement)))
+}
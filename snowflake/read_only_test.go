@@ -0,0 +1,48 @@
+package snowflake
+
+import "testing"
+
+func TestCheckReadOnlyStatement(t *testing.T) {
+	s := &session{
+		meta: &providerMeta{readOnly: true, resourceType: "snowflake_pipe", operation: "read"},
+		d:    resourceSnowflakePipe().TestResourceData(),
+	}
+	cases := []struct {
+		statement string
+		allowed   bool
+	}{
+		{"SHOW PIPES IN SCHEMA D.S", true},
+		{"desc user U", true},
+		{"DESCRIBE FILE FORMAT D.S.F", true},
+		{"SELECT CURRENT_ROLE(), CURRENT_WAREHOUSE()", true},
+		{"WITH T AS (SELECT 1) SELECT * FROM T", true},
+		{"  -- comment\n/* another */ SHOW GRANTS ON WAREHOUSE W", true},
+		{"SELECT 'SYSTEM' FROM T", true},
+
+		{"CREATE DATABASE D", false},
+		{"DROP PIPE D.S.P", false},
+		{"-- SELECT\nDROP PIPE D.S.P", false},
+		{"/* SHOW */ ALTER PIPE D.S.P SET COMMENT = 'c'", false},
+		{"GRANT USAGE ON WAREHOUSE W TO ROLE R", false},
+		{"CALL P()", false},
+		{"SHOWX", false},
+
+		// System functions can change Snowflake although they are selected.
+		{"SELECT SYSTEM$PIPE_FORCE_RESUME('D.S.P')", false},
+		{"select system$abort_session(1065153872298)", false},
+		{"SELECT 1, SYSTEM$ABORT_TRANSACTION(1)", false},
+		{"WITH T AS (SELECT SYSTEM$CANCEL_ALL_QUERIES(1)) SELECT * FROM T", false},
+	}
+	for _, c := range cases {
+		err := s.checkReadOnlyStatement(c.statement)
+		if allowed := err == nil; allowed != c.allowed {
+			t.Errorf("checkReadOnlyStatement(%q) = %v, want allowed %t", c.statement, err, c.allowed)
+		}
+	}
+
+	// Outside of read-only mode everything goes.
+	s.meta.readOnly = false
+	if err := s.checkReadOnlyStatement("SELECT SYSTEM$PIPE_FORCE_RESUME('D.S.P')"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,84 @@
+package snowflake
+
+import (
+	"regexp"
+)
+
+const redacted = "****"
+
+// A single quoted string literal, allowing for backslash and doubled quote
+// escapes.
+const stringLiteralPattern = `'(?:[^'\\]|\\.|'')*'`
+
+var (
+	// CREDENTIALS = (...) and ENCRYPTION = (...) of stages
+	reParenthesizedSecret = regexp.MustCompile(`(?is)\b((?:credentials|encryption)\s*=\s*)\((?:[^()']|` + stringLiteralPattern + `)*\)`)
+	// PASSWORD = '...', RSA_PUBLIC_KEY = '...' and friends of users
+	reQuotedSecret = regexp.MustCompile(`(?is)\b((?:password|rsa_public_key(?:_2)?|master_key|aws_secret_key|aws_token|azure_sas_token)\s*=\s*)` + stringLiteralPattern)
+	rePrivateKey   = regexp.MustCompile(`(?s)-----BEGIN ([A-Z ]*)PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)
+	// user:password@ and secret query parameters of a DSN
+	reDSNPassword = regexp.MustCompile(`^([^:@/]*):[^?]*@`)
+	reDSNParam    = regexp.MustCompile(`(?i)([?&](?:password|passcode|token|privatekey|oauthclientsecret)=)[^&]*`)
+)
+
+/*
+redactSQL masks the secrets a statement issued by the provider can contain:
+stage credentials and encryption keys, user passwords and public keys and PEM
+encoded private keys. Everything the provider logs or returns as an error goes
+through it.
+*/
+func redactSQL(s string) string {
+	s = reParenthesizedSecret.ReplaceAllString(s, "${1}("+redacted+")")
+	s = reQuotedSecret.ReplaceAllString(s, "${1}'"+redacted+"'")
+	s = rePrivateKey.ReplaceAllString(s, "-----BEGIN ${1}PRIVATE KEY-----"+redacted+"-----END ${1}PRIVATE KEY-----")
+	return s
+}
+
+// redactDSN masks the password and secret parameters of a gosnowflake DSN.
+func redactDSN(dsn string) string {
+	dsn = reDSNPassword.ReplaceAllString(dsn, "${1}:"+redacted+"@")
+	return reDSNParam.ReplaceAllString(dsn, "${1}"+redacted)
+}
+
+// redactError returns err with its message redacted. Errors that do not
+// contain secrets are returned unchanged.
+func redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	message := err.Error()
+	if r := redactSQL(message); r != message {
+		return &redactedError{err: err, message: r}
+	}
+	return err
+}
+
+// redactedError is an error whose message contained secrets. The original
+// error is kept so that callers can still tell what went wrong, see
+// originalError, but only the redacted message is displayed.
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the original error, which must not be displayed.
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// Cause is Unwrap for github.com/pkg/errors.
+func (e *redactedError) Cause() error {
+	return e.err
+}
+
+// originalError returns the error err was redacted from, or err itself.
+func originalError(err error) error {
+	if e, ok := err.(*redactedError); ok {
+		return e.err
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package snowflake
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/snowflakedb/gosnowflake"
+)
+
+func TestRedactError(t *testing.T) {
+	if redactError(nil) != nil {
+		t.Fatal("redactError(nil) is not nil")
+	}
+
+	// Errors without secrets are returned as is.
+	err := &gosnowflake.SnowflakeError{Number: errCodeObjectNotFound, Message: "Object does not exist"}
+	if redactError(err) != error(err) {
+		t.Fatalf("redactError changed an error without secrets: %v", redactError(err))
+	}
+
+	// Errors with secrets keep the original error, but do not display it.
+	cases := []struct {
+		name      string
+		err       error
+		notFound  bool
+		retryable bool
+	}{
+		{
+			name:     "not found",
+			err:      &gosnowflake.SnowflakeError{Number: errCodeObjectNotFound, Message: "Object does not exist: ALTER USER U SET PASSWORD = 'hunter2'"},
+			notFound: true,
+		},
+		{
+			name:      "locked",
+			err:       &gosnowflake.SnowflakeError{Number: errCodeObjectLocked, Message: "Locked: ALTER USER U SET PASSWORD = 'hunter2'"},
+			retryable: true,
+		},
+		{
+			name: "other",
+			err:  errors.New("failed: CREATE STAGE S CREDENTIALS = (AWS_KEY_ID = 'id' AWS_SECRET_KEY = 'hunter2')"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := redactError(c.err)
+			if strings.Contains(r.Error(), "hunter2") {
+				t.Fatalf("error is not redacted: %v", r)
+			}
+			if !strings.Contains(r.Error(), redacted) {
+				t.Fatalf("error does not say it is redacted: %v", r)
+			}
+			if originalError(r) != c.err {
+				t.Fatalf("originalError = %v, want %v", originalError(r), c.err)
+			}
+			if u := r.(interface{ Unwrap() error }).Unwrap(); u != c.err {
+				t.Fatalf("Unwrap = %v, want %v", u, c.err)
+			}
+			if isNotFound(r) != c.notFound {
+				t.Fatalf("isNotFound = %t, want %t", isNotFound(r), c.notFound)
+			}
+			if isRetryableError("ALTER USER U SET COMMENT = 'c'", r) != c.retryable {
+				t.Fatalf("isRetryableError = %t, want %t", !c.retryable, c.retryable)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
+)
+
+/*
+Resource IDs are the dot separated canonical names of the object, see
+canonicalIdentifier, e.g. ANALYTICS.PUBLIC."events.raw" for a table. Quoting
+is what makes the format unambiguous: a name containing a dot or a double quote
+is always rendered in double quotes, with embedded double quotes doubled, just
+like in a statement. The same format is accepted by terraform import.
+
+Version 0 of the state held the dot joined upper case names as they were
+configured and, for grants, the privileges as additional parts, see
+upgradeIDV0.
+*/
+const idSchemaVersion = 1
+
+// identifierAttributes are the attributes naming objects, which hold the
+// canonical form of the name since version 1 of the state.
+var identifierAttributes = []string{"name", "database", "schema", "table", "view", "grantee_role", "grantee_share"}
+
+// encodeID returns the ID of the object with the given names.
+func encodeID(names ...string) string {
+	return qualifiedName(names...)
+}
+
+// decodeID splits id into its names, which are returned in canonical form.
+// format names the parts the ID has to consist of and is used in errors.
+func decodeID(id string, format ...string) ([]string, error) {
+	ids, err := snowsql.ParseQualifiedName(id)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid ID %q, expected %s: %v", id, strings.Join(format, "."), err)
+	}
+	if len(ids) != len(format) {
+		return nil, fmt.Errorf("Invalid ID %q, expected %s", id, strings.Join(format, "."))
+	}
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = id.String()
+	}
+	return names, nil
+}
+
+// importStateID returns the importer State function of resources whose ID
+// consists of format, it validates the ID and brings it into canonical form.
+func importStateID(format ...string) schema.StateFunc {
+	return func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		names, err := decodeID(d.Id(), format...)
+		if err != nil {
+			return nil, err
+		}
+		d.SetId(encodeID(names...))
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+// v1Attributes are the attributes added to the resources in version 1 of the
+// state, which version 0 of the state does not hold.
+var v1Attributes = []string{"execution_role", "file_format_name"}
+
+// idStateUpgraders returns the StateUpgraders of resource r whose ID consists
+// of format. Other than the ID and the names, version 0 of the state has the
+// attributes of r less v1Attributes.
+func idStateUpgraders(r *schema.Resource, format ...string) []schema.StateUpgrader {
+	v0 := &schema.Resource{Schema: map[string]*schema.Schema{}}
+	for k, s := range r.Schema {
+		v0.Schema[k] = s
+	}
+	for _, k := range v1Attributes {
+		delete(v0.Schema, k)
+	}
+	return []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    v0.CoreConfigSchema().ImpliedType(),
+			Upgrade: upgradeIDV0(format...),
+		},
+	}
+}
+
+// upgradeIDV0 migrates state from version 0, it drops the privileges from
+// grant IDs and brings the ID and the names into canonical form.
+func upgradeIDV0(format ...string) schema.StateUpgradeFunc {
+	return func(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		id, _ := rawState["id"].(string)
+		ids, err := snowsql.ParseQualifiedName(id)
+		if err != nil || len(ids) < len(format) {
+			return nil, fmt.Errorf("Cannot migrate ID %q, expected %s", id, strings.Join(format, "."))
+		}
+		rawState["id"] = snowsql.QualifiedName(ids[:len(format)]...)
+		for _, k := range identifierAttributes {
+			if v, ok := rawState[k].(string); ok {
+				rawState[k] = canonicalIdentifier(v)
+			}
+		}
+		return rawState, nil
+	}
+}
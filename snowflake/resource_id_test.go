@@ -0,0 +1,187 @@
+package snowflake
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDecodeID(t *testing.T) {
+	cases := []struct {
+		id    string
+		names []string
+		err   bool
+	}{
+		{id: "DB.PUBLIC.T", names: []string{"DB", "PUBLIC", "T"}},
+		{id: "db.public.t", names: []string{"DB", "PUBLIC", "T"}},
+		{id: `DB.PUBLIC."events.raw"`, names: []string{"DB", "PUBLIC", `"events.raw"`}},
+		{id: `DB."My ""Schema""".T`, names: []string{"DB", `"My ""Schema"""`, "T"}},
+		{id: "", err: true},
+		{id: "a..b", err: true},
+		{id: "DB.PUBLIC.", err: true},
+		{id: `DB.PUBLIC."T`, err: true},
+		{id: `DB.PUB"LIC.T`, err: true},
+		{id: "DB.PUBLIC", err: true},
+		{id: "DB.PUBLIC.T.SELECT", err: true},
+	}
+	for _, c := range cases {
+		names, err := decodeID(c.id, "database", "schema", "name")
+		if c.err {
+			if err == nil {
+				t.Errorf("decodeID(%q) = %q, want an error", c.id, names)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("decodeID(%q): %v", c.id, err)
+			continue
+		}
+		if !reflect.DeepEqual(names, c.names) {
+			t.Errorf("decodeID(%q) = %q, want %q", c.id, names, c.names)
+		}
+	}
+}
+
+func TestUpgradeIDV0(t *testing.T) {
+	cases := []struct {
+		name     string
+		resource *schema.Resource
+		state    map[string]interface{}
+		want     map[string]interface{}
+		err      bool
+	}{
+		{
+			name:     "grant with privileges",
+			resource: resourceSnowflakeTableGrant(),
+			state: map[string]interface{}{
+				"id":           "ANALYST.DB.PUBLIC.T.SELECT.INSERT",
+				"grantee_role": "ANALYST",
+				"database":     "DB",
+				"schema":       "PUBLIC",
+				"table":        "T",
+				"privileges":   []interface{}{"SELECT", "INSERT"},
+			},
+			want: map[string]interface{}{
+				"id":           "ANALYST.DB.PUBLIC.T",
+				"grantee_role": "ANALYST",
+				"database":     "DB",
+				"schema":       "PUBLIC",
+				"table":        "T",
+				"privileges":   []interface{}{"SELECT", "INSERT"},
+			},
+		},
+		{
+			name:     "grant of all tables",
+			resource: resourceSnowflakeTableGrant(),
+			state: map[string]interface{}{
+				"id":           "ANALYST.DB.PUBLIC.ALL.SELECT",
+				"grantee_role": "analyst",
+				"table":        "ALL",
+			},
+			// ALL is reserved, so like the table attribute of a new grant of
+			// all tables the migrated ID names "ALL" in double quotes.
+			want: map[string]interface{}{
+				"id":           `ANALYST.DB.PUBLIC."ALL"`,
+				"grantee_role": "ANALYST",
+				"table":        `"ALL"`,
+			},
+		},
+		{
+			name:     "lower case and special characters",
+			resource: resourceSnowflakeStage(),
+			state: map[string]interface{}{
+				"id":       "mydb.public.my-stage",
+				"database": "mydb",
+				"schema":   "public",
+				"name":     "my-stage",
+				"url":      "s3://bucket/path",
+			},
+			want: map[string]interface{}{
+				"id":       `MYDB.PUBLIC."MY-STAGE"`,
+				"database": "MYDB",
+				"schema":   "PUBLIC",
+				"name":     `"MY-STAGE"`,
+				"url":      "s3://bucket/path",
+			},
+		},
+		{
+			name:     "already canonical",
+			resource: resourceSnowflakeSchema(),
+			state:    map[string]interface{}{"id": "DB.PUBLIC", "database": "DB", "name": "PUBLIC"},
+			want:     map[string]interface{}{"id": "DB.PUBLIC", "database": "DB", "name": "PUBLIC"},
+		},
+		{
+			name:     "too few parts",
+			resource: resourceSnowflakeTableGrant(),
+			state:    map[string]interface{}{"id": "ANALYST.DB.PUBLIC"},
+			err:      true,
+		},
+		{
+			name:     "empty",
+			resource: resourceSnowflakeDatabase(),
+			state:    map[string]interface{}{"id": ""},
+			err:      true,
+		},
+		{
+			name:     "unterminated quote",
+			resource: resourceSnowflakeView(),
+			state:    map[string]interface{}{"id": `DB.PUBLIC."V`},
+			err:      true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if len(c.resource.StateUpgraders) != 1 || c.resource.SchemaVersion != idSchemaVersion {
+				t.Fatalf("resource has %d StateUpgraders and version %d", len(c.resource.StateUpgraders), c.resource.SchemaVersion)
+			}
+			state, err := c.resource.StateUpgraders[0].Upgrade(c.state, nil)
+			if c.err {
+				if err == nil {
+					t.Fatalf("Upgrade = %v, want an error", state)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(state, c.want) {
+				t.Fatalf("Upgrade = %v, want %v", state, c.want)
+			}
+		})
+	}
+}
+
+func TestIDStateUpgradersV0Type(t *testing.T) {
+	added := map[string]bool{}
+	for _, k := range v1Attributes {
+		added[k] = true
+	}
+	for name, r := range Provider().(*schema.Provider).ResourcesMap {
+		if len(r.StateUpgraders) == 0 {
+			continue
+		}
+		typ := r.StateUpgraders[0].Type
+		for _, k := range v1Attributes {
+			if typ.HasAttribute(k) {
+				t.Errorf("%s: version 0 has attribute %s", name, k)
+			}
+		}
+		for k := range r.Schema {
+			if !typ.HasAttribute(k) && !added[k] {
+				t.Errorf("%s: version 0 lacks attribute %s", name, k)
+			}
+		}
+		if _, ok := r.Schema["execution_role"]; !ok {
+			t.Errorf("%s: building the version 0 type removed execution_role from the resource", name)
+		}
+	}
+
+	// Resources added in version 1 have no state to migrate.
+	for _, name := range []string{"snowflake_warehouse", "snowflake_resource_monitor", "snowflake_warehouse_grant", "snowflake_file_format", "snowflake_sequence"} {
+		r := Provider().(*schema.Provider).ResourcesMap[name]
+		if r.SchemaVersion != 0 || r.StateUpgraders != nil {
+			t.Errorf("%s has version %d and %d StateUpgraders", name, r.SchemaVersion, len(r.StateUpgraders))
+		}
+	}
+}
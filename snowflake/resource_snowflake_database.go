@@ -1,35 +1,33 @@
 package snowflake
 
 import (
-	"database/sql"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
 )
 
 // TODO: Implement Clone parameter of create
 
 func resourceSnowflakeDatabase() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceSnowflakeDatabaseCreate,
 		Read:   resourceSnowflakeDatabaseRead,
 		Update: resourceSnowflakeDatabaseUpdate,
 		Delete: resourceSnowflakeDatabaseDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				d.SetId(strings.ToUpper(d.Id()))
-				return []*schema.ResourceData{d}, nil
-			},
+			State: importStateID("name"),
 		},
+		SchemaVersion: idSchemaVersion,
 		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    identifierStateFunc,
+				ValidateFunc: validateIdentifier,
 			},
 			"owner": {
 				Type:     schema.TypeString,
@@ -56,47 +54,47 @@ func resourceSnowflakeDatabase() *schema.Resource {
 		},
 		// Importer:
 	}
+	r.StateUpgraders = idStateUpgraders(r, "name")
+	return r
 }
 
 func resourceSnowflakeDatabaseCreate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	name := strings.ToUpper(d.Get("name").(string))
-	retentionTime := d.Get("retention_time")
-	transient := d.Get("transient").(bool)
-	comment := d.Get("comment")
-
-	statement := fmt.Sprintf("CREATE DATABASE %v DATA_RETENTION_TIME_IN_DAYS = %d", name, retentionTime)
-	if transient == true {
-		statement = fmt.Sprintf("CREATE TRANSIENT DATABASE %v DATA_RETENTION_TIME_IN_DAYS = %d", name, retentionTime)
-	}
-	if comment != "" {
-		statement += fmt.Sprintf(" COMMENT = '%v'", comment)
-	}
-	_, err := db.Exec(statement)
+	db, err := newClient(meta, d)
 	if err != nil {
 		return err
 	}
-	d.SetId(name)
-	return nil
+	name := canonicalIdentifier(d.Get("name").(string))
+
+	statement := snowsql.NewCreateBuilder("DATABASE", identifier(name)).
+		Modifier("TRANSIENT", d.Get("transient").(bool)).
+		SetInt("DATA_RETENTION_TIME_IN_DAYS", d.Get("retention_time").(int)).
+		SetString("COMMENT", d.Get("comment").(string)).
+		Statement()
+	_, err = db.Exec(statement)
+	return createThenRead(d, meta, db, name, err, resourceSnowflakeDatabaseRead)
 }
 
 func resourceSnowflakeDatabaseRead(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	name := d.Id()
-	databaseInfo, err := showDatabase(db, name)
+	db, err := newClient(meta, d)
 	if err != nil {
 		return err
 	}
-	d.Set("name", databaseInfo.name)
-	d.Set("owner", databaseInfo.owner)
-	d.Set("comment", databaseInfo.comment)
-	if databaseInfo.options == "TRANSIENT" {
+	defer db.Close()
+	name := d.Id()
+	databaseInfo, err := db.ShowDatabase(name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	d.Set("name", storedIdentifier(databaseInfo.Name))
+	d.Set("owner", databaseInfo.Owner)
+	d.Set("comment", databaseInfo.Comment)
+	if databaseInfo.Options == "TRANSIENT" {
 		d.Set("transient", true)
 	} else {
 		d.Set("transient", false)
 	}
-	if databaseInfo.retentionTime != "" {
-		retentionTime, err := strconv.Atoi(databaseInfo.retentionTime)
+	if databaseInfo.RetentionTime != "" {
+		retentionTime, err := strconv.Atoi(databaseInfo.RetentionTime)
 		if err != nil {
 			return err
 		}
@@ -106,15 +104,19 @@ func resourceSnowflakeDatabaseRead(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceSnowflakeDatabaseUpdate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	name := d.Id()
-	exists, err := sqlObjExists(db, "databases", name, "account")
+	exists, err := db.ObjectExists("databases", name, inAccount)
 
 	if err != nil {
 		return err
 	}
 	if exists == false {
-		return fmt.Errorf("Database %s does not exist", d.Id())
+		return newNotFoundError("Database", d.Id())
 	}
 	// Rather than issue a single alter database statement for all possible
 	// changes issue an alter for each possible thing that has changed. Enable
@@ -122,31 +124,31 @@ func resourceSnowflakeDatabaseUpdate(d *schema.ResourceData, meta interface{}) e
 	d.Partial(true)
 	if d.HasChange("name") {
 		// check that the rename target does not exist
-		exists, err := sqlObjExists(db, "databases", d.Get("name").(string), "account")
+		exists, err := db.ObjectExists("databases", d.Get("name").(string), inAccount)
 		if err != nil {
 			return err
 		}
 		if exists == true {
 			return fmt.Errorf("Cannot rename %v to %v, %v already exists", d.Id(), d.Get("name"), d.Get("name"))
 		}
-		statement := fmt.Sprintf("ALTER DATABASE %v RENAME TO %v", d.Id(), d.Get("name"))
-		if _, err := db.Exec(statement); err != nil {
+		statement := snowsql.NewAlterBuilder("DATABASE", identifier(d.Id())).Rename(identifier(d.Get("name").(string)))
+		if _, err = db.Exec(statement); err != nil {
 			return err
 		}
 		d.SetPartial("name")
-		d.SetId(d.Get("name").(string))
+		d.SetId(canonicalIdentifier(d.Get("name").(string)))
 
 	}
 	if d.HasChange("comment") {
-		statement := fmt.Sprintf("ALTER DATABASE %v SET COMMENT = '%v'", d.Id(), d.Get("comment"))
-		if _, err := db.Exec(statement); err != nil {
+		b := snowsql.NewAlterBuilder("DATABASE", identifier(d.Id())).SetString("COMMENT", d.Get("comment").(string))
+		if err = db.ExecAll(b.Statements()); err != nil {
 			return err
 		}
 		d.SetPartial("comment")
 	}
 	if d.HasChange("retention_time") {
-		statement := fmt.Sprintf("ALTER DATABASE %v SET DATA_RETENTION_TIME_IN_DAYS = %d", d.Id(), d.Get("retention_time"))
-		if _, err := db.Exec(statement); err != nil {
+		b := snowsql.NewAlterBuilder("DATABASE", identifier(d.Id())).SetInt("DATA_RETENTION_TIME_IN_DAYS", d.Get("retention_time").(int))
+		if err = db.ExecAll(b.Statements()); err != nil {
 			return err
 		}
 		d.SetPartial("retention_time")
@@ -156,17 +158,22 @@ func resourceSnowflakeDatabaseUpdate(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceSnowflakeDatabaseDelete(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	name := d.Id()
-	exists, err := sqlObjExists(db, "databases", name, "account")
+	exists, err := db.ObjectExists("databases", name, inAccount)
 	if err != nil {
 		return err
 	}
 	if exists == false {
-		return fmt.Errorf("Database %s does not exist", d.Id())
+		// Already dropped outside of Terraform.
+		return nil
 	}
-	statement := fmt.Sprintf("DROP DATABASE %v", d.Get("name"))
-	if _, err := db.Exec(statement); err != nil {
+	statement := snowsql.Drop("DATABASE", identifier(name))
+	if _, err = db.Exec(statement); err != nil {
 		return err
 	}
 	return nil
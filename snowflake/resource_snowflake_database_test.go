@@ -0,0 +1,152 @@
+package snowflake
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func testAccShowDatabase(c Client, id string) error {
+	_, err := c.ShowDatabase(id)
+	return err
+}
+
+func TestAccDatabase(t *testing.T) {
+	name := testAccName()
+	newName := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_database", testAccShowDatabase),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfig(name, "created", 1, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_database.test", testAccShowDatabase),
+					resource.TestCheckResourceAttr("snowflake_database.test", "id", name),
+					resource.TestCheckResourceAttr("snowflake_database.test", "name", name),
+					resource.TestCheckResourceAttr("snowflake_database.test", "comment", "created"),
+					resource.TestCheckResourceAttr("snowflake_database.test", "retention_time", "1"),
+					resource.TestCheckResourceAttr("snowflake_database.test", "transient", "false"),
+					resource.TestCheckResourceAttrSet("snowflake_database.test", "owner"),
+				),
+			},
+			// Renamed and altered in place.
+			{
+				Config: testAccDatabaseConfig(newName, "updated", 0, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_database.test", testAccShowDatabase),
+					resource.TestCheckResourceAttr("snowflake_database.test", "id", newName),
+					resource.TestCheckResourceAttr("snowflake_database.test", "comment", "updated"),
+					resource.TestCheckResourceAttr("snowflake_database.test", "retention_time", "0"),
+				),
+			},
+			// Replaced.
+			{
+				Config: testAccDatabaseConfig(newName, "updated", 0, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_database.test", testAccShowDatabase),
+					resource.TestCheckResourceAttr("snowflake_database.test", "transient", "true"),
+				),
+			},
+			{
+				ResourceName:      "snowflake_database.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Dropped outside of Terraform.
+			{
+				Config:             testAccDatabaseConfig(newName, "updated", 0, true),
+				Check:              testAccExec("DROP DATABASE " + newName),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccDatabaseConfig(name string, comment string, retentionTime int, transient bool) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name           = "%s"
+  comment        = "%s"
+  retention_time = %d
+  transient      = %t
+}
+`, name, comment, retentionTime, transient)
+}
+
+func TestDatabaseFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeDatabase()
+	c.rows["ShowDatabase D"] = showDatabaseRow{Name: "D", Owner: "SYSADMIN", Comment: "it's", RetentionTime: "3"}
+	c.objects["databases D IN ACCOUNT"] = true
+
+	state, err := testFakeApply(t, r, meta, nil, map[string]interface{}{
+		"name":           "d",
+		"comment":        "it's",
+		"retention_time": 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "CREATE DATABASE D DATA_RETENTION_TIME_IN_DAYS = 3 COMMENT = 'it''s'")
+	testCheckAttributes(t, state, map[string]string{
+		"id":             "D",
+		"name":           "D",
+		"owner":          "SYSADMIN",
+		"comment":        "it's",
+		"retention_time": "3",
+		"transient":      "false",
+	})
+
+	// Nothing changed.
+	if _, err = testFakeApply(t, r, meta, state, map[string]interface{}{"name": "D", "comment": "it's", "retention_time": 3}); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c)
+
+	state, err = testFakeApply(t, r, meta, state, map[string]interface{}{"name": "D", "retention_time": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c,
+		"ALTER DATABASE D UNSET COMMENT",
+		"ALTER DATABASE D SET DATA_RETENTION_TIME_IN_DAYS = 1",
+	)
+
+	imported, err := testFakeImport(t, r, meta, "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c)
+	testCheckAttributes(t, imported, map[string]string{"id": "D", "name": "D", "owner": "SYSADMIN"})
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "DROP DATABASE D")
+
+	// Dropped outside of Terraform.
+	delete(c.rows, "ShowDatabase D")
+	delete(c.objects, "databases D IN ACCOUNT")
+	if refreshed, err := r.Refresh(state, meta); err != nil || refreshed != nil {
+		t.Fatalf("refreshing a dropped database returned %v, %v, want it removed from the state", refreshed, err)
+	}
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c)
+	if _, err = testFakeApply(t, r, meta, state, map[string]interface{}{"name": "D", "comment": "c"}); !isNotFound(err) {
+		t.Fatalf("updating a dropped database returned %v, want a notFoundError", err)
+	}
+	testCheckStatements(t, c)
+
+	// Failing statements fail the operation.
+	c.execErr = func(statement string) error {
+		return fmt.Errorf("SQL compilation error")
+	}
+	if _, err = testFakeApply(t, r, meta, nil, map[string]interface{}{"name": "D"}); err == nil {
+		t.Fatal("creating the database succeeded although the statement failed")
+	}
+	testCheckStatements(t, c, "CREATE DATABASE D DATA_RETENTION_TIME_IN_DAYS = 1")
+}
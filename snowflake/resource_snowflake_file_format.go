@@ -0,0 +1,356 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
+)
+
+// fileFormatTypes are the types of file formats.
+var fileFormatTypes = []string{"CSV", "JSON", "AVRO", "ORC", "PARQUET", "XML"}
+
+// fileFormatTypeOptions are the attributes that only apply to some types of
+// file formats, with those types.
+var fileFormatTypeOptions = []struct {
+	option string
+	types  []string
+}{
+	{"record_delimiter", []string{"CSV"}},
+	{"field_delimiter", []string{"CSV"}},
+	{"skip_header", []string{"CSV"}},
+	{"field_optionally_enclosed_by", []string{"CSV"}},
+	{"null_if", []string{"CSV"}},
+	{"strip_outer_array", []string{"JSON"}},
+	{"compression", []string{"CSV", "JSON", "AVRO", "PARQUET", "XML"}},
+	{"trim_space", []string{"CSV", "AVRO", "ORC", "PARQUET"}},
+}
+
+// fileFormatCompressions are the compressions of file formats, PARQUET only
+// takes AUTO, LZO, SNAPPY and NONE.
+var fileFormatCompressions = []string{"AUTO", "GZIP", "BZ2", "BROTLI", "ZSTD", "DEFLATE", "RAW_DEFLATE", "LZO", "SNAPPY", "NONE"}
+
+// fileFormatOptionApplies tells whether the attribute option applies to file
+// formats of type formatType.
+func fileFormatOptionApplies(option string, formatType string) bool {
+	for _, o := range fileFormatTypeOptions {
+		if o.option != option {
+			continue
+		}
+		for _, t := range o.types {
+			if t == formatType {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func resourceSnowflakeFileFormat() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceSnowflakeFileFormatCreate,
+		Read:   resourceSnowflakeFileFormatRead,
+		Update: resourceSnowflakeFileFormatUpdate,
+		Delete: resourceSnowflakeFileFormatDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStateID("database", "schema", "name"),
+		},
+		// Options of another type are rejected here rather than by Snowflake.
+		// Options left out of the configuration keep the value read back, so
+		// only options being set are checked.
+		CustomizeDiff: func(d *schema.ResourceDiff, meta interface{}) error {
+			formatType := strings.ToUpper(d.Get("type").(string))
+			for _, option := range fileFormatTypeOptions {
+				if fileFormatOptionApplies(option.option, formatType) {
+					continue
+				}
+				if _, ok := d.GetOk(option.option); ok && d.HasChange(option.option) {
+					return fmt.Errorf("%s only applies to file formats of type %s", option.option, strings.Join(option.types, ", "))
+				}
+			}
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				StateFunc:    identifierStateFunc,
+				ValidateFunc: validateIdentifier,
+			},
+			"schema": {
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
+			},
+			"database": {
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				StateFunc: func(v interface{}) string {
+					return strings.ToUpper(v.(string))
+				},
+				ValidateFunc: validation.StringInSlice(fileFormatTypes, true),
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// CSV options. Left out of the configuration they take the
+			// default of Snowflake when the file format is created, and keep
+			// their value when they are removed later on.
+			"record_delimiter": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"field_delimiter": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"skip_header": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"field_optionally_enclosed_by": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"null_if": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				Computed: true,
+			},
+			// JSON options.
+			"strip_outer_array": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			// Options of several types, see fileFormatTypeOptions.
+			"compression": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				StateFunc: func(v interface{}) string {
+					return strings.ToUpper(v.(string))
+				},
+				ValidateFunc: validation.StringInSlice(fileFormatCompressions, true),
+			},
+			"trim_space": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			// The name stages and pipes refer to the file format by, e.g. in
+			// FILE_FORMAT = (FORMAT_NAME = ...).
+			"fully_qualified_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"owner": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// expandNullIf returns the null_if attribute of a file format.
+func expandNullIf(d *schema.ResourceData) []string {
+	var values []string
+	for _, v := range d.Get("null_if").([]interface{}) {
+		// An empty string in the list comes as nil.
+		value, _ := v.(string)
+		values = append(values, value)
+	}
+	return values
+}
+
+// fileFormatNameSchema is the schema of the file_format_name attribute of
+// stages and pipes, which takes the fully_qualified_name of a
+// snowflake_file_format.
+func fileFormatNameSchema(conflictsWith ...string) *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		ForceNew:      true,
+		ConflictsWith: conflictsWith,
+		ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+			if _, err := decodeID(v.(string), "database", "schema", "name"); err != nil {
+				errors = append(errors, fmt.Errorf("%s: %v", k, err))
+			}
+			return
+		},
+	}
+}
+
+// renderFormatName renders the FILE_FORMAT options naming the file format of
+// the file_format_name attribute, empty if there is none.
+func renderFormatName(d *schema.ResourceData) string {
+	names, err := decodeID(d.Get("file_format_name").(string), "database", "schema", "name")
+	if err != nil {
+		// fileFormatNameSchema rejects such names in the configuration.
+		return ""
+	}
+	return "FORMAT_NAME = " + qualifiedName(names...)
+}
+
+func resourceSnowflakeFileFormatCreate(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	databaseName := d.Get("database").(string)
+	schemaName := d.Get("schema").(string)
+	name := d.Get("name").(string)
+	formatType := strings.ToUpper(d.Get("type").(string))
+	b := snowsql.NewCreateBuilder("FILE FORMAT", qualifiedName(databaseName, schemaName, name)).
+		SetRaw("TYPE", formatType)
+	switch formatType {
+	case "CSV":
+		b.SetString("RECORD_DELIMITER", d.Get("record_delimiter").(string)).
+			SetString("FIELD_DELIMITER", d.Get("field_delimiter").(string)).
+			SetInt("SKIP_HEADER", d.Get("skip_header").(int)).
+			SetString("FIELD_OPTIONALLY_ENCLOSED_BY", d.Get("field_optionally_enclosed_by").(string)).
+			SetStringList("NULL_IF", expandNullIf(d))
+	case "JSON":
+		b.SetBool("STRIP_OUTER_ARRAY", d.Get("strip_outer_array").(bool))
+	}
+	if fileFormatOptionApplies("compression", formatType) {
+		b.SetRaw("COMPRESSION", strings.ToUpper(d.Get("compression").(string)))
+	}
+	if trim := d.Get("trim_space").(bool); trim && fileFormatOptionApplies("trim_space", formatType) {
+		b.SetBool("TRIM_SPACE", trim)
+	}
+	b.SetString("COMMENT", d.Get("comment").(string))
+	_, err = db.Exec(b.Statement())
+	return createThenRead(d, meta, db, encodeID(databaseName, schemaName, name), err, resourceSnowflakeFileFormatRead)
+}
+
+func resourceSnowflakeFileFormatRead(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	database, schema, name := id[0], id[1], id[2]
+	r, err := db.ShowFileFormat(database, schema, name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	options, err := db.DescFileFormat(database, schema, name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	nullIf, err := formatOptionsNullIf(r.FormatOptions)
+	if err != nil {
+		return err
+	}
+	d.Set("database", storedIdentifier(r.DatabaseName))
+	d.Set("schema", storedIdentifier(r.SchemaName))
+	d.Set("name", storedIdentifier(r.Name))
+	d.Set("fully_qualified_name", qualifiedName(database, schema, name))
+	d.Set("type", options.formatType)
+	d.Set("comment", r.Comment)
+	d.Set("owner", r.Owner)
+	d.Set("record_delimiter", options.recordDelimiter)
+	d.Set("field_delimiter", options.fieldDelimiter)
+	d.Set("skip_header", options.skipHeader)
+	d.Set("field_optionally_enclosed_by", options.fieldOptionallyEnclosedBy)
+	d.Set("null_if", nullIf)
+	d.Set("strip_outer_array", options.stripOuterArray)
+	d.Set("compression", options.compression)
+	d.Set("trim_space", options.trimSpace)
+
+	return nil
+}
+
+func resourceSnowflakeFileFormatUpdate(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	databaseName, schemaName, name := id[0], id[1], id[2]
+	// The type cannot be altered, the options of the type and the comment
+	// are set in one go. Options of other types are left alone, Snowflake
+	// rejects them. Only the comment is unset when it is removed, the
+	// options keep their value, see the schema.
+	formatType := strings.ToUpper(d.Get("type").(string))
+	changed := func(key string) bool {
+		return d.HasChange(key) && fileFormatOptionApplies(key, formatType)
+	}
+	b := snowsql.NewAlterBuilder("FILE FORMAT", qualifiedName(databaseName, schemaName, name))
+	for _, key := range []string{"record_delimiter", "field_delimiter", "field_optionally_enclosed_by", "comment"} {
+		if changed(key) {
+			b.SetString(strings.ToUpper(key), d.Get(key).(string))
+		}
+	}
+	if changed("skip_header") {
+		b.SetInt("SKIP_HEADER", d.Get("skip_header").(int))
+	}
+	if changed("null_if") {
+		b.SetStringList("NULL_IF", expandNullIf(d))
+	}
+	if changed("strip_outer_array") {
+		b.SetBool("STRIP_OUTER_ARRAY", d.Get("strip_outer_array").(bool))
+	}
+	if changed("compression") {
+		b.SetRaw("COMPRESSION", strings.ToUpper(d.Get("compression").(string)))
+	}
+	if changed("trim_space") {
+		b.SetBool("TRIM_SPACE", d.Get("trim_space").(bool))
+	}
+	return db.ExecAll(b.Statements())
+}
+
+func resourceSnowflakeFileFormatDelete(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	databaseName, schemaName, name := id[0], id[1], id[2]
+	exists, err := db.ObjectExists("file formats", name, inSchema(databaseName, schemaName))
+	if err != nil {
+		return err
+	}
+	if exists == false {
+		// Already dropped outside of Terraform.
+		return nil
+	}
+	statement := snowsql.Drop("FILE FORMAT", qualifiedName(databaseName, schemaName, name))
+	if _, err = db.Exec(statement); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package snowflake
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func testAccShowFileFormat(c Client, id string) error {
+	names, err := decodeID(id, "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	_, err = c.ShowFileFormat(names[0], names[1], names[2])
+	return err
+}
+
+func TestAccFileFormat(t *testing.T) {
+	database := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_file_format", testAccShowFileFormat),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFileFormatConfig(database, "created", ",", 0),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_file_format.csv", testAccShowFileFormat),
+					testAccCheckExists("snowflake_file_format.json", testAccShowFileFormat),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "id", database+".PUBLIC.CSV"),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "fully_qualified_name", database+".PUBLIC.CSV"),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "type", "CSV"),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "comment", "created"),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "record_delimiter", "\n"),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "field_delimiter", ","),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "skip_header", "0"),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "field_optionally_enclosed_by", `"`),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "null_if.#", "3"),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "null_if.0", `\N`),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "null_if.1", "NULL"),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "null_if.2", "a, b"),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "compression", "AUTO"),
+					resource.TestCheckResourceAttr("snowflake_file_format.json", "type", "JSON"),
+					resource.TestCheckResourceAttr("snowflake_file_format.json", "strip_outer_array", "true"),
+					resource.TestCheckResourceAttr("snowflake_file_format.json", "field_delimiter", ""),
+					resource.TestCheckResourceAttr("snowflake_file_format.parquet", "compression", "SNAPPY"),
+					resource.TestCheckResourceAttr("snowflake_stage.test", "file_format_name", database+".PUBLIC.CSV"),
+				),
+			},
+			// Altered in place.
+			{
+				Config: testAccFileFormatConfig(database, "updated", "\\t", 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_file_format.csv", testAccShowFileFormat),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "id", database+".PUBLIC.CSV"),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "comment", "updated"),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "field_delimiter", "\t"),
+					resource.TestCheckResourceAttr("snowflake_file_format.csv", "skip_header", "1"),
+				),
+			},
+			{
+				ResourceName:      "snowflake_file_format.csv",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "snowflake_file_format.json",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "snowflake_file_format.parquet",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Options of another type are rejected.
+			{
+				Config:      testAccFileFormatMismatchConfig(database),
+				ExpectError: regexp.MustCompile("field_delimiter only applies to file formats of type CSV"),
+			},
+			// Dropped outside of Terraform.
+			{
+				Config:             testAccFileFormatConfig(database, "updated", "\\t", 1),
+				Check:              testAccExec(fmt.Sprintf("DROP FILE FORMAT %s.PUBLIC.CSV", database)),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccFileFormatConfig(database string, comment string, fieldDelimiter string, skipHeader int) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_file_format" "csv" {
+  database                     = "${snowflake_database.test.name}"
+  schema                       = "PUBLIC"
+  name                         = "CSV"
+  type                         = "csv"
+  comment                      = "%s"
+  field_delimiter              = "%s"
+  skip_header                  = %d
+  field_optionally_enclosed_by = "\""
+  null_if                      = ["\\N", "NULL", "a, b"]
+}
+
+resource "snowflake_file_format" "json" {
+  database          = "${snowflake_database.test.name}"
+  schema            = "PUBLIC"
+  name              = "JSON"
+  type              = "JSON"
+  strip_outer_array = true
+}
+
+resource "snowflake_file_format" "parquet" {
+  database    = "${snowflake_database.test.name}"
+  schema      = "PUBLIC"
+  name        = "PARQUET"
+  type        = "PARQUET"
+  compression = "snappy"
+}
+
+resource "snowflake_stage" "test" {
+  database         = "${snowflake_database.test.name}"
+  name             = "EVENTS"
+  url              = "s3://tf-acc-stage/events/"
+  file_format_name = "${snowflake_file_format.csv.fully_qualified_name}"
+}
+`, database, comment, fieldDelimiter, skipHeader)
+}
+
+func testAccFileFormatMismatchConfig(database string) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_file_format" "json" {
+  database        = "${snowflake_database.test.name}"
+  schema          = "PUBLIC"
+  name            = "JSON"
+  type            = "JSON"
+  field_delimiter = "|"
+}
+`, database)
+}
+
+func TestFileFormatFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeFileFormat()
+	c.rows["ShowFileFormat D S F"] = showFileFormatRow{
+		Name:          "F",
+		DatabaseName:  "D",
+		SchemaName:    "S",
+		Type:          "CSV",
+		Owner:         "SYSADMIN",
+		FormatOptions: `{"TYPE":"CSV","NULL_IF":["","a, b"]}`,
+	}
+	c.rows["DescFileFormat D S F"] = descFileFormatResult{
+		formatType:      "CSV",
+		recordDelimiter: `\n`,
+		fieldDelimiter:  "|",
+		skipHeader:      1,
+		compression:     "AUTO",
+	}
+	c.objects["file formats F IN SCHEMA D.S"] = true
+	raw := map[string]interface{}{
+		"database":        "d",
+		"schema":          "s",
+		"name":            "f",
+		"type":            "csv",
+		"field_delimiter": "|",
+		"skip_header":     1,
+		"null_if":         []interface{}{"", "a, b"},
+	}
+
+	state, err := testFakeApply(t, r, meta, nil, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "CREATE FILE FORMAT D.S.F TYPE = CSV FIELD_DELIMITER = '|' SKIP_HEADER = 1 NULL_IF = ('', 'a, b')")
+	testCheckAttributes(t, state, map[string]string{
+		"id":                   "D.S.F",
+		"type":                 "CSV",
+		"record_delimiter":     `\n`,
+		"null_if.#":            "2",
+		"null_if.1":            "a, b",
+		"compression":          "AUTO",
+		"fully_qualified_name": "D.S.F",
+	})
+
+	raw["field_delimiter"] = ","
+	raw["comment"] = "c"
+	raw["compression"] = "gzip"
+	if state, err = testFakeApply(t, r, meta, state, raw); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "ALTER FILE FORMAT D.S.F SET FIELD_DELIMITER = ',' COMMENT = 'c' COMPRESSION = GZIP")
+
+	raw["strip_outer_array"] = true
+	if _, err = testFakeApply(t, r, meta, state, raw); err == nil {
+		t.Fatal("setting a JSON option on a CSV file format succeeded")
+	}
+	testCheckStatements(t, c)
+
+	imported, err := testFakeImport(t, r, meta, "D.S.F")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{"name": "F", "type": "CSV", "skip_header": "1", "null_if.0": "", "null_if.1": "a, b"})
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "DROP FILE FORMAT D.S.F")
+}
+
+func TestFileFormatFakeClientTypes(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeFileFormat()
+	raw := map[string]interface{}{
+		"database":    "D",
+		"schema":      "S",
+		"name":        "A",
+		"type":        "avro",
+		"compression": "gzip",
+		"trim_space":  true,
+	}
+	if _, err := testFakeApply(t, r, meta, nil, raw); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "CREATE FILE FORMAT D.S.A TYPE = AVRO COMPRESSION = GZIP TRIM_SPACE = TRUE")
+
+	// An option of another type in the state, e.g. of an earlier version of
+	// the provider, is not altered.
+	c.objects["file formats J IN SCHEMA D.S"] = true
+	state := testFakeState(t, r, "D.S.J", map[string]interface{}{
+		"database":   "D",
+		"schema":     "S",
+		"name":       "J",
+		"type":       "JSON",
+		"trim_space": true,
+	})
+	raw = map[string]interface{}{"database": "D", "schema": "S", "name": "J", "type": "JSON", "comment": "c"}
+	if _, err := testFakeApply(t, r, meta, state, raw); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "ALTER FILE FORMAT D.S.J SET COMMENT = 'c'")
+}
+
+func TestFormatOptionsNullIf(t *testing.T) {
+	cases := []struct {
+		formatOptions string
+		want          []string
+	}{
+		{"", nil},
+		{`{"TYPE":"JSON"}`, nil},
+		{`{"TYPE":"CSV","NULL_IF":[]}`, []string{}},
+		{`{"TYPE":"CSV","NULL_IF":["\\N","NULL"]}`, []string{`\N`, "NULL"}},
+		{`{"TYPE":"CSV","NULL_IF":["a, b","","]","it's"]}`, []string{"a, b", "", "]", "it's"}},
+	}
+	for _, c := range cases {
+		got, err := formatOptionsNullIf(c.formatOptions)
+		if err != nil {
+			t.Errorf("%s: %v", c.formatOptions, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %q, want %q", c.formatOptions, got, c.want)
+		}
+	}
+	if _, err := formatOptionsNullIf("[a, b]"); err == nil {
+		t.Error("parsing [a, b] succeeded")
+	}
+}
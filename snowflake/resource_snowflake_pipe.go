@@ -1,49 +1,41 @@
 package snowflake
 
 import (
-	"database/sql"
-	"fmt"
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
 )
 
 func resourceSnowflakePipe() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceSnowflakePipeCreate,
 		Read:   resourceSnowflakePipeRead,
 		Update: resourceSnowflakePipeUpdate,
 		Delete: resourceSnowflakePipeDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				d.SetId(strings.ToUpper(d.Id()))
-				return []*schema.ResourceData{d}, nil
-			},
+			State: importStateID("database", "schema", "name"),
 		},
+		SchemaVersion: idSchemaVersion,
 		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
 			},
 			"schema": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
 			},
 			"database": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
 			},
 			"comment": {
 				Type:     schema.TypeString,
@@ -57,6 +49,8 @@ func resourceSnowflakePipe() *schema.Resource {
 					return strings.TrimSpace(v.(string))
 				},
 			},
+			// Added to the copy statement as its FILE_FORMAT.
+			"file_format_name": fileFormatNameSchema(),
 			"auto_ingest": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -73,62 +67,112 @@ func resourceSnowflakePipe() *schema.Resource {
 			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(r, "database", "schema", "name")
+	return r
 }
 
 func resourceSnowflakePipeCreate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	databaseName := d.Get("database")
-	schemaName := d.Get("schema")
-	name := d.Get("name")
-	comment := d.Get("comment")
-	copyStatement := d.Get("copy_statement")
-	autoIngest := d.Get("auto_ingest")
-	pipeID := fmt.Sprintf("%s.%s.%s", databaseName, schemaName, name)
-	statement := fmt.Sprintf("CREATE PIPE %s auto_ingest=%t comment='%s' as %s", pipeID, autoIngest, comment, copyStatement)
-	_, err := db.Exec(statement)
+	db, err := newClient(meta, d)
 	if err != nil {
 		return err
 	}
-	d.SetId(strings.ToUpper(pipeID))
-	return nil
+	databaseName := d.Get("database").(string)
+	schemaName := d.Get("schema").(string)
+	name := d.Get("name").(string)
+	pipeID := encodeID(databaseName, schemaName, name)
+	statement := snowsql.NewCreateBuilder("PIPE", qualifiedName(databaseName, schemaName, name)).
+		SetBool("AUTO_INGEST", d.Get("auto_ingest").(bool)).
+		SetString("COMMENT", d.Get("comment").(string)).
+		As(pipeCopyStatement(d)).
+		Statement()
+	_, err = db.Exec(statement)
+	return createThenRead(d, meta, db, pipeID, err, resourceSnowflakePipeRead)
+}
+
+// pipeCopyStatement returns the copy statement of a pipe with the FILE_FORMAT
+// of its file_format_name, if any.
+func pipeCopyStatement(d *schema.ResourceData) string {
+	statement := d.Get("copy_statement").(string)
+	if formatName := renderFormatName(d); formatName != "" {
+		statement = strings.TrimSpace(statement) + " FILE_FORMAT = (" + formatName + ")"
+	}
+	return statement
 }
+
 func resourceSnowflakePipeRead(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	pipeID := d.Id()
-	s := strings.Split(pipeID, ".")
-	database, schema, name := s[0], s[1], s[2]
-	r, err := showPipe(db, database, schema, name)
+	id, err := decodeID(pipeID, "database", "schema", "name")
 	if err != nil {
 		return err
 	}
-	d.Set("database", r.databaseName)
-	d.Set("schema", r.schemaName)
-	d.Set("copy_statement", strings.TrimSpace(r.definition))
-	d.Set("owner", r.owner)
-	d.Set("notification_channel", r.notificationChannel)
-	d.Set("auto_ingest", r.notificationChannel != "")
-	d.Set("comment", r.comment)
-	d.Set("name", r.name)
+	database, schema, name := id[0], id[1], id[2]
+	r, err := db.ShowPipe(database, schema, name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	d.Set("database", storedIdentifier(r.DatabaseName))
+	d.Set("schema", storedIdentifier(r.SchemaName))
+	// The FILE_FORMAT of file_format_name is not part of copy_statement.
+	definition := strings.TrimSpace(r.Definition)
+	if formatName := renderFormatName(d); formatName != "" {
+		definition = strings.TrimSuffix(definition, " FILE_FORMAT = ("+formatName+")")
+	}
+	d.Set("copy_statement", definition)
+	d.Set("owner", r.Owner)
+	d.Set("notification_channel", r.NotificationChannel)
+	d.Set("auto_ingest", r.NotificationChannel != "")
+	d.Set("comment", r.Comment)
+	d.Set("name", storedIdentifier(r.Name))
 
 	return nil
 }
 func resourceSnowflakePipeUpdate(d *schema.ResourceData, meta interface{}) error {
-	// Update comment
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	databaseName, schemaName, name := id[0], id[1], id[2]
+	// The comment is the only property that can be altered, everything else
+	// forces a new pipe.
+	if d.HasChange("comment") {
+		b := snowsql.NewAlterBuilder("PIPE", qualifiedName(databaseName, schemaName, name)).SetString("COMMENT", d.Get("comment").(string))
+		if err = db.ExecAll(b.Statements()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 func resourceSnowflakePipeDelete(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	s := strings.Split(d.Id(), ".")
-	databaseName, schemaName, name := s[0], s[1], s[2]
-	exists, err := sqlObjExists(db, "pipes", name, fmt.Sprintf("%s.%s", databaseName, schemaName))
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	databaseName, schemaName, name := id[0], id[1], id[2]
+	exists, err := db.ObjectExists("pipes", name, inSchema(databaseName, schemaName))
 	if err != nil {
 		return err
 	}
 	if exists == false {
-		return fmt.Errorf("Pipe %s does not exist", d.Id())
+		// Already dropped outside of Terraform.
+		return nil
 	}
-	statement := fmt.Sprintf("DROP PIPE %s", d.Id())
-	if _, err := db.Exec(statement); err != nil {
+	statement := snowsql.Drop("PIPE", qualifiedName(databaseName, schemaName, name))
+	if _, err = db.Exec(statement); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,203 @@
+package snowflake
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func testAccShowPipe(c Client, id string) error {
+	names, err := decodeID(id, "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	_, err = c.ShowPipe(names[0], names[1], names[2])
+	return err
+}
+
+func TestAccPipe(t *testing.T) {
+	database := testAccName()
+	name := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_pipe", testAccShowPipe),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPipeConfig(database, name, "created", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_pipe.test", testAccShowPipe),
+					resource.TestCheckResourceAttr("snowflake_pipe.test", "id", database+".PUBLIC."+name),
+					resource.TestCheckResourceAttr("snowflake_pipe.test", "name", name),
+					resource.TestCheckResourceAttr("snowflake_pipe.test", "comment", "created"),
+					resource.TestCheckResourceAttr("snowflake_pipe.test", "auto_ingest", "false"),
+					resource.TestCheckResourceAttr("snowflake_pipe.test", "notification_channel", ""),
+					resource.TestCheckResourceAttrSet("snowflake_pipe.test", "owner"),
+				),
+			},
+			// Altered in place.
+			{
+				Config: testAccPipeConfig(database, name, "updated", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_pipe.test", testAccShowPipe),
+					resource.TestCheckResourceAttr("snowflake_pipe.test", "comment", "updated"),
+				),
+			},
+			// Replaced.
+			{
+				Config: testAccPipeConfig(database, name, "updated", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_pipe.test", testAccShowPipe),
+					resource.TestCheckResourceAttr("snowflake_pipe.test", "auto_ingest", "true"),
+					resource.TestCheckResourceAttrSet("snowflake_pipe.test", "notification_channel"),
+				),
+			},
+			{
+				ResourceName:      "snowflake_pipe.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Dropped outside of Terraform.
+			{
+				Config:             testAccPipeConfig(database, name, "updated", true),
+				Check:              testAccExec(fmt.Sprintf("DROP PIPE %s.PUBLIC.%s", database, name)),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccPipeConfig(database string, name string, comment string, autoIngest bool) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_table" "test" {
+  database = "${snowflake_database.test.name}"
+  schema   = "PUBLIC"
+  name     = "EVENTS"
+
+  columns {
+    name = "DATA"
+    type = "VARIANT"
+  }
+}
+
+resource "snowflake_stage" "test" {
+  database = "${snowflake_database.test.name}"
+  name     = "EVENTS"
+  url      = "s3://tf-acc-stage/events/"
+}
+
+resource "snowflake_pipe" "test" {
+  database       = "${snowflake_database.test.name}"
+  schema         = "PUBLIC"
+  name           = "%s"
+  comment        = "%s"
+  auto_ingest    = %t
+  copy_statement = "copy into ${snowflake_table.test.database}.PUBLIC.${snowflake_table.test.name} from @${snowflake_stage.test.database}.PUBLIC.${snowflake_stage.test.name} file_format = (type = 'JSON')"
+}
+`, database, name, comment, autoIngest)
+}
+
+func TestPipeFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakePipe()
+	c.rows["ShowPipe D S P"] = showPipeRow{
+		Name:                "P",
+		DatabaseName:        "D",
+		SchemaName:          "S",
+		Definition:          "COPY INTO D.S.T FROM @D.S.STAGE",
+		Owner:               "SYSADMIN",
+		NotificationChannel: "arn:aws:sqs:us-east-1:1:q",
+	}
+	c.objects["pipes P IN SCHEMA D.S"] = true
+	raw := map[string]interface{}{
+		"database":       "d",
+		"schema":         "s",
+		"name":           "p",
+		"auto_ingest":    true,
+		"copy_statement": "\nCOPY INTO D.S.T FROM @D.S.STAGE\n",
+	}
+
+	state, err := testFakeApply(t, r, meta, nil, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "CREATE PIPE D.S.P AUTO_INGEST = TRUE AS\n\nCOPY INTO D.S.T FROM @D.S.STAGE\n")
+	testCheckAttributes(t, state, map[string]string{
+		"id":                   "D.S.P",
+		"owner":                "SYSADMIN",
+		"notification_channel": "arn:aws:sqs:us-east-1:1:q",
+		"copy_statement":       "COPY INTO D.S.T FROM @D.S.STAGE",
+	})
+
+	raw["comment"] = "it's"
+	if state, err = testFakeApply(t, r, meta, state, raw); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "ALTER PIPE D.S.P SET COMMENT = 'it''s'")
+
+	imported, err := testFakeImport(t, r, meta, "D.S.P")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{"name": "P", "auto_ingest": "true"})
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "DROP PIPE D.S.P")
+}
+
+func TestPipeFakeClientFileFormatName(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakePipe()
+	c.rows["ShowPipe D S P"] = showPipeRow{
+		Name:         "P",
+		DatabaseName: "D",
+		SchemaName:   "S",
+		Definition:   `COPY INTO D.S.T FROM @D.S.STAGE FILE_FORMAT = (FORMAT_NAME = D.S."csv")`,
+	}
+
+	state, err := testFakeApply(t, r, meta, nil, map[string]interface{}{
+		"database":         "D",
+		"schema":           "S",
+		"name":             "P",
+		"copy_statement":   "COPY INTO D.S.T FROM @D.S.STAGE\n",
+		"file_format_name": `d.s."csv"`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, `CREATE PIPE D.S.P AUTO_INGEST = FALSE AS`+"\n"+`COPY INTO D.S.T FROM @D.S.STAGE FILE_FORMAT = (FORMAT_NAME = D.S."csv")`)
+	testCheckAttributes(t, state, map[string]string{"copy_statement": "COPY INTO D.S.T FROM @D.S.STAGE"})
+
+	// Imported, the FILE_FORMAT stays part of the copy statement.
+	imported, err := testFakeImport(t, r, meta, "D.S.P")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{
+		"copy_statement":   `COPY INTO D.S.T FROM @D.S.STAGE FILE_FORMAT = (FORMAT_NAME = D.S."csv")`,
+		"file_format_name": "",
+	})
+
+	cfg, err := config.NewRawConfig(map[string]interface{}{
+		"database":         "D",
+		"schema":           "S",
+		"name":             "P",
+		"copy_statement":   "COPY INTO D.S.T FROM @D.S.STAGE",
+		"file_format_name": "CSV",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errs := r.Validate(terraform.NewResourceConfig(cfg)); len(errs) == 0 {
+		t.Fatal("a file_format_name that is not fully qualified is valid")
+	}
+}
@@ -0,0 +1,474 @@
+package snowflake
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
+)
+
+// resourceMonitorActions are what a resource monitor trigger can do.
+var resourceMonitorActions = []string{"NOTIFY", "SUSPEND", "SUSPEND_IMMEDIATE"}
+
+func resourceSnowflakeResourceMonitor() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceSnowflakeResourceMonitorCreate,
+		Read:   resourceSnowflakeResourceMonitorRead,
+		Update: resourceSnowflakeResourceMonitorUpdate,
+		Delete: resourceSnowflakeResourceMonitorDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStateID("name"),
+		},
+		// Snowflake has no statement removing the credit quota or all
+		// triggers of a monitor, the monitor is replaced instead.
+		CustomizeDiff: func(d *schema.ResourceDiff, meta interface{}) error {
+			if o, n := d.GetChange("credit_quota"); o.(int) > 0 && n.(int) == 0 {
+				if err := d.ForceNew("credit_quota"); err != nil {
+					return err
+				}
+			}
+			o, n := d.GetChange("trigger")
+			if o.(*schema.Set).Len() > 0 && n.(*schema.Set).Len() == 0 {
+				return d.ForceNew("trigger")
+			}
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				StateFunc:    identifierStateFunc,
+				ValidateFunc: validateIdentifier,
+			},
+			"credit_quota": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			// Removing the frequency sets it back to the default of Snowflake.
+			"frequency": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "MONTHLY",
+				StateFunc: func(v interface{}) string {
+					return strings.ToUpper(v.(string))
+				},
+				ValidateFunc: validation.StringInSlice([]string{"MONTHLY", "DAILY", "WEEKLY", "YEARLY", "NEVER"}, true),
+			},
+			// SHOW RESOURCE MONITORS returns the timestamps in the time zone
+			// of the account, they are read back as configured if they name
+			// the same time, see readMonitorTimestamp.
+			"start_timestamp": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"end_timestamp": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"notify_users": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+			},
+			"trigger": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						// The percentage of the credit quota used.
+						"threshold": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"action": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(resourceMonitorActions, false),
+						},
+					},
+				},
+			},
+			// Assign the monitor either to the account or to warehouses here,
+			// or to a warehouse with its resource_monitor, not both.
+			"set_for_account": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"warehouses"},
+			},
+			"warehouses": {
+				Type:          schema.TypeSet,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Optional:      true,
+				ConflictsWith: []string{"set_for_account"},
+			},
+		},
+	}
+}
+
+// renderNotifyUsers renders the users of the notify_users set, e.g. (A, B).
+func renderNotifyUsers(users *schema.Set) string {
+	names := make([]string, 0, users.Len())
+	for _, u := range users.List() {
+		names = append(names, identifier(u.(string)))
+	}
+	sort.Strings(names)
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+// renderTriggers renders the trigger set as the TRIGGERS clause of CREATE
+// and ALTER RESOURCE MONITOR, empty if there are none.
+func renderTriggers(triggers *schema.Set) string {
+	if triggers.Len() == 0 {
+		return ""
+	}
+	list := triggers.List()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].(map[string]interface{})["threshold"].(int) < list[j].(map[string]interface{})["threshold"].(int)
+	})
+	var definitions []string
+	for _, t := range list {
+		t := t.(map[string]interface{})
+		definitions = append(definitions, fmt.Sprintf("ON %d PERCENT DO %s", t["threshold"].(int), t["action"].(string)))
+	}
+	return "TRIGGERS " + strings.Join(definitions, " ")
+}
+
+// renderStartTimestamp renders the START_TIMESTAMP of a monitor, which is
+// either IMMEDIATELY or a timestamp literal.
+func renderStartTimestamp(start string) string {
+	if strings.ToUpper(start) == "IMMEDIATELY" {
+		return "IMMEDIATELY"
+	}
+	return snowsql.Literal(start)
+}
+
+// monitorTimestampLayouts are the layouts of the timestamps of a monitor,
+// those with a time zone first. SHOW RESOURCE MONITORS returns the first.
+var monitorTimestampLayouts = []string{
+	"2006-01-02 15:04:05.000 -0700",
+	"2006-01-02 15:04:05 -0700",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseMonitorTimestamp parses a timestamp of a monitor. A timestamp without a
+// time zone is in the time zone of the account, which is not known, it is
+// parsed as UTC and zoned is false.
+func parseMonitorTimestamp(s string) (t time.Time, zoned bool, err error) {
+	for i, layout := range monitorTimestampLayouts {
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, i < 3, nil
+		}
+	}
+	return t, false, fmt.Errorf("Could not parse resource monitor timestamp %q", s)
+}
+
+/*
+readMonitorTimestamp returns what to read back for a timestamp configured as
+configured which SHOW RESOURCE MONITORS shows as shown. The configured value is
+kept if it names the same time, with a time zone the same instant, without the
+same wall clock time in the time zone of the account. Otherwise the timestamp
+was changed outside of Terraform and shown is read back.
+*/
+func readMonitorTimestamp(configured string, shown string) (string, error) {
+	if shown == "" {
+		return "", nil
+	}
+	s, _, err := parseMonitorTimestamp(shown)
+	if err != nil {
+		return "", err
+	}
+	if c, zoned, err := parseMonitorTimestamp(configured); err == nil {
+		if zoned && c.Equal(s) {
+			return configured, nil
+		}
+		wall := time.Date(s.Year(), s.Month(), s.Day(), s.Hour(), s.Minute(), s.Second(), s.Nanosecond(), time.UTC)
+		if !zoned && c.Equal(wall) {
+			return configured, nil
+		}
+	}
+	return shown, nil
+}
+
+// readMonitorStart returns what to read back for the start_timestamp of a
+// monitor created at created. A monitor without a start or starting
+// IMMEDIATELY shows the time it was created or altered as its start, which is
+// not drift.
+func readMonitorStart(configured string, shown string, created time.Time) (string, error) {
+	if strings.ToUpper(configured) == "IMMEDIATELY" {
+		return configured, nil
+	}
+	if configured == "" && shown != "" {
+		s, _, err := parseMonitorTimestamp(shown)
+		if err != nil {
+			return "", err
+		}
+		if d := s.Sub(created); d > -time.Minute && d < time.Minute {
+			return "", nil
+		}
+	}
+	return readMonitorTimestamp(configured, shown)
+}
+
+func resourceSnowflakeResourceMonitorCreate(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	name := canonicalIdentifier(d.Get("name").(string))
+
+	b := snowsql.NewCreateBuilder("RESOURCE MONITOR", identifier(name))
+	if quota, ok := d.GetOk("credit_quota"); ok {
+		b.SetInt("CREDIT_QUOTA", quota.(int))
+	}
+	b.SetRaw("FREQUENCY", strings.ToUpper(d.Get("frequency").(string)))
+	if start := d.Get("start_timestamp").(string); start != "" {
+		b.SetRaw("START_TIMESTAMP", renderStartTimestamp(start))
+	}
+	b.SetString("END_TIMESTAMP", d.Get("end_timestamp").(string))
+	if users := d.Get("notify_users").(*schema.Set); users.Len() > 0 {
+		b.SetRaw("NOTIFY_USERS", renderNotifyUsers(users))
+	}
+	b.Clause(renderTriggers(d.Get("trigger").(*schema.Set)))
+	if _, err = db.Exec(b.Statement()); err != nil {
+		db.Close()
+		return err
+	}
+	// The monitor exists, the assignments need its ID and if they fail it
+	// is kept to be replaced.
+	d.SetId(name)
+	err = setResourceMonitorAssignments(db, d)
+	return createThenRead(d, meta, db, name, err, resourceSnowflakeResourceMonitorRead)
+}
+
+// setResourceMonitorAssignments assigns the monitor to the account and the
+// warehouses it was added to, and takes it away from those it was removed
+// from.
+func setResourceMonitorAssignments(db Client, d *schema.ResourceData) error {
+	monitor := identifier(d.Id())
+	if d.HasChange("set_for_account") {
+		statement := "ALTER ACCOUNT UNSET RESOURCE_MONITOR"
+		if d.Get("set_for_account").(bool) {
+			statement = fmt.Sprintf("ALTER ACCOUNT SET RESOURCE_MONITOR = %s", monitor)
+		}
+		if _, err := db.Exec(statement); err != nil {
+			return err
+		}
+	}
+	o, n := d.GetChange("warehouses")
+	removed := o.(*schema.Set).Difference(n.(*schema.Set))
+	added := n.(*schema.Set).Difference(o.(*schema.Set))
+	for _, w := range removed.List() {
+		b := snowsql.NewAlterBuilder("WAREHOUSE", identifier(w.(string))).Unset("RESOURCE_MONITOR")
+		err := db.ExecAll(b.Statements())
+		// A warehouse dropped outside of Terraform is no longer monitored
+		// anyway.
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+	for _, w := range added.List() {
+		b := snowsql.NewAlterBuilder("WAREHOUSE", identifier(w.(string))).SetRaw("RESOURCE_MONITOR", monitor)
+		if err := db.ExecAll(b.Statements()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseThresholds parses the thresholds SHOW RESOURCE MONITORS returns for an
+// action, e.g. 50%,100%.
+func parseThresholds(thresholds string) ([]int, error) {
+	var percents []int
+	for _, t := range strings.Split(thresholds, ",") {
+		t = strings.TrimSuffix(strings.TrimSpace(t), "%")
+		if t == "" {
+			continue
+		}
+		percent, err := strconv.Atoi(t)
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse resource monitor threshold %q: %v", thresholds, err)
+		}
+		percents = append(percents, percent)
+	}
+	return percents, nil
+}
+
+func resourceSnowflakeResourceMonitorRead(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	name := d.Id()
+	// The state of an imported monitor has nothing but the ID yet.
+	importing := d.Get("name").(string) == ""
+	r, err := db.ShowResourceMonitor(name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	d.Set("name", storedIdentifier(r.Name))
+	if r.CreditQuota != "" {
+		quota, err := strconv.ParseFloat(r.CreditQuota, 64)
+		if err != nil {
+			return err
+		}
+		d.Set("credit_quota", int(quota))
+	} else {
+		d.Set("credit_quota", 0)
+	}
+	d.Set("frequency", r.Frequency)
+	start, err := readMonitorStart(d.Get("start_timestamp").(string), r.StartTime, r.CreatedOn)
+	if err != nil {
+		return err
+	}
+	d.Set("start_timestamp", start)
+	end, err := readMonitorTimestamp(d.Get("end_timestamp").(string), r.EndTime)
+	if err != nil {
+		return err
+	}
+	d.Set("end_timestamp", end)
+
+	var users []string
+	for _, u := range strings.Split(r.NotifyUsers, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			users = append(users, storedIdentifier(u))
+		}
+	}
+	d.Set("notify_users", users)
+
+	var triggers []map[string]interface{}
+	actions := map[string]string{
+		"NOTIFY":            r.NotifyAt,
+		"SUSPEND":           r.SuspendAt,
+		"SUSPEND_IMMEDIATE": r.SuspendImmediatelyAt,
+	}
+	for _, action := range resourceMonitorActions {
+		thresholds, err := parseThresholds(actions[action])
+		if err != nil {
+			return err
+		}
+		for _, t := range thresholds {
+			triggers = append(triggers, map[string]interface{}{"threshold": t, "action": action})
+		}
+	}
+	d.Set("trigger", triggers)
+
+	d.Set("set_for_account", r.Level == "ACCOUNT")
+	warehouses, err := db.ShowWarehouses()
+	if err != nil {
+		return err
+	}
+	// Only the warehouses the monitor lists are read back, a
+	// snowflake_warehouse may have been assigned the monitor with its
+	// resource_monitor. An imported monitor takes all of them.
+	listed := map[string]bool{}
+	for _, w := range d.Get("warehouses").(*schema.Set).List() {
+		listed[canonicalIdentifier(w.(string))] = true
+	}
+	var monitored []string
+	for _, w := range warehouses {
+		name := storedIdentifier(w.Name)
+		if w.ResourceMonitor == r.Name && (importing || listed[name]) {
+			monitored = append(monitored, name)
+		}
+	}
+	d.Set("warehouses", monitored)
+	return nil
+}
+
+func resourceSnowflakeResourceMonitorUpdate(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	name := d.Id()
+	exists, err := db.ObjectExists("resource monitors", name, inAccount)
+	if err != nil {
+		return err
+	}
+	if exists == false {
+		return newNotFoundError("Resource monitor", d.Id())
+	}
+	d.Partial(true)
+	// The schedule is set at once, Snowflake checks the frequency and
+	// timestamps against each other.
+	if d.HasChange("credit_quota") || d.HasChange("frequency") || d.HasChange("start_timestamp") || d.HasChange("end_timestamp") {
+		b := snowsql.NewAlterBuilder("RESOURCE MONITOR", identifier(name))
+		if quota, ok := d.GetOk("credit_quota"); ok {
+			b.SetInt("CREDIT_QUOTA", quota.(int))
+		}
+		b.SetRaw("FREQUENCY", strings.ToUpper(d.Get("frequency").(string)))
+		if start := d.Get("start_timestamp").(string); start != "" {
+			b.SetRaw("START_TIMESTAMP", renderStartTimestamp(start))
+		}
+		if d.HasChange("end_timestamp") {
+			b.SetString("END_TIMESTAMP", d.Get("end_timestamp").(string))
+		}
+		if err = db.ExecAll(b.Statements()); err != nil {
+			return err
+		}
+		d.SetPartial("credit_quota")
+		d.SetPartial("frequency")
+		d.SetPartial("start_timestamp")
+		d.SetPartial("end_timestamp")
+	}
+	if d.HasChange("notify_users") {
+		b := snowsql.NewAlterBuilder("RESOURCE MONITOR", identifier(name)).
+			SetRaw("NOTIFY_USERS", renderNotifyUsers(d.Get("notify_users").(*schema.Set)))
+		if err = db.ExecAll(b.Statements()); err != nil {
+			return err
+		}
+		d.SetPartial("notify_users")
+	}
+	// Removing all triggers replaces the monitor, see CustomizeDiff.
+	if d.HasChange("trigger") {
+		statement := snowsql.NewAlterBuilder("RESOURCE MONITOR", identifier(name)).
+			Clause(renderTriggers(d.Get("trigger").(*schema.Set)))
+		if _, err = db.Exec(statement); err != nil {
+			return err
+		}
+		d.SetPartial("trigger")
+	}
+	if err = setResourceMonitorAssignments(db, d); err != nil {
+		return err
+	}
+	d.Partial(false)
+	return nil
+}
+
+func resourceSnowflakeResourceMonitorDelete(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	name := d.Id()
+	exists, err := db.ObjectExists("resource monitors", name, inAccount)
+	if err != nil {
+		return err
+	}
+	if exists == false {
+		// Already dropped outside of Terraform.
+		return nil
+	}
+	// Dropping the monitor takes it away from the account and warehouses.
+	statement := snowsql.Drop("RESOURCE MONITOR", identifier(name))
+	if _, err = db.Exec(statement); err != nil {
+		return err
+	}
+	return nil
+}
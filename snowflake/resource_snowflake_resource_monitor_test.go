@@ -0,0 +1,383 @@
+package snowflake
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func testAccShowResourceMonitor(c Client, id string) error {
+	_, err := c.ShowResourceMonitor(id)
+	return err
+}
+
+// testAccCheckWarehouseMonitor checks the resource monitor SHOW WAREHOUSES
+// returns for warehouse, null for none.
+func testAccCheckWarehouseMonitor(warehouse string, monitor string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		return testAccWithClient(func(c Client) error {
+			r, err := c.ShowWarehouse(warehouse)
+			if err != nil {
+				return err
+			}
+			if r.ResourceMonitor != monitor {
+				return fmt.Errorf("Warehouse %s has resource monitor %s, expected %s", warehouse, r.ResourceMonitor, monitor)
+			}
+			return nil
+		})
+	}
+}
+
+func TestAccResourceMonitor(t *testing.T) {
+	name := testAccName()
+	user := testAccName()
+	warehouse1 := testAccName()
+	warehouse2 := testAccName()
+	// The warehouses are snowflake_warehouse resources without a
+	// resource_monitor, the plan after each step must be empty.
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_resource_monitor", testAccShowResourceMonitor),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceMonitorConfig(name, user, warehouse1, warehouse2, "warehouse1", `
+  credit_quota = 100
+
+  trigger {
+    threshold = 50
+    action    = "NOTIFY"
+  }
+
+  trigger {
+    threshold = 100
+    action    = "SUSPEND"
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_resource_monitor.test", testAccShowResourceMonitor),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "id", name),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "credit_quota", "100"),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "frequency", "MONTHLY"),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "trigger.#", "2"),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "warehouses.#", "1"),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "set_for_account", "false"),
+					testAccCheckWarehouseMonitor(warehouse1, name),
+					testAccCheckWarehouseMonitor(warehouse2, "null"),
+				),
+			},
+			// Altered in place and moved to the other warehouse.
+			{
+				Config: testAccResourceMonitorConfig(name, user, warehouse1, warehouse2, "warehouse2", `
+  credit_quota    = 200
+  frequency       = "weekly"
+  start_timestamp = "IMMEDIATELY"
+  end_timestamp   = "2040-01-01 00:00"
+  notify_users    = ["${snowflake_user.test.name}"]
+
+  trigger {
+    threshold = 75
+    action    = "NOTIFY"
+  }
+
+  trigger {
+    threshold = 110
+    action    = "SUSPEND_IMMEDIATE"
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_resource_monitor.test", testAccShowResourceMonitor),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "credit_quota", "200"),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "frequency", "WEEKLY"),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "notify_users.#", "1"),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "trigger.#", "2"),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "start_timestamp", "IMMEDIATELY"),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "end_timestamp", "2040-01-01 00:00"),
+					testAccCheckWarehouseMonitor(warehouse1, "null"),
+					testAccCheckWarehouseMonitor(warehouse2, name),
+				),
+			},
+			// The end was moved outside of Terraform.
+			{
+				Config: testAccResourceMonitorConfig(name, user, warehouse1, warehouse2, "warehouse2", `
+  credit_quota    = 200
+  frequency       = "weekly"
+  start_timestamp = "IMMEDIATELY"
+  end_timestamp   = "2040-01-01 00:00"
+  notify_users    = ["${snowflake_user.test.name}"]
+
+  trigger {
+    threshold = 75
+    action    = "NOTIFY"
+  }
+
+  trigger {
+    threshold = 110
+    action    = "SUSPEND_IMMEDIATE"
+  }
+`),
+				Check:              testAccExec(fmt.Sprintf("ALTER RESOURCE MONITOR %s SET END_TIMESTAMP = '2041-01-01 00:00'", name)),
+				ExpectNonEmptyPlan: true,
+			},
+			// Replaced as the triggers are gone.
+			{
+				Config: testAccResourceMonitorConfig(name, user, warehouse1, warehouse2, "warehouse2", `
+  credit_quota = 200
+  frequency    = "weekly"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_resource_monitor.test", testAccShowResourceMonitor),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "trigger.#", "0"),
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "notify_users.#", "0"),
+					testAccCheckWarehouseMonitor(warehouse2, name),
+				),
+			},
+			{
+				ResourceName:      "snowflake_resource_monitor.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Dropped outside of Terraform.
+			{
+				Config: testAccResourceMonitorConfig(name, user, warehouse1, warehouse2, "warehouse2", `
+  credit_quota = 200
+  frequency    = "weekly"
+`),
+				Check:              testAccExec("DROP RESOURCE MONITOR " + name),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// The account can have only one resource monitor, the test would take over
+// the one of a real account.
+func TestAccResourceMonitorAccount(t *testing.T) {
+	if testAccUseAccount() {
+		t.Skip("Assigning a resource monitor to the account would replace the account's own")
+	}
+	name := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_resource_monitor", testAccShowResourceMonitor),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceMonitorAccountConfig(name, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "set_for_account", "true"),
+				),
+			},
+			{
+				Config: testAccResourceMonitorAccountConfig(name, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("snowflake_resource_monitor.test", "set_for_account", "false"),
+				),
+			},
+		},
+	})
+}
+
+// testAccResourceMonitorConfig returns the configuration of two warehouses
+// and a monitor of the warehouse resource monitored, with the attributes of
+// monitor.
+func testAccResourceMonitorConfig(name string, user string, warehouse1 string, warehouse2 string, monitored string, monitor string) string {
+	return fmt.Sprintf(`
+resource "snowflake_user" "test" {
+  name       = "%s"
+  login_name = "%s"
+  email      = "monitor@example.com"
+}
+
+resource "snowflake_warehouse" "warehouse1" {
+  name                = "%s"
+  initially_suspended = true
+}
+
+resource "snowflake_warehouse" "warehouse2" {
+  name                = "%s"
+  initially_suspended = true
+}
+
+resource "snowflake_resource_monitor" "test" {
+  name       = "%s"
+  warehouses = ["${snowflake_warehouse.%s.name}"]
+%s}
+`, user, user, warehouse1, warehouse2, name, monitored, monitor)
+}
+
+func testAccResourceMonitorAccountConfig(name string, setForAccount bool) string {
+	return fmt.Sprintf(`
+resource "snowflake_resource_monitor" "test" {
+  name            = "%s"
+  credit_quota    = 10
+  set_for_account = %t
+}
+`, name, setForAccount)
+}
+
+func TestResourceMonitorFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeResourceMonitor()
+	c.rows["ShowResourceMonitor M"] = showResourceMonitorRow{
+		Name:        "M",
+		CreditQuota: "10.00",
+		Frequency:   "MONTHLY",
+		Level:       "WAREHOUSE",
+		NotifyAt:    "50%",
+		SuspendAt:   "100%",
+	}
+	c.rows["ShowWarehouses"] = []showWarehouseRow{{Name: "W1", ResourceMonitor: "M"}, {Name: "W2", ResourceMonitor: "null"}}
+	c.objects["resource monitors M IN ACCOUNT"] = true
+	raw := map[string]interface{}{
+		"name":         "m",
+		"credit_quota": 10,
+		"frequency":    "monthly",
+		"trigger": []interface{}{
+			map[string]interface{}{"threshold": 100, "action": "SUSPEND"},
+			map[string]interface{}{"threshold": 50, "action": "NOTIFY"},
+		},
+		"warehouses": []interface{}{"w1"},
+	}
+
+	state, err := testFakeApply(t, r, meta, nil, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c,
+		"CREATE RESOURCE MONITOR M CREDIT_QUOTA = 10 FREQUENCY = MONTHLY TRIGGERS ON 50 PERCENT DO NOTIFY ON 100 PERCENT DO SUSPEND",
+		"ALTER WAREHOUSE W1 SET RESOURCE_MONITOR = M",
+	)
+	testCheckAttributes(t, state, map[string]string{
+		"id":              "M",
+		"credit_quota":    "10",
+		"frequency":       "MONTHLY",
+		"trigger.#":       "2",
+		"warehouses.#":    "1",
+		"set_for_account": "false",
+	})
+
+	raw["credit_quota"] = 20
+	raw["notify_users"] = []interface{}{"b", "a"}
+	raw["warehouses"] = []interface{}{"W2"}
+	if state, err = testFakeApply(t, r, meta, state, raw); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c,
+		"ALTER RESOURCE MONITOR M SET CREDIT_QUOTA = 20 FREQUENCY = MONTHLY",
+		"ALTER RESOURCE MONITOR M SET NOTIFY_USERS = (A, B)",
+		"ALTER WAREHOUSE W1 UNSET RESOURCE_MONITOR",
+		"ALTER WAREHOUSE W2 SET RESOURCE_MONITOR = M",
+	)
+
+	imported, err := testFakeImport(t, r, meta, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{"id": "M", "credit_quota": "10", "trigger.#": "2", "warehouses.#": "1"})
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "DROP RESOURCE MONITOR M")
+}
+
+// Snowflake has no default frequency to unset to but MONTHLY, and no way to
+// remove the credit quota but replacing the monitor.
+func TestResourceMonitorFakeClientUnset(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeResourceMonitor()
+	c.objects["resource monitors M IN ACCOUNT"] = true
+	state := testFakeState(t, r, "M", map[string]interface{}{
+		"name":         "M",
+		"credit_quota": 10,
+		"frequency":    "WEEKLY",
+	})
+
+	raw := map[string]interface{}{"name": "M", "credit_quota": 10}
+	if _, err := testFakeApply(t, r, meta, state, raw); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "ALTER RESOURCE MONITOR M SET CREDIT_QUOTA = 10 FREQUENCY = MONTHLY")
+
+	cfg, err := config.NewRawConfig(map[string]interface{}{"name": "M", "frequency": "WEEKLY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfig(cfg), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.RequiresNew() {
+		t.Fatal("removing the credit quota does not replace the monitor")
+	}
+}
+
+func TestResourceMonitorAssignmentsConflict(t *testing.T) {
+	cfg, err := config.NewRawConfig(map[string]interface{}{
+		"name":            "M",
+		"set_for_account": true,
+		"warehouses":      []interface{}{"W"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errs := resourceSnowflakeResourceMonitor().Validate(terraform.NewResourceConfig(cfg)); len(errs) == 0 {
+		t.Fatal("a monitor of both the account and warehouses is valid")
+	}
+}
+
+func TestReadMonitorTimestamp(t *testing.T) {
+	cases := []struct {
+		configured string
+		shown      string
+		want       string
+	}{
+		{"", "", ""},
+		{"2040-01-01", "", ""},
+		{"", "2040-01-01 00:00:00.000 -0800", "2040-01-01 00:00:00.000 -0800"},
+		// The same wall clock time in the time zone of the account.
+		{"2040-01-01", "2040-01-01 00:00:00.000 -0800", "2040-01-01"},
+		{"2040-01-01 12:30", "2040-01-01 12:30:00.000 +0100", "2040-01-01 12:30"},
+		{"2040-01-01 12:30", "2040-01-01T12:30:00+01:00", "2040-01-01 12:30"},
+		// The same instant.
+		{"2040-01-01T08:00:00Z", "2040-01-01 00:00:00.000 -0800", "2040-01-01T08:00:00Z"},
+		// Changed outside of Terraform.
+		{"2040-01-01", "2041-01-01 00:00:00.000 -0800", "2041-01-01 00:00:00.000 -0800"},
+		{"2040-01-01T00:00:00Z", "2040-01-01 00:00:00.000 -0800", "2040-01-01 00:00:00.000 -0800"},
+		{"tomorrow", "2040-01-01 00:00:00.000 -0800", "2040-01-01 00:00:00.000 -0800"},
+	}
+	for _, c := range cases {
+		got, err := readMonitorTimestamp(c.configured, c.shown)
+		if err != nil {
+			t.Errorf("%q, %q: %v", c.configured, c.shown, err)
+		} else if got != c.want {
+			t.Errorf("%q, %q: got %q, want %q", c.configured, c.shown, got, c.want)
+		}
+	}
+	if _, err := readMonitorTimestamp("", "soon"); err == nil {
+		t.Error("reading an unparsable timestamp succeeded")
+	}
+
+	created := time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC)
+	starts := []struct {
+		configured string
+		shown      string
+		want       string
+	}{
+		{"IMMEDIATELY", "2031-01-01 00:00:00.000 -0800", "IMMEDIATELY"},
+		{"", "2030-01-01 00:00:00.123 -0800", ""},
+		{"", "2031-01-01 00:00:00.000 -0800", "2031-01-01 00:00:00.000 -0800"},
+		{"2031-01-01", "2031-01-01 00:00:00.000 -0800", "2031-01-01"},
+	}
+	for _, c := range starts {
+		got, err := readMonitorStart(c.configured, c.shown, created)
+		if err != nil {
+			t.Errorf("start %q, %q: %v", c.configured, c.shown, err)
+		} else if got != c.want {
+			t.Errorf("start %q, %q: got %q, want %q", c.configured, c.shown, got, c.want)
+		}
+	}
+}
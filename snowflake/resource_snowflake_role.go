@@ -1,32 +1,29 @@
 package snowflake
 
 import (
-	"database/sql"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
 )
 
 func resourceSnowflakeRole() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceSnowflakeRoleCreate,
 		Read:   resourceSnowflakeRoleRead,
 		Update: resourceSnowflakeRoleUpdate,
 		Delete: resourceSnowflakeRoleDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				d.SetId(strings.ToUpper(d.Id()))
-				return []*schema.ResourceData{d}, nil
-			},
+			State: importStateID("name"),
 		},
+		SchemaVersion: idSchemaVersion,
 		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    identifierStateFunc,
+				ValidateFunc: validateIdentifier,
 			},
 			"comment": {
 				Type:     schema.TypeString,
@@ -34,20 +31,23 @@ func resourceSnowflakeRole() *schema.Resource {
 			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(r, "name")
+	return r
 }
 
 func resourceSnowflakeRoleCreate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	name := strings.ToUpper(d.Get("name").(string))
-	comment := d.Get("comment").(string)
-
-	statement := fmt.Sprintf("CREATE ROLE %v", name)
-
-	if comment != "" {
-		statement += fmt.Sprintf(" COMMENT = '%v'", comment)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
 	}
+	defer db.Close()
+	name := canonicalIdentifier(d.Get("name").(string))
+
+	statement := snowsql.NewCreateBuilder("ROLE", identifier(name)).
+		SetString("COMMENT", d.Get("comment").(string)).
+		Statement()
 
-	_, err := db.Exec(statement)
+	_, err = db.Exec(statement)
 	if err != nil {
 		return err
 	}
@@ -58,57 +58,60 @@ func resourceSnowflakeRoleCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceSnowflakeRoleRead(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	name := d.Id()
-	showRoleRow, err := showRole(db, name)
+	db, err := newClient(meta, d)
 	if err != nil {
 		return err
 	}
-	d.Set("name", showRoleRow.name)
-	d.Set("comment", showRoleRow.comment)
+	defer db.Close()
+	name := d.Id()
+	showRoleRow, err := db.ShowRole(name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	d.Set("name", storedIdentifier(showRoleRow.Name))
+	d.Set("comment", showRoleRow.Comment)
 
 	return nil
 }
 
 func resourceSnowflakeRoleUpdate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	name := d.Id()
-	exists, err := sqlObjExists(db, "roles", name, "account")
+	exists, err := db.ObjectExists("roles", name, inAccount)
 
 	if err != nil {
 		return err
 	}
 
 	if exists == false {
-		return fmt.Errorf("Role %s does not exist", d.Id())
+		return newNotFoundError("Role", d.Id())
 	}
 
 	d.Partial(true)
 	if d.HasChange("name") {
 		// check that the rename target does not exist
-		exists, err := sqlObjExists(db, "roles", d.Get("name").(string), "account")
+		exists, err := db.ObjectExists("roles", d.Get("name").(string), inAccount)
 		if err != nil {
 			return err
 		}
 		if exists == true {
 			return fmt.Errorf("Cannot rename %v to %v, %v already exists", d.Id(), d.Get("name"), d.Get("name"))
 		}
-		statement := fmt.Sprintf("ALTER ROLE %v RENAME TO %v", d.Id(), d.Get("name"))
-		if _, err := db.Exec(statement); err != nil {
+		statement := snowsql.NewAlterBuilder("ROLE", identifier(d.Id())).Rename(identifier(d.Get("name").(string)))
+		if _, err = db.Exec(statement); err != nil {
 			return err
 		}
 		d.SetPartial("name")
-		d.SetId(d.Get("name").(string))
+		d.SetId(canonicalIdentifier(d.Get("name").(string)))
 	}
 
 	if d.HasChange("comment") {
-		var statement string
-		if d.Get("comment") == "" {
-			statement = fmt.Sprintf("ALTER ROLE %v UNSET COMMENT", d.Id())
-		} else {
-			statement = fmt.Sprintf("ALTER ROLE %v SET COMMENT = '%v'", d.Id(), d.Get("comment"))
-		}
-		if _, err := db.Exec(statement); err != nil {
+		b := snowsql.NewAlterBuilder("ROLE", identifier(d.Id())).SetString("COMMENT", d.Get("comment").(string))
+		if err = db.ExecAll(b.Statements()); err != nil {
 			return err
 		}
 		d.SetPartial("comment")
@@ -118,17 +121,22 @@ func resourceSnowflakeRoleUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceSnowflakeRoleDelete(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	name := d.Id()
-	exists, err := sqlObjExists(db, "roles", name, "account")
+	exists, err := db.ObjectExists("roles", name, inAccount)
 	if err != nil {
 		return err
 	}
 	if exists == false {
-		return fmt.Errorf("ROLE %s does not exist", d.Id())
+		// Already dropped outside of Terraform.
+		return nil
 	}
-	statement := fmt.Sprintf("DROP ROLE %v", d.Get("name"))
-	if _, err := db.Exec(statement); err != nil {
+	statement := snowsql.Drop("ROLE", identifier(name))
+	if _, err = db.Exec(statement); err != nil {
 		return err
 	}
 	return nil
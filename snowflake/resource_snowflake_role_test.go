@@ -0,0 +1,98 @@
+package snowflake
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func testAccShowRole(c Client, id string) error {
+	_, err := c.ShowRole(id)
+	return err
+}
+
+func TestAccRole(t *testing.T) {
+	name := testAccName()
+	newName := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_role", testAccShowRole),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRoleConfig(name, "created"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_role.test", testAccShowRole),
+					resource.TestCheckResourceAttr("snowflake_role.test", "id", name),
+					resource.TestCheckResourceAttr("snowflake_role.test", "name", name),
+					resource.TestCheckResourceAttr("snowflake_role.test", "comment", "created"),
+				),
+			},
+			// Renamed and altered in place.
+			{
+				Config: testAccRoleConfig(newName, "updated"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_role.test", testAccShowRole),
+					resource.TestCheckResourceAttr("snowflake_role.test", "id", newName),
+					resource.TestCheckResourceAttr("snowflake_role.test", "comment", "updated"),
+				),
+			},
+			{
+				ResourceName:      "snowflake_role.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Dropped outside of Terraform.
+			{
+				Config:             testAccRoleConfig(newName, "updated"),
+				Check:              testAccExec("DROP ROLE " + newName),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccRoleConfig(name string, comment string) string {
+	return fmt.Sprintf(`
+resource "snowflake_role" "test" {
+  name    = "%s"
+  comment = "%s"
+}
+`, name, comment)
+}
+
+func TestRoleFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeRole()
+	c.rows["ShowRole R"] = showRoleRow{Name: "R", Comment: "c"}
+	c.objects["roles R IN ACCOUNT"] = true
+
+	state, err := testFakeApply(t, r, meta, nil, map[string]interface{}{"name": "r", "comment": "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "CREATE ROLE R COMMENT = 'c'")
+	testCheckAttributes(t, state, map[string]string{"id": "R", "name": "R", "comment": "c"})
+
+	if state, err = testFakeApply(t, r, meta, state, map[string]interface{}{"name": "R"}); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "ALTER ROLE R UNSET COMMENT")
+
+	imported, err := testFakeImport(t, r, meta, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{"id": "R", "name": "R", "comment": "c"})
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "DROP ROLE R")
+
+	delete(c.objects, "roles R IN ACCOUNT")
+	if _, err = testFakeApply(t, r, meta, state, map[string]interface{}{"name": "R", "comment": "c"}); !isNotFound(err) {
+		t.Fatalf("updating a dropped role returned %v, want a notFoundError", err)
+	}
+	testCheckStatements(t, c)
+}
@@ -1,41 +1,39 @@
 package snowflake
 
 import (
-	"database/sql"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
 )
 
 func resourceSnowflakeSchema() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceSnowflakeSchemaCreate,
 		Read:   resourceSnowflakeSchemaRead,
 		Update: resourceSnowflakeSchemaUpdate,
 		Delete: resourceSnowflakeSchemaDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				d.SetId(strings.ToUpper(d.Id()))
-				return []*schema.ResourceData{d}, nil
-			},
+			State: importStateID("database", "name"),
 		},
 
+		SchemaVersion: idSchemaVersion,
 		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    identifierStateFunc,
+				ValidateFunc: validateIdentifier,
 			},
 			"database": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				StateFunc:    identifierStateFunc,
+				ValidateFunc: validateIdentifier,
 			},
 			"owner": {
 				Type:     schema.TypeString,
@@ -51,6 +49,7 @@ func resourceSnowflakeSchema() *schema.Resource {
 			"transient": {
 				Type:     schema.TypeBool,
 				Optional: true,
+				ForceNew: true,
 			},
 			"retention_time": {
 				Type:     schema.TypeInt,
@@ -59,48 +58,55 @@ func resourceSnowflakeSchema() *schema.Resource {
 			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(r, "database", "name")
+	return r
 }
 
 func resourceSnowflakeSchemaCreate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	resourceID := strings.ToUpper(fmt.Sprintf("%s.%s", d.Get("database"), d.Get("name")))
-	statement := fmt.Sprintf("CREATE SCHEMA %s DATA_RETENTION_TIME_IN_DAYS = %d", resourceID, d.Get("retention_time"))
-	if d.Get("transient").(bool) == true {
-		statement = fmt.Sprintf("CREATE TRANSIENT SCHEMA %s DATA_RETENTION_TIME_IN_DAYS = %d", resourceID, d.Get("retention_time"))
-		d.Set("transient", true)
-	} else {
-		d.Set("transient", false)
-	}
-	if d.Get("comment") != "" {
-		statement += fmt.Sprintf(" COMMENT = '%s'", d.Get("comment"))
-	}
-	_, err := db.Exec(statement)
+	db, err := newClient(meta, d)
 	if err != nil {
 		return err
 	}
-	d.SetId(resourceID)
-	return nil
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+	resourceID := encodeID(database, name)
+	transient := d.Get("transient").(bool)
+	statement := snowsql.NewCreateBuilder("SCHEMA", qualifiedName(database, name)).
+		Modifier("TRANSIENT", transient).
+		SetInt("DATA_RETENTION_TIME_IN_DAYS", d.Get("retention_time").(int)).
+		SetString("COMMENT", d.Get("comment").(string)).
+		Statement()
+	d.Set("transient", transient)
+	_, err = db.Exec(statement)
+	return createThenRead(d, meta, db, resourceID, err, resourceSnowflakeSchemaRead)
 }
 
 func resourceSnowflakeSchemaRead(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	s := strings.Split(d.Id(), ".")
-	database, schema := s[0], s[1]
-	schemaInfo, err := showSchema(db, database, schema)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "name")
 	if err != nil {
 		return err
 	}
-	d.Set("name", schemaInfo.name)
-	d.Set("database", schemaInfo.databaseName)
-	d.Set("owner", schemaInfo.owner)
-	d.Set("comment", schemaInfo.comment)
-	if schemaInfo.options == "TRANSIENT" {
+	database, schema := id[0], id[1]
+	schemaInfo, err := db.ShowSchema(database, schema)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	d.Set("name", storedIdentifier(schemaInfo.Name))
+	d.Set("database", storedIdentifier(schemaInfo.DatabaseName))
+	d.Set("owner", schemaInfo.Owner)
+	d.Set("comment", schemaInfo.Comment)
+	if schemaInfo.Options == "TRANSIENT" {
 		d.Set("transient", true)
 	} else {
 		d.Set("transient", false)
 	}
-	if schemaInfo.retentionTime != "" {
-		retentionTime, err := strconv.Atoi(schemaInfo.retentionTime)
+	if schemaInfo.RetentionTime != "" {
+		retentionTime, err := strconv.Atoi(schemaInfo.RetentionTime)
 		if err != nil {
 			return err
 		}
@@ -111,40 +117,48 @@ func resourceSnowflakeSchemaRead(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceSnowflakeSchemaUpdate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	s := strings.Split(d.Id(), ".")
-	database, name := s[0], s[1]
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "name")
+	if err != nil {
+		return err
+	}
+	database, name := id[0], id[1]
 	// Rather than issue a single alter database statement for all possible
 	// changes issue an alter for each possible thing that has changed. Enable
 	// partial mode.
 	d.Partial(true)
 	if d.HasChange("name") {
 		// check that the rename target does not exist
-		exists, err := sqlObjExists(db, "schemas", name, database)
+		exists, err := db.ObjectExists("schemas", d.Get("name").(string), inDatabase(database))
 		if err != nil {
 			return err
 		}
 		if exists == true {
-			return fmt.Errorf("Cannot rename %s to %s.%s, %s.%s already exists", d.Id(), database, name, database, d.Get("name"))
+			return fmt.Errorf("Cannot rename %s to %s.%s, %s.%s already exists", d.Id(), database, d.Get("name"), database, d.Get("name"))
 		}
-		statement := fmt.Sprintf("ALTER SCHEMA %s RENAME TO %s.%s", d.Id(), database, d.Get("name"))
-		if _, err := db.Exec(statement); err != nil {
+		statement := snowsql.NewAlterBuilder("SCHEMA", qualifiedName(database, name)).Rename(qualifiedName(database, d.Get("name").(string)))
+		if _, err = db.Exec(statement); err != nil {
 			return err
 		}
 		d.SetPartial("name")
-		newResourceID := fmt.Sprintf("%s.%s", database, d.Get("name"))
+		newResourceID := encodeID(database, d.Get("name").(string))
 		d.SetId(newResourceID)
+		name = d.Get("name").(string)
 	}
 	if d.HasChange("comment") {
-		statement := fmt.Sprintf("ALTER SCHEMA %s SET COMMENT = '%s'", d.Id(), d.Get("comment"))
-		if _, err := db.Exec(statement); err != nil {
+		b := snowsql.NewAlterBuilder("SCHEMA", qualifiedName(database, name)).SetString("COMMENT", d.Get("comment").(string))
+		if err = db.ExecAll(b.Statements()); err != nil {
 			return err
 		}
 		d.SetPartial("comment")
 	}
 	if d.HasChange("retention_time") {
-		statement := fmt.Sprintf("ALTER SCHEMA %s SET DATA_RETENTION_TIME_IN_DAYS = %d", d.Id(), d.Get("retention_time"))
-		if _, err := db.Exec(statement); err != nil {
+		b := snowsql.NewAlterBuilder("SCHEMA", qualifiedName(database, name)).SetInt("DATA_RETENTION_TIME_IN_DAYS", d.Get("retention_time").(int))
+		if err = db.ExecAll(b.Statements()); err != nil {
 			return err
 		}
 		d.SetPartial("retention_time")
@@ -154,18 +168,26 @@ func resourceSnowflakeSchemaUpdate(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceSnowflakeSchemaDelete(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	s := strings.Split(d.Id(), ".")
-	database, name := s[0], s[1]
-	exists, err := sqlObjExists(db, "schemas", name, database)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "name")
+	if err != nil {
+		return err
+	}
+	database, name := id[0], id[1]
+	exists, err := db.ObjectExists("schemas", name, inDatabase(database))
 	if err != nil {
 		return err
 	}
 	if exists == false {
-		return fmt.Errorf("Schema %s does not exist", d.Id())
+		// Already dropped outside of Terraform.
+		return nil
 	}
-	statement := fmt.Sprintf("DROP SCHEMA %s", d.Id())
-	if _, err := db.Exec(statement); err != nil {
+	statement := snowsql.Drop("SCHEMA", qualifiedName(database, name))
+	if _, err = db.Exec(statement); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,221 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func testAccShowSchema(c Client, id string) error {
+	names, err := decodeID(id, "database", "name")
+	if err != nil {
+		return err
+	}
+	_, err = c.ShowSchema(names[0], names[1])
+	return err
+}
+
+func TestAccSchema(t *testing.T) {
+	database := testAccName()
+	name := testAccName()
+	newName := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_schema", testAccShowSchema),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSchemaConfig(database, name, "created", 1, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_schema.test", testAccShowSchema),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "id", database+"."+name),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "database", database),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "name", name),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "comment", "created"),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "retention_time", "1"),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "transient", "false"),
+					resource.TestCheckResourceAttrSet("snowflake_schema.test", "owner"),
+				),
+			},
+			// Renamed and altered in place.
+			{
+				Config: testAccSchemaConfig(database, newName, "updated", 0, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_schema.test", testAccShowSchema),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "id", database+"."+newName),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "comment", "updated"),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "retention_time", "0"),
+				),
+			},
+			// Replaced.
+			{
+				Config: testAccSchemaConfig(database, newName, "updated", 0, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_schema.test", testAccShowSchema),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "transient", "true"),
+				),
+			},
+			{
+				ResourceName:      "snowflake_schema.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Dropped outside of Terraform.
+			{
+				Config:             testAccSchemaConfig(database, newName, "updated", 0, true),
+				Check:              testAccExec(fmt.Sprintf("DROP SCHEMA %s.%s", database, newName)),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccSchemaConfig(database string, name string, comment string, retentionTime int, transient bool) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_schema" "test" {
+  database       = "${snowflake_database.test.name}"
+  name           = "%s"
+  comment        = "%s"
+  retention_time = %d
+  transient      = %t
+}
+`, database, name, comment, retentionTime, transient)
+}
+
+func TestSchemaFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeSchema()
+	c.rows["ShowSchema D S"] = showSchemaRow{Name: "S", DatabaseName: "D", Owner: "SYSADMIN", Options: "TRANSIENT", RetentionTime: "0"}
+
+	state, err := testFakeApply(t, r, meta, nil, map[string]interface{}{
+		"database":       "d",
+		"name":           "s",
+		"transient":      true,
+		"retention_time": 0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "CREATE TRANSIENT SCHEMA D.S DATA_RETENTION_TIME_IN_DAYS = 0")
+	testCheckAttributes(t, state, map[string]string{
+		"id":        "D.S",
+		"database":  "D",
+		"name":      "S",
+		"owner":     "SYSADMIN",
+		"transient": "true",
+	})
+
+	state, err = testFakeApply(t, r, meta, state, map[string]interface{}{
+		"database":       "D",
+		"name":           "s2",
+		"comment":        "c",
+		"transient":      true,
+		"retention_time": 0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c,
+		"ALTER SCHEMA D.S RENAME TO D.S2",
+		"ALTER SCHEMA D.S2 SET COMMENT = 'c'",
+	)
+	testCheckAttributes(t, state, map[string]string{"id": "D.S2", "name": "S2"})
+
+	c.objects["schemas S3 IN DATABASE D"] = true
+	if _, err = testFakeApply(t, r, meta, state, map[string]interface{}{"database": "D", "name": "S3", "comment": "c", "transient": true, "retention_time": 0}); err == nil {
+		t.Fatal("renaming onto an existing schema succeeded")
+	}
+	testCheckStatements(t, c)
+
+	imported, err := testFakeImport(t, r, meta, `d."S"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{"id": "D.S", "database": "D", "name": "S", "owner": "SYSADMIN"})
+	if _, err = testFakeImport(t, r, meta, "S"); err == nil {
+		t.Fatal("importing a schema without its database succeeded")
+	}
+
+	c.objects["schemas S2 IN DATABASE D"] = true
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "DROP SCHEMA D.S2")
+}
+
+func TestSchemaDatabase(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeSchema()
+	state := testFakeState(t, r, "D.S", map[string]interface{}{"database": "D", "name": "S"})
+
+	// A schema cannot be moved to another database, it is replaced.
+	cfg, err := config.NewRawConfig(map[string]interface{}{"database": "D2", "name": "S"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfig(cfg), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || !diff.RequiresNew() {
+		t.Fatalf("moving a schema to another database does not replace it: %v", diff)
+	}
+
+	cfg, err = config.NewRawConfig(map[string]interface{}{"database": `"D`, "name": "S"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errs := r.Validate(terraform.NewResourceConfig(cfg)); len(errs) == 0 {
+		t.Fatal("an invalid database name is valid")
+	}
+}
+
+// TestAccSchemaQuotedNames manages a schema and its database by names that
+// only double quotes preserve: lower and mixed case, a dot and a double quote.
+func TestAccSchemaQuotedNames(t *testing.T) {
+	database := fmt.Sprintf(`"%s.Db"`, strings.ToLower(testAccName()))
+	name := `"Mixed ""Case"" s"`
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_schema", testAccShowSchema),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSchemaQuotedNamesConfig(database, name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_database.test", testAccShowDatabase),
+					testAccCheckExists("snowflake_schema.test", testAccShowSchema),
+					resource.TestCheckResourceAttr("snowflake_database.test", "id", database),
+					resource.TestCheckResourceAttr("snowflake_database.test", "name", database),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "id", database+"."+name),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "database", database),
+					resource.TestCheckResourceAttr("snowflake_schema.test", "name", name),
+				),
+			},
+			{
+				ResourceName:      "snowflake_schema.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccSchemaQuotedNamesConfig(database string, name string) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = %q
+}
+
+resource "snowflake_schema" "test" {
+  database = "${snowflake_database.test.name}"
+  name     = %q
+}
+`, database, name)
+}
@@ -0,0 +1,167 @@
+package snowflake
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
+)
+
+func resourceSnowflakeSequence() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceSnowflakeSequenceCreate,
+		Read:   resourceSnowflakeSequenceRead,
+		Update: resourceSnowflakeSequenceUpdate,
+		Delete: resourceSnowflakeSequenceDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStateID("database", "schema", "name"),
+		},
+		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				StateFunc:    identifierStateFunc,
+				ValidateFunc: validateIdentifier,
+			},
+			"schema": {
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
+			},
+			"database": {
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
+			},
+			// SHOW SEQUENCES only returns the next value, the start is kept
+			// as configured. An imported sequence has none, which is not a
+			// reason to replace it.
+			"start": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1,
+				ForceNew: true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return old == "" && d.Id() != ""
+				},
+			},
+			"increment": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1,
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"next_value": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			// The name to call NEXTVAL on, e.g. in the default of a column.
+			"fully_qualified_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"owner": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceSnowflakeSequenceCreate(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	databaseName := d.Get("database").(string)
+	schemaName := d.Get("schema").(string)
+	name := d.Get("name").(string)
+	statement := snowsql.NewCreateBuilder("SEQUENCE", qualifiedName(databaseName, schemaName, name)).
+		SetInt("START", d.Get("start").(int)).
+		SetInt("INCREMENT", d.Get("increment").(int)).
+		SetString("COMMENT", d.Get("comment").(string)).
+		Statement()
+	_, err = db.Exec(statement)
+	return createThenRead(d, meta, db, encodeID(databaseName, schemaName, name), err, resourceSnowflakeSequenceRead)
+}
+
+func resourceSnowflakeSequenceRead(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	database, schema, name := id[0], id[1], id[2]
+	r, err := db.ShowSequence(database, schema, name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	d.Set("database", storedIdentifier(r.DatabaseName))
+	d.Set("schema", storedIdentifier(r.SchemaName))
+	d.Set("name", storedIdentifier(r.Name))
+	d.Set("fully_qualified_name", qualifiedName(database, schema, name))
+	d.Set("increment", r.Interval)
+	d.Set("next_value", r.NextValue)
+	d.Set("comment", r.Comment)
+	d.Set("owner", r.Owner)
+
+	return nil
+}
+
+func resourceSnowflakeSequenceUpdate(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	databaseName, schemaName, name := id[0], id[1], id[2]
+	// The increment and comment are the only properties that can be altered,
+	// everything else forces a new sequence.
+	b := snowsql.NewAlterBuilder("SEQUENCE", qualifiedName(databaseName, schemaName, name))
+	if d.HasChange("increment") {
+		b.SetInt("INCREMENT", d.Get("increment").(int))
+	}
+	if d.HasChange("comment") {
+		b.SetString("COMMENT", d.Get("comment").(string))
+	}
+	return db.ExecAll(b.Statements())
+}
+
+func resourceSnowflakeSequenceDelete(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	databaseName, schemaName, name := id[0], id[1], id[2]
+	exists, err := db.ObjectExists("sequences", name, inSchema(databaseName, schemaName))
+	if err != nil {
+		return err
+	}
+	if exists == false {
+		// Already dropped outside of Terraform.
+		return nil
+	}
+	statement := snowsql.Drop("SEQUENCE", qualifiedName(databaseName, schemaName, name))
+	if _, err = db.Exec(statement); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package snowflake
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func testAccShowSequence(c Client, id string) error {
+	names, err := decodeID(id, "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	_, err = c.ShowSequence(names[0], names[1], names[2])
+	return err
+}
+
+func TestAccSequence(t *testing.T) {
+	database := testAccName()
+	name := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_sequence", testAccShowSequence),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSequenceConfig(database, name, 100, 1, "created"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_sequence.test", testAccShowSequence),
+					resource.TestCheckResourceAttr("snowflake_sequence.test", "id", database+".PUBLIC."+name),
+					resource.TestCheckResourceAttr("snowflake_sequence.test", "fully_qualified_name", database+".PUBLIC."+name),
+					resource.TestCheckResourceAttr("snowflake_sequence.test", "start", "100"),
+					resource.TestCheckResourceAttr("snowflake_sequence.test", "increment", "1"),
+					resource.TestCheckResourceAttr("snowflake_sequence.test", "next_value", "100"),
+					resource.TestCheckResourceAttr("snowflake_sequence.test", "comment", "created"),
+					resource.TestCheckResourceAttrSet("snowflake_sequence.test", "owner"),
+				),
+			},
+			// Altered in place.
+			{
+				Config: testAccSequenceConfig(database, name, 100, 10, "updated"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_sequence.test", testAccShowSequence),
+					resource.TestCheckResourceAttr("snowflake_sequence.test", "increment", "10"),
+					resource.TestCheckResourceAttr("snowflake_sequence.test", "comment", "updated"),
+				),
+			},
+			// Replaced.
+			{
+				Config: testAccSequenceConfig(database, name, 1, 10, "updated"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_sequence.test", testAccShowSequence),
+					resource.TestCheckResourceAttr("snowflake_sequence.test", "start", "1"),
+					resource.TestCheckResourceAttr("snowflake_sequence.test", "next_value", "1"),
+				),
+			},
+			// The start is not part of SHOW SEQUENCES and can't be imported.
+			{
+				ResourceName:            "snowflake_sequence.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"start"},
+			},
+			// Dropped outside of Terraform.
+			{
+				Config:             testAccSequenceConfig(database, name, 1, 10, "updated"),
+				Check:              testAccExec(fmt.Sprintf("DROP SEQUENCE %s.PUBLIC.%s", database, name)),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccSequenceConfig(database string, name string, start int, increment int, comment string) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_sequence" "test" {
+  database  = "${snowflake_database.test.name}"
+  schema    = "PUBLIC"
+  name      = "%s"
+  start     = %d
+  increment = %d
+  comment   = "%s"
+}
+`, database, name, start, increment, comment)
+}
+
+func TestSequenceFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeSequence()
+	c.rows["ShowSequence D S Q"] = showSequenceRow{Name: "Q", DatabaseName: "D", SchemaName: "S", NextValue: 10, Interval: 5, Owner: "SYSADMIN"}
+	c.objects["sequences Q IN SCHEMA D.S"] = true
+
+	state, err := testFakeApply(t, r, meta, nil, map[string]interface{}{
+		"database":  "d",
+		"schema":    "s",
+		"name":      "q",
+		"start":     10,
+		"increment": 5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "CREATE SEQUENCE D.S.Q START = 10 INCREMENT = 5")
+	testCheckAttributes(t, state, map[string]string{
+		"id":                   "D.S.Q",
+		"start":                "10",
+		"next_value":           "10",
+		"fully_qualified_name": "D.S.Q",
+	})
+
+	state, err = testFakeApply(t, r, meta, state, map[string]interface{}{
+		"database":  "d",
+		"schema":    "s",
+		"name":      "q",
+		"start":     10,
+		"increment": 2,
+		"comment":   "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "ALTER SEQUENCE D.S.Q SET INCREMENT = 2 COMMENT = 'c'")
+
+	// The start of an imported sequence is unknown and does not replace it.
+	imported, err := testFakeImport(t, r, meta, "D.S.Q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{"name": "Q", "increment": "5", "start": ""})
+	if _, err = testFakeApply(t, r, meta, imported, map[string]interface{}{"database": "D", "schema": "S", "name": "Q", "start": 10, "increment": 5}); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c)
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "DROP SEQUENCE D.S.Q")
+}
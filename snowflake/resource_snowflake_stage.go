@@ -1,51 +1,43 @@
 package snowflake
 
 import (
-	"database/sql"
-	"fmt"
-	"strings"
-
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
 )
 
 func resourceSnowflakeStage() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceSnowflakeStageCreate,
 		Read:   resourceSnowflakeStageRead,
+		Update: executionRoleUpdate,
 		Delete: resourceSnowflakeStageDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				d.SetId(strings.ToUpper(d.Id()))
-				return []*schema.ResourceData{d}, nil
-			},
+			State: importStateID("database", "schema", "name"),
 		},
 		// TODO: validation for Snowflake compatible names, ex. no hyphens
 		// TODO: verify schema present in database
+		SchemaVersion: idSchemaVersion,
 		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    identifierStateFunc,
+				ValidateFunc: validateIdentifier,
+				ForceNew:     true,
 			},
 			"database": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
-				ForceNew: true,
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: identifierStateFunc,
+				ForceNew:  true,
 			},
 			"schema": {
-				Type:     schema.TypeString,
-				Optional: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
-				Default:  "PUBLIC",
-				ForceNew: true,
+				Type:      schema.TypeString,
+				Optional:  true,
+				StateFunc: identifierStateFunc,
+				Default:   "PUBLIC",
+				ForceNew:  true,
 			},
 			"url": {
 				Type:     schema.TypeString,
@@ -62,10 +54,12 @@ func resourceSnowflakeStage() *schema.Resource {
 				Sensitive: true,
 			},
 			"file_format": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"file_format_name"},
 			},
+			"file_format_name": fileFormatNameSchema("file_format"),
 			"copy_options": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -88,58 +82,51 @@ func resourceSnowflakeStage() *schema.Resource {
 			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(r, "database", "schema", "name")
+	return r
 }
 
 func resourceSnowflakeStageCreate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	name := strings.ToUpper(d.Get("name").(string))
-	database := strings.ToUpper(d.Get("database").(string))
-	schema := strings.ToUpper(d.Get("schema").(string))
-	stageId := fmt.Sprintf("%s.%s.%s", database, schema, name)
-	url := d.Get("url")
-	credentials := d.Get("credentials")
-	file_format := d.Get("file_format")
-	copy_options := d.Get("copy_options")
-	encryption := d.Get("encryption")
-
-	statement := fmt.Sprintf("CREATE STAGE %v.%v.%v", database, schema, name)
-	if url != "" {
-		statement += fmt.Sprintf(" URL = '%v'", url)
-	}
-	if credentials != "" {
-		statement += fmt.Sprintf(" CREDENTIALS = (%v)", credentials)
-	}
-	if file_format != "" {
-		statement += fmt.Sprintf(" file_format = (%v)", file_format)
-	}
-	if copy_options != "" {
-		statement += fmt.Sprintf(" copy_options = (%v)", copy_options)
-	}
-	if encryption != "" {
-		statement += fmt.Sprintf(" encryption = (%v)", encryption)
-	}
-	_, err := db.Exec(statement)
+	db, err := newClient(meta, d)
 	if err != nil {
 		return err
 	}
-	d.SetId(stageId)
-	err = resourceSnowflakeStageRead(d, meta)
-	if err != nil {
-		return err
+	name := d.Get("name").(string)
+	database := d.Get("database").(string)
+	schema := d.Get("schema").(string)
+	stageId := encodeID(database, schema, name)
+
+	fileFormat := d.Get("file_format").(string)
+	if fileFormat == "" {
+		fileFormat = renderFormatName(d)
 	}
-	return nil
+	statement := snowsql.NewCreateBuilder("STAGE", qualifiedName(database, schema, name)).
+		SetString("URL", d.Get("url").(string)).
+		SetOptions("CREDENTIALS", d.Get("credentials").(string)).
+		SetOptions("FILE_FORMAT", fileFormat).
+		SetOptions("COPY_OPTIONS", d.Get("copy_options").(string)).
+		SetOptions("ENCRYPTION", d.Get("encryption").(string)).
+		Statement()
+	_, err = db.Exec(statement)
+	return createThenRead(d, meta, db, stageId, err, resourceSnowflakeStageRead)
 }
 
 func resourceSnowflakeStageRead(d *schema.ResourceData, meta interface{}) error {
-
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	stageID := d.Id()
-	s := strings.Split(stageID, ".")
-	database, schema, name := s[0], s[1], s[2]
-	stageInfo, err := descStage(db, database, schema, name)
+	id, err := decodeID(stageID, "database", "schema", "name")
 	if err != nil {
 		return err
 	}
+	database, schema, name := id[0], id[1], id[2]
+	stageInfo, err := db.DescStage(database, schema, name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
 	d.Set("name", name)
 	d.Set("schema", schema)
 	d.Set("database", database)
@@ -161,11 +148,26 @@ func resourceSnowflakeStageUpdate(d *schema.ResourceData, meta interface{}) erro
 }*/
 
 func resourceSnowflakeStageDelete(d *schema.ResourceData, meta interface{}) error {
-
-	db := meta.(*sql.DB)
-	stageID := d.Id()
-	statement := fmt.Sprintf("DROP STAGE %v", stageID)
-	_, err := db.Exec(statement)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	database, schema, name := id[0], id[1], id[2]
+	exists, err := db.ObjectExists("stages", name, inSchema(database, schema))
+	if err != nil {
+		return err
+	}
+	if exists == false {
+		// Already dropped outside of Terraform.
+		return nil
+	}
+	statement := snowsql.Drop("STAGE", qualifiedName(database, schema, name))
+	_, err = db.Exec(statement)
 	if err != nil {
 		return err
 	}
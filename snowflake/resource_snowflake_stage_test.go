@@ -0,0 +1,139 @@
+package snowflake
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func testAccShowStage(c Client, id string) error {
+	names, err := decodeID(id, "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	_, err = c.DescStage(names[0], names[1], names[2])
+	return err
+}
+
+func TestAccStage(t *testing.T) {
+	database := testAccName()
+	name := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_stage", testAccShowStage),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStageConfig(database, name, "s3://tf-acc-stage/created/"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_stage.test", testAccShowStage),
+					resource.TestCheckResourceAttr("snowflake_stage.test", "id", database+".PUBLIC."+name),
+					resource.TestCheckResourceAttr("snowflake_stage.test", "name", name),
+					resource.TestCheckResourceAttr("snowflake_stage.test", "schema", "PUBLIC"),
+					resource.TestCheckResourceAttr("snowflake_stage.test", "url", "s3://tf-acc-stage/created/"),
+				),
+			},
+			// Replaced.
+			{
+				Config: testAccStageConfig(database, name, "s3://tf-acc-stage/replaced/"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_stage.test", testAccShowStage),
+					resource.TestCheckResourceAttr("snowflake_stage.test", "url", "s3://tf-acc-stage/replaced/"),
+				),
+			},
+			// The options are not part of DESC STAGE and can't be imported.
+			{
+				ResourceName:            "snowflake_stage.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"file_format", "file_format_name", "copy_options"},
+			},
+			// Dropped outside of Terraform.
+			{
+				Config:             testAccStageConfig(database, name, "s3://tf-acc-stage/replaced/"),
+				Check:              testAccExec(fmt.Sprintf("DROP STAGE %s.PUBLIC.%s", database, name)),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccStageConfig(database string, name string, url string) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_stage" "test" {
+  database     = "${snowflake_database.test.name}"
+  name         = "%s"
+  url          = "%s"
+  file_format  = "TYPE = CSV FIELD_DELIMITER = '|'"
+  copy_options = "ON_ERROR = CONTINUE"
+}
+`, database, name, url)
+}
+
+func TestStageFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeStage()
+	c.rows["DescStage D PUBLIC S"] = descStageResult{
+		url:                "s3://bucket/path/",
+		aws_external_id:    "EXTERNAL_ID",
+		snowflake_iam_user: "arn:aws:iam::1:user/u",
+	}
+	c.objects["stages S IN SCHEMA D.PUBLIC"] = true
+
+	state, err := testFakeApply(t, r, meta, nil, map[string]interface{}{
+		"database":    "d",
+		"name":        "s",
+		"url":         "s3://bucket/path/",
+		"credentials": "AWS_ROLE = 'arn:aws:iam::1:role/r'",
+		"file_format": "TYPE = CSV",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "CREATE STAGE D.PUBLIC.S URL = 's3://bucket/path/' CREDENTIALS = (AWS_ROLE = 'arn:aws:iam::1:role/r') FILE_FORMAT = (TYPE = CSV)")
+	testCheckAttributes(t, state, map[string]string{
+		"id":                 "D.PUBLIC.S",
+		"schema":             "PUBLIC",
+		"aws_external_id":    "EXTERNAL_ID",
+		"snowflake_iam_user": "arn:aws:iam::1:user/u",
+	})
+
+	imported, err := testFakeImport(t, r, meta, "d.public.s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{"id": "D.PUBLIC.S", "name": "S", "url": "s3://bucket/path/"})
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "DROP STAGE D.PUBLIC.S")
+
+	if _, err = testFakeApply(t, r, meta, nil, map[string]interface{}{
+		"database":         "D",
+		"name":             "S",
+		"file_format_name": `d.public."csv"`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, `CREATE STAGE D.PUBLIC.S FILE_FORMAT = (FORMAT_NAME = D.PUBLIC."csv")`)
+
+	cfg, err := config.NewRawConfig(map[string]interface{}{
+		"database":         "D",
+		"name":             "S",
+		"file_format":      "TYPE = CSV",
+		"file_format_name": "D.PUBLIC.CSV",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errs := r.Validate(terraform.NewResourceConfig(cfg)); len(errs) == 0 {
+		t.Fatal("a stage with both file_format and file_format_name is valid")
+	}
+}
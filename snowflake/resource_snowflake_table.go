@@ -1,48 +1,42 @@
 package snowflake
 
 import (
-	"database/sql"
 	"fmt"
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
 )
 
 func resourceSnowflakeTable() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceSnowflakeTableCreate,
 		Read:   resourceSnowflakeTableRead,
 		Update: resourceSnowflakeTableUpdate,
 		Delete: resourceSnowflakeTableDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				d.SetId(strings.ToUpper(d.Id()))
-				return []*schema.ResourceData{d}, nil
-			},
+			State: importStateID("database", "schema", "name"),
 		},
+		SchemaVersion: idSchemaVersion,
 		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    identifierStateFunc,
+				ValidateFunc: validateIdentifier,
 			},
 			"database": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
 			},
 			"schema": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
 			},
 			"columns": {
 				Type:     schema.TypeList,
@@ -53,12 +47,10 @@ func resourceSnowflakeTable() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"name": {
-							Type:     schema.TypeString,
-							Required: true,
-							ForceNew: true,
-							StateFunc: func(v interface{}) string {
-								return strings.ToUpper(v.(string))
-							},
+							Type:      schema.TypeString,
+							Required:  true,
+							ForceNew:  true,
+							StateFunc: identifierStateFunc,
 						},
 						"type": {
 							Type:     schema.TypeString,
@@ -81,14 +73,20 @@ func resourceSnowflakeTable() *schema.Resource {
 			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(r, "database", "schema", "name")
+	return r
 }
 
 func resourceSnowflakeTableCreate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	databaseName := d.Get("database")
-	schemaName := d.Get("schema")
-	tableName := d.Get("name")
-	tableID := fmt.Sprintf("%s.%s.%s", databaseName, schemaName, tableName)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	databaseName := d.Get("database").(string)
+	schemaName := d.Get("schema").(string)
+	tableName := d.Get("name").(string)
+	tableID := encodeID(databaseName, schemaName, tableName)
 	columnDefs := ""
 	// This is black magic to me but it seems to work.
 	// Casting d.Get("columns").([]map[string]interface{}) did not seem to work
@@ -96,41 +94,53 @@ func resourceSnowflakeTableCreate(d *schema.ResourceData, meta interface{}) erro
 	for _, iElement := range d.Get("columns").([]interface{}) {
 		element := iElement.(map[string]interface{})
 		if element["default"] != "" {
-			columnDefs += fmt.Sprintf("%s %s default %s,", element["name"], element["type"], element["default"])
+			columnDefs += fmt.Sprintf("%s %s default %s,", identifier(element["name"].(string)), element["type"], element["default"])
 		} else {
-			columnDefs += fmt.Sprintf("%s %s,", element["name"], element["type"])
+			columnDefs += fmt.Sprintf("%s %s,", identifier(element["name"].(string)), element["type"])
 		}
 	}
 	columnDefs = strings.TrimRight(columnDefs, ",")
-	statement := fmt.Sprintf("CREATE TABLE %s ( %s )", tableID, columnDefs)
-	_, err := db.Exec(statement)
+	statement := snowsql.NewCreateBuilder("TABLE", qualifiedName(databaseName, schemaName, tableName)).
+		Body(fmt.Sprintf("( %s )", columnDefs)).
+		Statement()
+	_, err = db.Exec(statement)
 	if err != nil {
 		return err
 	}
-	d.SetId(strings.ToUpper(tableID))
+	d.SetId(tableID)
 	return nil
 }
 
 func resourceSnowflakeTableRead(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	s := strings.Split(d.Id(), ".")
-	database, schema, name := s[0], s[1], s[2]
-	t, err := readTable(db, database, schema, name)
+	db, err := newClient(meta, d)
 	if err != nil {
 		return err
 	}
-	d.Set("name", t.tableName)
-	d.Set("database", t.tableCatalog)
-	d.Set("schema", t.tableSchema)
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	database, schema, name := id[0], id[1], id[2]
+	t, err := db.ShowTable(database, schema, name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	d.Set("name", storedIdentifier(t.Name))
+	d.Set("database", storedIdentifier(t.DatabaseName))
+	d.Set("schema", storedIdentifier(t.SchemaName))
 	columnDefs := []map[string]string{}
-	columnInfo, err := descTable(db, database, schema, name)
+	columnInfo, err := db.DescTable(database, schema, name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
 	for _, e := range columnInfo {
 		columnDef := map[string]string{
-			"name": e.colName,
-			"type": e.colType,
+			"name": storedIdentifier(e.ColName),
+			"type": e.ColType,
 		}
-		if e.defaultValue.Valid {
-			columnDef["default"] = e.defaultValue.String
+		if e.DefaultValue.Valid {
+			columnDef["default"] = e.DefaultValue.String
 		}
 		columnDefs = append(columnDefs, columnDef)
 	}
@@ -139,28 +149,35 @@ func resourceSnowflakeTableRead(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceSnowflakeTableUpdate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	s := strings.Split(d.Id(), ".")
-	databaseName, schemaName, tableName := s[0], s[1], s[2]
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	databaseName, schemaName, tableName := id[0], id[1], id[2]
 	// Rather than issue a single alter database statement for all possible
 	// changes issue an alter for each possible thing that has changed. Enable
 	// partial mode.
 	d.Partial(true)
 	if d.HasChange("name") {
 		// check that the rename target does not exist
-		exists, err := sqlObjExists(db, "tables", d.Get("name").(string), fmt.Sprintf("%s.%s", databaseName, schemaName))
+		exists, err := db.ObjectExists("tables", d.Get("name").(string), inSchema(databaseName, schemaName))
 		if err != nil {
 			return err
 		}
 		if exists == true {
-			return fmt.Errorf("Cannot rename %s to %s.%s.%s, already exists", d.Id(), databaseName, schemaName, tableName)
+			return fmt.Errorf("Cannot rename %s to %s.%s.%s, already exists", d.Id(), databaseName, schemaName, d.Get("name"))
 		}
-		statement := fmt.Sprintf("ALTER TABLE %s RENAME TO %s.%s.%s", d.Id(), databaseName, schemaName, d.Get("name"))
-		if _, err := db.Exec(statement); err != nil {
+		statement := snowsql.NewAlterBuilder("TABLE", qualifiedName(databaseName, schemaName, tableName)).Rename(qualifiedName(databaseName, schemaName, d.Get("name").(string)))
+		if _, err = db.Exec(statement); err != nil {
 			return err
 		}
 		d.SetPartial("name")
-		newResourceID := fmt.Sprintf("%s.%s.%s", databaseName, schemaName, d.Get("name"))
+		newResourceID := encodeID(databaseName, schemaName, d.Get("name").(string))
 		d.SetId(newResourceID)
 	}
 	d.Partial(false)
@@ -168,18 +185,26 @@ func resourceSnowflakeTableUpdate(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceSnowflakeTableDelete(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	s := strings.Split(d.Id(), ".")
-	databaseName, schemaName, name := s[0], s[1], s[2]
-	exists, err := sqlObjExists(db, "tables", name, fmt.Sprintf("%s.%s", databaseName, schemaName))
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	databaseName, schemaName, name := id[0], id[1], id[2]
+	exists, err := db.ObjectExists("tables", name, inSchema(databaseName, schemaName))
 	if err != nil {
 		return err
 	}
 	if exists == false {
-		return fmt.Errorf("Table %s does not exist", d.Id())
+		// Already dropped outside of Terraform.
+		return nil
 	}
-	statement := fmt.Sprintf("DROP TABLE %s", d.Id())
-	if _, err := db.Exec(statement); err != nil {
+	statement := snowsql.Drop("TABLE", qualifiedName(databaseName, schemaName, name))
+	if _, err = db.Exec(statement); err != nil {
 		return err
 	}
 	return nil
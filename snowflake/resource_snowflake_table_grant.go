@@ -1,7 +1,6 @@
 package snowflake
 
 import (
-	"database/sql"
 	"fmt"
 	"strings"
 
@@ -9,40 +8,34 @@ import (
 )
 
 func resourceSnowflakeTableGrant() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceSnowflakeTableGrantCreate,
 		Read:   resourceSnowflakeTableGrantRead,
+		Update: executionRoleUpdate,
 		Delete: resourceSnowflakeTableGrantDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				d.SetId(strings.ToUpper(d.Id()))
-				return []*schema.ResourceData{d}, nil
-			},
+			State: importStateID("grantee", "database", "schema", "table"),
 		},
+		SchemaVersion: idSchemaVersion,
 		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
 			"table": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
-				ForceNew: true,
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: identifierStateFunc,
+				ForceNew:  true,
 			},
 			"database": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
-				ForceNew: true,
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: identifierStateFunc,
+				ForceNew:  true,
 			},
 			"schema": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
-				ForceNew: true,
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: identifierStateFunc,
+				ForceNew:  true,
 			},
 			"privileges": {
 				Type: schema.TypeList,
@@ -51,76 +44,59 @@ func resourceSnowflakeTableGrant() *schema.Resource {
 					StateFunc: func(v interface{}) string {
 						return strings.ToUpper(v.(string))
 					},
+					ValidateFunc: validatePrivilege,
 				},
 				Required: true,
 				ForceNew: true,
 			},
 			"grantee_role": {
-				Type:     schema.TypeString,
-				Optional: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				StateFunc:     identifierStateFunc,
 				ForceNew:      true,
 				ConflictsWith: []string{"grantee_share"},
 			},
 			"grantee_share": {
-				Type:     schema.TypeString,
-				Optional: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				StateFunc:     identifierStateFunc,
 				ForceNew:      true,
 				ConflictsWith: []string{"grantee_role"},
 			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(r, "grantee", "database", "schema", "table")
+	return r
 }
 
 func resourceSnowflakeTableGrantCreate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	table := strings.ToUpper(d.Get("table").(string))
-	database := strings.ToUpper(d.Get("database").(string))
-	schema := strings.ToUpper(d.Get("schema").(string))
-	granteeRole := strings.ToUpper(d.Get("grantee_role").(string))
-	granteeShare := strings.ToUpper(d.Get("grantee_share").(string))
-
-	id := ""
-
-	if granteeRole != "" {
-		id += fmt.Sprintf("%v.%v.%v.%v.", granteeRole, database, schema, table)
-	} else {
-		id += fmt.Sprintf("%v.%v.%v.%v.", granteeShare, database, schema, table)
-	}
-
-	statement := "GRANT "
-
-	for _, p := range d.Get("privileges").([]interface{}) {
-		statement += p.(string)
-		statement += ", "
-		id += fmt.Sprintf("%v.", p)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
 	}
-	statement = strings.Trim(statement, ", ")
-	id = strings.Trim(id, ".")
-
-	if table == "ALL" {
-		statement += fmt.Sprintf(" ON ALL TABLES IN %v.%v", database, schema)
+	defer db.Close()
+	table := canonicalIdentifier(d.Get("table").(string))
+	database := canonicalIdentifier(d.Get("database").(string))
+	schema := canonicalIdentifier(d.Get("schema").(string))
+	granteeRole := canonicalIdentifier(d.Get("grantee_role").(string))
+	granteeShare := canonicalIdentifier(d.Get("grantee_share").(string))
+
+	var id string
+	if granteeRole != "" {
+		id = encodeID(granteeRole, database, schema, table)
 	} else {
-		statement += fmt.Sprintf(" ON %v.%v.%v TO ", database, schema, table)
+		id = encodeID(granteeShare, database, schema, table)
 	}
 
-	if granteeRole != "" {
-		statement += fmt.Sprintf("ROLE %v", granteeRole)
+	on := qualifiedName(database, schema, table)
+	if resolvedName(table) == "ALL" {
+		on = fmt.Sprintf("ALL TABLES IN SCHEMA %s", qualifiedName(database, schema))
 	}
-
+	to := fmt.Sprintf("ROLE %s", identifier(granteeRole))
 	if granteeShare != "" {
-		statement += fmt.Sprintf("SHARE %v", granteeShare)
+		to = fmt.Sprintf("SHARE %s", identifier(granteeShare))
 	}
-
-	statement = strings.ToUpper(statement)
-
-	_, err := db.Exec(statement)
-	if err != nil {
+	if err = db.Grant(expandPrivileges(d), on, to); err != nil {
 		return err
 	}
 
@@ -129,43 +105,69 @@ func resourceSnowflakeTableGrantCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceSnowflakeTableGrantRead(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	grantID := d.Id()
-	s := strings.Split(grantID, ".")
-	grantee, database, schema, table := s[0], s[1], s[2], s[3]
-	tableGrantInfoResult, err := showTableGrant(db, grantee, database, schema, table)
+	id, err := decodeID(grantID, "grantee", "database", "schema", "table")
+	if err != nil {
+		return err
+	}
+	grantee, database, schema, table := id[0], id[1], id[2], id[3]
+	tableGrantInfoResult, err := db.ShowTableGrant(grantee, database, schema, table)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	if resolvedName(table) == "ALL" && len(tableGrantInfoResult.privileges) == 0 {
+		// The schema has no tables yet, none of them lacks a privilege.
+		tableGrantInfoResult.privileges = expandPrivileges(d)
+	}
 
-	d.Set("privileges", tableGrantInfoResult.privileges)
-	d.Set("granteeRole", tableGrantInfoResult.grantee)
-	d.Set("granteeShare", tableGrantInfoResult.grantee)
+	d.Set("privileges", managedPrivileges(d, tableGrantInfoResult.privileges))
+	if d.Get("grantee_share").(string) != "" {
+		d.Set("grantee_share", tableGrantInfoResult.grantee)
+	} else {
+		d.Set("grantee_role", tableGrantInfoResult.grantee)
+	}
 	d.Set("table", table)
 	d.Set("schema", schema)
 	d.Set("database", database)
 
-	return err
+	return nil
 }
 
 func resourceSnowflakeTableGrantDelete(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	grantID := d.Id()
-	s := strings.Split(grantID, ".")
-	grantee, database, schema, table := s[0], s[1], s[2], s[3]
-	statement := "REVOKE "
-
-	for index, val := range s {
-		if index >= 4 {
-			statement += fmt.Sprintf("%v, ", val)
-		}
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
 	}
-
-	statement = strings.Trim(statement, ", ")
-	statement += fmt.Sprintf(" ON %v.%v.%v FROM %v", database, schema, table, grantee)
-
-	statement = strings.ToUpper(statement)
-
-	_, err := db.Exec(statement)
+	defer db.Close()
+	grantID := d.Id()
+	id, err := decodeID(grantID, "grantee", "database", "schema", "table")
 	if err != nil {
 		return err
 	}
+	grantee, database, schema, table := id[0], id[1], id[2], id[3]
+	on := qualifiedName(database, schema, table)
+	if resolvedName(table) == "ALL" {
+		on = fmt.Sprintf("ALL TABLES IN SCHEMA %s", qualifiedName(database, schema))
+	}
+	from := fmt.Sprintf("ROLE %s", identifier(grantee))
+	if d.Get("grantee_share").(string) != "" {
+		from = fmt.Sprintf("SHARE %s", identifier(grantee))
+	}
+	privileges := grantedPrivileges(d)
+	if len(privileges) == 0 {
+		// Already revoked outside of Terraform.
+		return nil
+	}
+	// Revoking from an object or grantee dropped outside of Terraform fails,
+	// the grant is gone with it.
+	err = db.Revoke(privileges, on, from)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
 	return nil
 }
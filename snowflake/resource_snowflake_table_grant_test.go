@@ -0,0 +1,269 @@
+package snowflake
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func testAccShowTableGrant(c Client, id string) error {
+	names, err := decodeID(id, "grantee", "database", "schema", "table")
+	if err != nil {
+		return err
+	}
+	_, err = c.ShowTableGrant(names[0], names[1], names[2], names[3])
+	return err
+}
+
+func TestAccTableGrant(t *testing.T) {
+	database := testAccName()
+	role := testAccName()
+	id := role + "." + database + ".PUBLIC.EVENTS"
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_table_grant", testAccShowTableGrant),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTableGrantConfig(database, role, "SELECT"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_table_grant.test", testAccShowTableGrant),
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "id", id),
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "grantee_role", role),
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "privileges.0", "SELECT"),
+				),
+			},
+			// Replaced.
+			{
+				Config: testAccTableGrantConfig(database, role, "INSERT"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_table_grant.test", testAccShowTableGrant),
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "privileges.0", "INSERT"),
+				),
+			},
+			{
+				ResourceName:      "snowflake_table_grant.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Revoked outside of Terraform.
+			{
+				Config:             testAccTableGrantConfig(database, role, "INSERT"),
+				Check:              testAccExec(fmt.Sprintf("REVOKE INSERT ON TABLE %s.PUBLIC.EVENTS FROM ROLE %s", database, role)),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// Two grants of different privileges on the same table to the same role each
+// manage their own privileges.
+func TestAccTableGrantSameGrantee(t *testing.T) {
+	database := testAccName()
+	role := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_table_grant", testAccShowTableGrant),
+		Steps: []resource.TestStep{
+			// The plan after applying is empty, neither grant reads back the
+			// privileges of the other.
+			{
+				Config: testAccTableGrantConfig(database, role, "SELECT") + testAccTableGrantSecondConfig("INSERT"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "privileges.0", "SELECT"),
+					resource.TestCheckResourceAttr("snowflake_table_grant.second", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("snowflake_table_grant.second", "privileges.0", "INSERT"),
+					testAccCheckTableGrantPrivileges(database, role, "INSERT", "SELECT"),
+				),
+			},
+			// Destroying one grant leaves the privileges of the other.
+			{
+				Config: testAccTableGrantConfig(database, role, "SELECT"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "privileges.#", "1"),
+					testAccCheckTableGrantPrivileges(database, role, "SELECT"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckTableGrantPrivileges checks the privileges role has on the
+// EVENTS table of database, in alphabetical order.
+func testAccCheckTableGrantPrivileges(database string, role string, privileges ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		return testAccWithClient(func(c Client) error {
+			r, err := c.ShowTableGrant(role, database, "PUBLIC", "EVENTS")
+			if err != nil {
+				return err
+			}
+			sort.Strings(r.privileges)
+			if !reflect.DeepEqual(r.privileges, privileges) {
+				return fmt.Errorf("%s has %v on %s.PUBLIC.EVENTS, want %v", role, r.privileges, database, privileges)
+			}
+			return nil
+		})
+	}
+}
+
+func testAccTableGrantSecondConfig(privilege string) string {
+	return fmt.Sprintf(`
+resource "snowflake_table_grant" "second" {
+  database     = "${snowflake_table.test.database}"
+  schema       = "${snowflake_table.test.schema}"
+  table        = "${snowflake_table.test.name}"
+  privileges   = ["%s"]
+  grantee_role = "${snowflake_role.test.name}"
+}
+`, privilege)
+}
+
+func testAccTableGrantConfig(database string, role string, privilege string) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_table" "test" {
+  database = "${snowflake_database.test.name}"
+  schema   = "PUBLIC"
+  name     = "EVENTS"
+
+  columns {
+    name = "ID"
+    type = "NUMBER(38,0)"
+  }
+}
+
+resource "snowflake_role" "test" {
+  name = "%s"
+}
+
+resource "snowflake_table_grant" "test" {
+  database     = "${snowflake_table.test.database}"
+  schema       = "${snowflake_table.test.schema}"
+  table        = "${snowflake_table.test.name}"
+  privileges   = ["%s"]
+  grantee_role = "${snowflake_role.test.name}"
+}
+`, database, role, privilege)
+}
+
+// A grant on ALL TABLES reads back as the privileges granted on every table
+// of the schema.
+func TestAccTableGrantAll(t *testing.T) {
+	database := testAccName()
+	role := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_table_grant", testAccShowTableGrant),
+		Steps: []resource.TestStep{
+			// The schema has no TABLES, none of them lacks SELECT.
+			{
+				Config: testAccTableGrantAllConfig(database, role),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "id", role+"."+database+`.PUBLIC."ALL"`),
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "privileges.0", "SELECT"),
+				),
+			},
+			// A table created afterwards lacks SELECT.
+			{
+				Config:             testAccTableGrantAllConfig(database, role),
+				Check:              testAccExec(fmt.Sprintf("CREATE TABLE %s.PUBLIC.EVENTS (ID NUMBER(38,0))", database)),
+				ExpectNonEmptyPlan: true,
+			},
+			// Granting again covers it.
+			{
+				Config: testAccTableGrantAllConfig(database, role),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_table_grant.test", testAccShowTableGrant),
+					resource.TestCheckResourceAttr("snowflake_table_grant.test", "privileges.0", "SELECT"),
+				),
+			},
+		},
+	})
+}
+
+func testAccTableGrantAllConfig(database string, role string) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_role" "test" {
+  name = "%s"
+}
+
+resource "snowflake_table_grant" "test" {
+  database     = "${snowflake_database.test.name}"
+  schema       = "PUBLIC"
+  table        = "ALL"
+  privileges   = ["SELECT"]
+  grantee_role = "${snowflake_role.test.name}"
+}
+`, database, role)
+}
+
+func TestTableGrantFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeTableGrant()
+	// UPDATE is granted by another resource.
+	c.rows["ShowTableGrant R D S T"] = showTableGrantResult{
+		database:   "D",
+		schema:     "S",
+		table:      "T",
+		grantee:    "R",
+		privileges: []string{"UPDATE", "SELECT", "INSERT"},
+	}
+	raw := map[string]interface{}{
+		"database":     "d",
+		"schema":       "s",
+		"table":        "t",
+		"privileges":   []interface{}{"insert", "select"},
+		"grantee_role": "r",
+	}
+
+	state, err := testFakeApply(t, r, meta, nil, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "GRANT INSERT, SELECT ON D.S.T TO ROLE R")
+	testCheckAttributes(t, state, map[string]string{"id": "R.D.S.T", "privileges.#": "2"})
+
+	state, err = r.Refresh(state, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The privileges keep the case they are configured in.
+	testCheckAttributes(t, state, map[string]string{"privileges.#": "2", "privileges.0": "insert", "privileges.1": "select"})
+
+	// An imported grant takes all privileges.
+	imported, err := testFakeImport(t, r, meta, "r.d.s.t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{
+		"id":           "R.D.S.T",
+		"privileges.#": "3",
+		"privileges.0": "INSERT",
+		"privileges.2": "UPDATE",
+	})
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "REVOKE INSERT, SELECT ON D.S.T FROM ROLE R")
+
+	raw["table"] = "all"
+	raw["grantee_role"] = nil
+	raw["grantee_share"] = "sh"
+	if _, err = testFakeApply(t, r, meta, nil, raw); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "GRANT INSERT, SELECT ON ALL TABLES IN SCHEMA D.S TO SHARE SH")
+}
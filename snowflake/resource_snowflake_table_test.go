@@ -0,0 +1,143 @@
+package snowflake
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func testAccShowTable(c Client, id string) error {
+	names, err := decodeID(id, "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	_, err = c.ShowTable(names[0], names[1], names[2])
+	return err
+}
+
+func TestAccTable(t *testing.T) {
+	database := testAccName()
+	name := testAccName()
+	newName := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_table", testAccShowTable),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTableConfig(database, name, "NUMBER(38,0)"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_table.test", testAccShowTable),
+					resource.TestCheckResourceAttr("snowflake_table.test", "id", database+".PUBLIC."+name),
+					resource.TestCheckResourceAttr("snowflake_table.test", "name", name),
+					resource.TestCheckResourceAttr("snowflake_table.test", "columns.#", "2"),
+					resource.TestCheckResourceAttr("snowflake_table.test", "columns.0.name", "ID"),
+					resource.TestCheckResourceAttr("snowflake_table.test", "columns.0.type", "NUMBER(38,0)"),
+					resource.TestCheckResourceAttr("snowflake_table.test", "columns.0.default", "1"),
+					resource.TestCheckResourceAttr("snowflake_table.test", "columns.1.name", "\"description\""),
+					resource.TestCheckResourceAttr("snowflake_table.test", "columns.1.type", "VARCHAR(16777216)"),
+				),
+			},
+			// Renamed in place.
+			{
+				Config: testAccTableConfig(database, newName, "NUMBER(38,0)"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_table.test", testAccShowTable),
+					resource.TestCheckResourceAttr("snowflake_table.test", "id", database+".PUBLIC."+newName),
+				),
+			},
+			// Replaced.
+			{
+				Config: testAccTableConfig(database, newName, "NUMBER(10,0)"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_table.test", testAccShowTable),
+					resource.TestCheckResourceAttr("snowflake_table.test", "columns.0.type", "NUMBER(10,0)"),
+				),
+			},
+			{
+				ResourceName:      "snowflake_table.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Dropped outside of Terraform.
+			{
+				Config:             testAccTableConfig(database, newName, "NUMBER(10,0)"),
+				Check:              testAccExec(fmt.Sprintf("DROP TABLE %s.PUBLIC.%s", database, newName)),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccTableConfig(database string, name string, idType string) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_table" "test" {
+  database = "${snowflake_database.test.name}"
+  schema   = "PUBLIC"
+  name     = "%s"
+
+  columns {
+    name    = "ID"
+    type    = "%s"
+    default = "1"
+  }
+
+  columns {
+    name = "\"description\""
+    type = "VARCHAR(16777216)"
+  }
+}
+`, database, name, idType)
+}
+
+func TestTableFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeTable()
+	c.rows["ShowTable D S T"] = showTableRow{Name: "T", DatabaseName: "D", SchemaName: "S"}
+	c.rows["DescTable D S T"] = []descTableRow{
+		{ColName: "ID", ColType: "NUMBER(38,0)"},
+		{ColName: "name", ColType: "VARCHAR(16777216)", DefaultValue: sql.NullString{String: "'X'", Valid: true}},
+	}
+	c.objects["tables T IN SCHEMA D.S"] = true
+	columns := []interface{}{
+		map[string]interface{}{"name": "id", "type": "number(38,0)"},
+		map[string]interface{}{"name": `"name"`, "type": "varchar", "default": "'X'"},
+	}
+
+	state, err := testFakeApply(t, r, meta, nil, map[string]interface{}{"database": "d", "schema": "s", "name": "t", "columns": columns})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, `CREATE TABLE D.S.T ( ID number(38,0),"name" varchar default 'X' )`)
+	testCheckAttributes(t, state, map[string]string{"id": "D.S.T", "columns.#": "2", "columns.1.name": `"name"`})
+
+	state, err = testFakeApply(t, r, meta, state, map[string]interface{}{"database": "d", "schema": "s", "name": "t2", "columns": columns})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "ALTER TABLE D.S.T RENAME TO D.S.T2")
+	testCheckAttributes(t, state, map[string]string{"id": "D.S.T2", "name": "T2"})
+
+	imported, err := testFakeImport(t, r, meta, "D.S.T")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{
+		"name":              "T",
+		"columns.#":         "2",
+		"columns.0.type":    "NUMBER(38,0)",
+		"columns.1.name":    `"name"`,
+		"columns.1.default": "'X'",
+	})
+
+	c.objects["tables T2 IN SCHEMA D.S"] = true
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "DROP TABLE D.S.T2")
+}
@@ -2,12 +2,12 @@ package snowflake
 
 import (
 	"crypto/sha256"
-	"database/sql"
 	b64 "encoding/base64"
 	"fmt"
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
 )
 
 func getKeyFingerprint(key string) string {
@@ -21,24 +21,22 @@ func getKeyFingerprint(key string) string {
 }
 
 func resourceSnowflakeUser() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceSnowflakeUserCreate,
 		Read:   resourceSnowflakeUserRead,
 		Update: resourceSnowflakeUserUpdate,
 		Delete: resourceSnowflakeUserDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				d.SetId(strings.ToUpper(d.Id()))
-				return []*schema.ResourceData{d}, nil
-			},
+			State: importStateID("name"),
 		},
+		SchemaVersion: idSchemaVersion,
 		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    identifierStateFunc,
+				ValidateFunc: validateIdentifier,
 			},
 			"login_name": {
 				Type:     schema.TypeString,
@@ -76,39 +74,28 @@ func resourceSnowflakeUser() *schema.Resource {
 			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(r, "name")
+	return r
 }
 
 func resourceSnowflakeUserCreate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	name := strings.ToUpper(d.Get("name").(string))
-	login_name := strings.ToUpper(d.Get("login_name").(string))
-	email := strings.ToUpper(d.Get("email").(string))
-	must_change_password := d.Get("must_change_password").(bool)
-	default_role := strings.ToUpper(d.Get("default_role").(string))
-	default_warehouse := strings.ToUpper(d.Get("default_warehouse").(string))
-	rsa_public_key := d.Get("rsa_public_key").(string)
-
-	statement := fmt.Sprintf("CREATE USER %v", name)
-	if must_change_password == true {
-		statement += fmt.Sprintf(" MUST_CHANGE_PASSWORD = TRUE")
-	}
-	if login_name != "" {
-		statement += fmt.Sprintf(" LOGIN_NAME = '%s'", login_name)
-	}
-	if email != "" {
-		statement += fmt.Sprintf(" EMAIL = '%s'", email)
-	}
-	if default_role != "" {
-		statement += fmt.Sprintf(" DEFAULT_ROLE = '%s'", default_role)
-	}
-	if default_warehouse != "" {
-		statement += fmt.Sprintf(" DEFAULT_WAREHOUSE = '%s'", default_warehouse)
-	}
-	if rsa_public_key != "" {
-		statement += fmt.Sprintf(" RSA_PUBLIC_KEY = '%s'", rsa_public_key)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
 	}
+	defer db.Close()
+	name := canonicalIdentifier(d.Get("name").(string))
+
+	statement := snowsql.NewCreateBuilder("USER", identifier(name)).
+		SetBool("MUST_CHANGE_PASSWORD", d.Get("must_change_password").(bool)).
+		SetString("LOGIN_NAME", strings.ToUpper(d.Get("login_name").(string))).
+		SetString("EMAIL", strings.ToUpper(d.Get("email").(string))).
+		SetString("DEFAULT_ROLE", strings.ToUpper(d.Get("default_role").(string))).
+		SetString("DEFAULT_WAREHOUSE", strings.ToUpper(d.Get("default_warehouse").(string))).
+		SetString("RSA_PUBLIC_KEY", d.Get("rsa_public_key").(string)).
+		Statement()
 
-	_, err := db.Exec(statement)
+	_, err = db.Exec(statement)
 	if err != nil {
 		return err
 	}
@@ -117,32 +104,40 @@ func resourceSnowflakeUserCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceSnowflakeUserRead(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	name := d.Id()
-	userInfo, err := descUser(db, name)
-	d.Set("name", userInfo.name)
+	userInfo, err := db.DescUser(name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	d.Set("name", storedIdentifier(userInfo.name))
 	d.Set("login_name", userInfo.login_name)
 	d.Set("email", userInfo.email)
-	d.Set("must_change_password", userInfo.must_change_password)
+	d.Set("must_change_password", userInfo.must_change_password == "true")
 	d.Set("default_role", userInfo.default_role)
 	d.Set("default_warehouse", userInfo.default_warehouse)
 	d.Set("rsa_public_key", userInfo.rsa_public_key)
-	if err != nil {
-		return err
-	}
 	return nil
 }
 
 func resourceSnowflakeUserUpdate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	name := d.Id()
-	exists, err := sqlObjExists(db, "users", name, "account")
+	exists, err := db.ObjectExists("users", name, inAccount)
 
 	if err != nil {
 		return err
 	}
 	if exists == false {
-		return fmt.Errorf("User %s does not exist", d.Id())
+		return newNotFoundError("User", d.Id())
 	}
 	// Rather than issue a single alter user statement for all possible
 	// changes issue an alter for each possible thing that has changed. Enable
@@ -150,59 +145,59 @@ func resourceSnowflakeUserUpdate(d *schema.ResourceData, meta interface{}) error
 	d.Partial(true)
 	if d.HasChange("name") {
 		// check that the rename target does not exist
-		exists, err := sqlObjExists(db, "users", d.Get("name").(string), "account")
+		exists, err := db.ObjectExists("users", d.Get("name").(string), inAccount)
 		if err != nil {
 			return err
 		}
 		if exists == true {
 			return fmt.Errorf("Cannot rename %v to %v, %v already exists", d.Id(), d.Get("name"), d.Get("name"))
 		}
-		statement := fmt.Sprintf("ALTER USER %v RENAME TO %v", d.Id(), d.Get("name"))
-		if _, err := db.Exec(statement); err != nil {
+		statement := snowsql.NewAlterBuilder("USER", identifier(d.Id())).Rename(identifier(d.Get("name").(string)))
+		if _, err = db.Exec(statement); err != nil {
 			return err
 		}
 		d.SetPartial("name")
-		d.SetId(d.Get("name").(string))
+		d.SetId(canonicalIdentifier(d.Get("name").(string)))
 
 	}
 	if d.HasChange("email") {
-		statement := fmt.Sprintf("ALTER USER %v SET EMAIL = '%v'", d.Id(), d.Get("email"))
-		if _, err := db.Exec(statement); err != nil {
+		b := snowsql.NewAlterBuilder("USER", identifier(d.Id())).SetString("EMAIL", strings.ToUpper(d.Get("email").(string)))
+		if err = db.ExecAll(b.Statements()); err != nil {
 			return err
 		}
 		d.SetPartial("email")
 	}
 	if d.HasChange("login_name") {
-		statement := fmt.Sprintf("ALTER USER %v SET LOGIN_NAME = '%v'", d.Id(), d.Get("login_name"))
-		if _, err := db.Exec(statement); err != nil {
+		b := snowsql.NewAlterBuilder("USER", identifier(d.Id())).SetString("LOGIN_NAME", strings.ToUpper(d.Get("login_name").(string)))
+		if err = db.ExecAll(b.Statements()); err != nil {
 			return err
 		}
 		d.SetPartial("login_name")
 	}
 	if d.HasChange("must_change_password") {
-		statement := fmt.Sprintf("ALTER USER %v SET MUST_CHANGE_PASSWORD = %v", d.Id(), d.Get("must_change_password"))
-		if _, err := db.Exec(statement); err != nil {
+		b := snowsql.NewAlterBuilder("USER", identifier(d.Id())).SetBool("MUST_CHANGE_PASSWORD", d.Get("must_change_password").(bool))
+		if err = db.ExecAll(b.Statements()); err != nil {
 			return err
 		}
 		d.SetPartial("must_change_password")
 	}
 	if d.HasChange("default_role") {
-		statement := fmt.Sprintf("ALTER USER %v SET DEFAULT_ROLE = '%v'", d.Id(), d.Get("default_role"))
-		if _, err := db.Exec(statement); err != nil {
+		b := snowsql.NewAlterBuilder("USER", identifier(d.Id())).SetString("DEFAULT_ROLE", strings.ToUpper(d.Get("default_role").(string)))
+		if err = db.ExecAll(b.Statements()); err != nil {
 			return err
 		}
 		d.SetPartial("default_role")
 	}
 	if d.HasChange("default_warehouse") {
-		statement := fmt.Sprintf("ALTER USER %v SET DEFAULT_WAREHOUSE = '%v'", d.Id(), d.Get("default_warehouse"))
-		if _, err := db.Exec(statement); err != nil {
+		b := snowsql.NewAlterBuilder("USER", identifier(d.Id())).SetString("DEFAULT_WAREHOUSE", strings.ToUpper(d.Get("default_warehouse").(string)))
+		if err = db.ExecAll(b.Statements()); err != nil {
 			return err
 		}
 		d.SetPartial("default_warehouse")
 	}
 	if d.HasChange("rsa_public_key") {
-		statement := fmt.Sprintf("ALTER USER %v SET RSA_PUBLIC_KEY = '%v'", d.Id(), d.Get("rsa_public_key"))
-		if _, err := db.Exec(statement); err != nil {
+		b := snowsql.NewAlterBuilder("USER", identifier(d.Id())).SetString("RSA_PUBLIC_KEY", d.Get("rsa_public_key").(string))
+		if err = db.ExecAll(b.Statements()); err != nil {
 			return err
 		}
 		d.SetPartial("rsa_public_key")
@@ -213,17 +208,22 @@ func resourceSnowflakeUserUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceSnowflakeUserDelete(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	name := d.Id()
-	exists, err := sqlObjExists(db, "users", name, "account")
+	exists, err := db.ObjectExists("users", name, inAccount)
 	if err != nil {
 		return err
 	}
 	if exists == false {
-		return fmt.Errorf("USER %s does not exist", d.Id())
+		// Already dropped outside of Terraform.
+		return nil
 	}
-	statement := fmt.Sprintf("DROP USER %v", d.Get("name"))
-	if _, err := db.Exec(statement); err != nil {
+	statement := snowsql.Drop("USER", identifier(name))
+	if _, err = db.Exec(statement); err != nil {
 		return err
 	}
 	return nil
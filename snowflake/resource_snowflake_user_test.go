@@ -0,0 +1,140 @@
+package snowflake
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+// RSA public keys of users, base64 encoded DER without the PEM armor.
+const (
+	testAccUserPublicKey  = "MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAwJHtSxVw/algFGOytJ2ZPjM7FpSQZaP8C72QwJvT+b+px8zrYbBlb5cXDlqSc1sgB4emul+vKmRLDg+iJR4gcpSt/itm3w0skfSsb5DXwA6GViYhaxitykKvG05QHRnCyPLmf6W3NiiGkOd/CkBzFVfZI4HdBh0SEGSprdP658jXj7G/Qbcj7Ai7ChUpR4BiGlEAR6VJXiXfFZvkULToRLYtproq40iR3jfnkyG2yZR9scL7Hu4lEL37z6bBaw+Suj1WntkBjD6gR/5+QKPRyOd4Ndra34kBqGqyDBisd9K4RT1yeNTZKBFV0TcVnn6cWL6gEdCB3wesANShsZSeVQIDAQAB"
+	testAccUserPublicKey2 = "MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA4UHVlCt90Kxo7Eww+5AUXpJImPIeg2hZJwOuGBubk/XFZU4Sr9nC4Ddkv3yaEbzGhtzQ/oYB8IBRzX9MMK5rzIMR9r1PAiEu1sYGlKnhrieZAt3p94ovZzxIDiqzyLiWGlQ/I0Vs55u7GdHLzB+Gm3G/GF3NlE97ugVm75ACngIMNBg7CxFfuHlQQJ6Ut6JGYY5MV4SSzytCEVOVZiYlM3/tb6kqVMUa/jsef3loUXXfcIjtsIGxQ+7c91D8v576BbwdiDDLNzQQK1+4am6d033APaOFef1V1KYKw3+S9ay7OLueO/ZkPAcY1JDogWuDRnsJCFXjrFD4b8ZmMEv8mQIDAQAB"
+)
+
+func testAccShowUser(c Client, id string) error {
+	_, err := c.DescUser(id)
+	return err
+}
+
+func TestAccUser(t *testing.T) {
+	name := testAccName()
+	newName := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_user", testAccShowUser),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig(name, "created@example.com", "PUBLIC", testAccUserPublicKey),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_user.test", testAccShowUser),
+					resource.TestCheckResourceAttr("snowflake_user.test", "id", name),
+					resource.TestCheckResourceAttr("snowflake_user.test", "name", name),
+					resource.TestCheckResourceAttr("snowflake_user.test", "login_name", name+"_LOGIN"),
+					resource.TestCheckResourceAttr("snowflake_user.test", "email", "CREATED@EXAMPLE.COM"),
+					resource.TestCheckResourceAttr("snowflake_user.test", "must_change_password", "false"),
+					resource.TestCheckResourceAttr("snowflake_user.test", "default_role", "PUBLIC"),
+					resource.TestCheckResourceAttr("snowflake_user.test", "rsa_public_key", getKeyFingerprint(testAccUserPublicKey)),
+				),
+			},
+			// Renamed and altered in place.
+			{
+				Config: testAccUserConfig(newName, "updated@example.com", "SYSADMIN", testAccUserPublicKey2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_user.test", testAccShowUser),
+					resource.TestCheckResourceAttr("snowflake_user.test", "id", newName),
+					resource.TestCheckResourceAttr("snowflake_user.test", "login_name", newName+"_LOGIN"),
+					resource.TestCheckResourceAttr("snowflake_user.test", "email", "UPDATED@EXAMPLE.COM"),
+					resource.TestCheckResourceAttr("snowflake_user.test", "default_role", "SYSADMIN"),
+					resource.TestCheckResourceAttr("snowflake_user.test", "rsa_public_key", getKeyFingerprint(testAccUserPublicKey2)),
+				),
+			},
+			{
+				ResourceName:      "snowflake_user.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Dropped outside of Terraform.
+			{
+				Config:             testAccUserConfig(newName, "updated@example.com", "SYSADMIN", testAccUserPublicKey2),
+				Check:              testAccExec("DROP USER " + newName),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccUserConfig(name string, email string, defaultRole string, publicKey string) string {
+	return fmt.Sprintf(`
+resource "snowflake_user" "test" {
+  name           = "%s"
+  login_name     = "%s_login"
+  email          = "%s"
+  default_role   = "%s"
+  rsa_public_key = "%s"
+}
+`, name, name, email, defaultRole, publicKey)
+}
+
+func TestUserFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeUser()
+	c.rows["DescUser U"] = descUserResult{
+		name:                 "U",
+		login_name:           "LOGIN",
+		email:                "U@EXAMPLE.COM",
+		must_change_password: "true",
+		default_role:         "PUBLIC",
+	}
+	c.objects["users U IN ACCOUNT"] = true
+
+	state, err := testFakeApply(t, r, meta, nil, map[string]interface{}{
+		"name":                 "u",
+		"login_name":           "login",
+		"email":                "u@example.com",
+		"must_change_password": true,
+		"default_role":         "public",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "CREATE USER U MUST_CHANGE_PASSWORD = TRUE LOGIN_NAME = 'LOGIN' EMAIL = 'U@EXAMPLE.COM' DEFAULT_ROLE = 'PUBLIC'")
+	testCheckAttributes(t, state, map[string]string{"id": "U", "login_name": "LOGIN", "email": "U@EXAMPLE.COM"})
+
+	state, err = testFakeApply(t, r, meta, state, map[string]interface{}{
+		"name":              "u",
+		"login_name":        "login",
+		"default_role":      "public",
+		"default_warehouse": "wh",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c,
+		"ALTER USER U UNSET EMAIL",
+		"ALTER USER U SET MUST_CHANGE_PASSWORD = FALSE",
+		"ALTER USER U SET DEFAULT_WAREHOUSE = 'WH'",
+	)
+
+	state, err = r.Refresh(state, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, state, map[string]string{
+		"must_change_password": "true",
+		"email":                "U@EXAMPLE.COM",
+		"default_role":         "PUBLIC",
+	})
+
+	imported, err := testFakeImport(t, r, meta, "u")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{"id": "U", "name": "U", "login_name": "LOGIN"})
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "DROP USER U")
+}
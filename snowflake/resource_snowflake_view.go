@@ -1,131 +1,145 @@
 package snowflake
 
 import (
-	"database/sql"
-	"fmt"
 	"regexp"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
 )
 
-var reViewPrefix = regexp.MustCompile(`(?i)^create (or replace )?view .* as\n`)
+var reViewPrefix = regexp.MustCompile(`(?i)^create (or replace )?(secure )?view .* as\n`)
+
+// viewQuery returns the query of the view defined by definition, which is
+// either a CREATE VIEW statement or the query itself.
+func viewQuery(definition string) string {
+	if loc := reViewPrefix.FindStringIndex(definition); loc != nil {
+		return definition[loc[1]:]
+	}
+	return definition
+}
 
 func resourceSnowflakeView() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceSnowflakeViewCreate,
 		Read:   resourceSnowflakeViewRead,
+		Update: executionRoleUpdate,
 		Delete: resourceSnowflakeViewDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				d.SetId(strings.ToUpper(d.Id()))
-				return []*schema.ResourceData{d}, nil
-			},
+			State: importStateID("database", "schema", "name"),
 		},
+		SchemaVersion: idSchemaVersion,
 		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
 			},
 			"database": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
 			},
 			"schema": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
 			},
 			"view_definition": {
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
 				StateFunc: func(v interface{}) string {
-					viewDefinition := v.(string)
-					createViewPos := reViewPrefix.FindStringIndex(viewDefinition)
-					if createViewPos != nil {
-						return viewDefinition[createViewPos[1]:]
-					}
-					return viewDefinition
+					return viewQuery(v.(string))
 				},
 			},
 			"comment": {
 				Type:     schema.TypeString,
 				Optional: true,
 				ForceNew: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
 			},
 			"secure": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				ForceNew: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
 			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(r, "database", "schema", "name")
+	return r
 }
 
 func resourceSnowflakeViewCreate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	database := d.Get("database")
-	schema := d.Get("schema")
-	name := d.Get("name")
-	viewID := strings.ToUpper(fmt.Sprintf("%s.%s.%s", database, schema, name))
-	viewDefinition := d.Get("view_definition").(string)
-	statement := fmt.Sprintf("create view %s.%s.%s as\n%s", database, schema, name, viewDefinition)
-	_, err := db.Exec(statement)
+	db, err := newClient(meta, d)
 	if err != nil {
 		return err
 	}
-	d.SetId(strings.ToUpper(viewID))
+	defer db.Close()
+	database := d.Get("database").(string)
+	schema := d.Get("schema").(string)
+	name := d.Get("name").(string)
+	viewID := encodeID(database, schema, name)
+	statement := snowsql.NewCreateBuilder("VIEW", qualifiedName(database, schema, name)).
+		Modifier("SECURE", d.Get("secure").(bool)).
+		SetString("COMMENT", d.Get("comment").(string)).
+		As(d.Get("view_definition").(string)).
+		Statement()
+	_, err = db.Exec(statement)
+	if err != nil {
+		return err
+	}
+	d.SetId(viewID)
 	return nil
 }
 
 func resourceSnowflakeViewRead(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	s := strings.Split(d.Id(), ".")
-	database, schema, name := s[0], s[1], s[2]
-	t, err := readView(db, database, schema, name)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
 	if err != nil {
 		return err
 	}
-	d.Set("name", t.tableName)
-	d.Set("database", t.tableCatalog)
-	d.Set("schema", t.tableSchema)
-	d.Set("comment", t.comment)
-	d.Set("secure", t.isSecure == "YES")
-	d.Set("view_definition", t.viewDefinition[reViewPrefix.FindStringIndex(t.viewDefinition)[1]:])
+	database, schema, name := id[0], id[1], id[2]
+	t, err := db.ReadView(database, schema, name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	d.Set("name", storedIdentifier(t.TableName))
+	d.Set("database", storedIdentifier(t.TableCatalog))
+	d.Set("schema", storedIdentifier(t.TableSchema))
+	d.Set("comment", t.Comment)
+	d.Set("secure", t.IsSecure == "YES")
+	d.Set("view_definition", viewQuery(t.ViewDefinition))
 	return nil
 }
 
 func resourceSnowflakeViewDelete(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	s := strings.Split(d.Id(), ".")
-	database, schema, name := s[0], s[1], s[2]
-	exists, err := sqlObjExists(db, "views", name, fmt.Sprintf("%s.%s", database, schema))
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	database, schema, name := id[0], id[1], id[2]
+	exists, err := db.ObjectExists("views", name, inSchema(database, schema))
 	if err != nil {
 		return err
 	}
 	if exists == false {
-		return fmt.Errorf("View %s does not exist", d.Id())
+		// Already dropped outside of Terraform.
+		return nil
 	}
-	statement := fmt.Sprintf("DROP VIEW %s", d.Id())
-	if _, err := db.Exec(statement); err != nil {
+	statement := snowsql.Drop("VIEW", qualifiedName(database, schema, name))
+	if _, err = db.Exec(statement); err != nil {
 		return err
 	}
 	return nil
@@ -1,7 +1,6 @@
 package snowflake
 
 import (
-	"database/sql"
 	"fmt"
 	"strings"
 
@@ -9,40 +8,34 @@ import (
 )
 
 func resourceSnowflakeViewGrant() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceSnowflakeViewGrantCreate,
 		Read:   resourceSnowflakeViewGrantRead,
+		Update: executionRoleUpdate,
 		Delete: resourceSnowflakeViewGrantDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				d.SetId(strings.ToUpper(d.Id()))
-				return []*schema.ResourceData{d}, nil
-			},
+			State: importStateID("grantee", "database", "schema", "view"),
 		},
+		SchemaVersion: idSchemaVersion,
 		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
 			"view": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
-				ForceNew: true,
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: identifierStateFunc,
+				ForceNew:  true,
 			},
 			"database": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
-				ForceNew: true,
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: identifierStateFunc,
+				ForceNew:  true,
 			},
 			"schema": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
-				ForceNew: true,
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: identifierStateFunc,
+				ForceNew:  true,
 			},
 			"privileges": {
 				Type: schema.TypeList,
@@ -51,54 +44,42 @@ func resourceSnowflakeViewGrant() *schema.Resource {
 					StateFunc: func(v interface{}) string {
 						return strings.ToUpper(v.(string))
 					},
+					ValidateFunc: validatePrivilege,
 				},
 				Required: true,
 				ForceNew: true,
 			},
 			"grantee_role": {
-				Type:     schema.TypeString,
-				Required: true,
-				StateFunc: func(v interface{}) string {
-					return strings.ToUpper(v.(string))
-				},
-				ForceNew: true,
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: identifierStateFunc,
+				ForceNew:  true,
 			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(r, "grantee", "database", "schema", "view")
+	return r
 }
 
 func resourceSnowflakeViewGrantCreate(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
-	view := strings.ToUpper(d.Get("view").(string))
-	database := strings.ToUpper(d.Get("database").(string))
-	schema := strings.ToUpper(d.Get("schema").(string))
-	granteeRole := strings.ToUpper(d.Get("grantee_role").(string))
-
-	id := fmt.Sprintf("%v.%v.%v.%v.", granteeRole, database, schema, view)
-
-	statement := "GRANT "
-
-	for _, p := range d.Get("privileges").([]interface{}) {
-		statement += p.(string)
-		statement += ", "
-		id += fmt.Sprintf("%v.", p)
-	}
-	statement = strings.Trim(statement, ", ")
-	id = strings.Trim(id, ".")
-
-	if view == "ALL" {
-		statement += fmt.Sprintf(" ON ALL VIEWS IN %v.%v", database, schema)
-	} else {
-		statement += fmt.Sprintf(" ON %v.%v.%v TO ", database, schema, view)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
 	}
+	defer db.Close()
+	view := canonicalIdentifier(d.Get("view").(string))
+	database := canonicalIdentifier(d.Get("database").(string))
+	schema := canonicalIdentifier(d.Get("schema").(string))
+	granteeRole := canonicalIdentifier(d.Get("grantee_role").(string))
 
-	statement += fmt.Sprintf("ROLE %v", granteeRole)
-
+	id := encodeID(granteeRole, database, schema, view)
 
-	statement = strings.ToUpper(statement)
-
-	_, err := db.Exec(statement)
-	if err != nil {
+	on := qualifiedName(database, schema, view)
+	if resolvedName(view) == "ALL" {
+		on = fmt.Sprintf("ALL VIEWS IN SCHEMA %s", qualifiedName(database, schema))
+	}
+	to := fmt.Sprintf("ROLE %s", identifier(granteeRole))
+	if err = db.Grant(expandPrivileges(d), on, to); err != nil {
 		return err
 	}
 
@@ -107,43 +88,62 @@ func resourceSnowflakeViewGrantCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceSnowflakeViewGrantRead(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	grantID := d.Id()
-	s := strings.Split(grantID, ".")
-	grantee, database, schema, view := s[0], s[1], s[2], s[3]
-	ViewGrantInfoResult, err := showViewGrant(db, grantee, database, schema, view)
+	id, err := decodeID(grantID, "grantee", "database", "schema", "view")
+	if err != nil {
+		return err
+	}
+	grantee, database, schema, view := id[0], id[1], id[2], id[3]
+	ViewGrantInfoResult, err := db.ShowViewGrant(grantee, database, schema, view)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	if resolvedName(view) == "ALL" && len(ViewGrantInfoResult.privileges) == 0 {
+		// The schema has no views yet, none of them lacks a privilege.
+		ViewGrantInfoResult.privileges = expandPrivileges(d)
+	}
 
-	d.Set("privileges", ViewGrantInfoResult.privileges)
-	d.Set("granteeRole", ViewGrantInfoResult.granteeRole)
+	d.Set("privileges", managedPrivileges(d, ViewGrantInfoResult.privileges))
+	d.Set("grantee_role", ViewGrantInfoResult.granteeRole)
 	d.Set("view", view)
 	d.Set("schema", schema)
 	d.Set("database", database)
 
-	return err
+	return nil
 }
 
 func resourceSnowflakeViewGrantDelete(d *schema.ResourceData, meta interface{}) error {
-	db := meta.(*sql.DB)
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 	grantID := d.Id()
-	s := strings.Split(grantID, ".")
-	granteeRole, database, schema, view := s[0], s[1], s[2], s[3]
-
-	statement := "REVOKE "
-
-	for index, val := range s {
-		if index >= 4 {
-			statement += fmt.Sprintf("%v, ", val)
-		}
+	id, err := decodeID(grantID, "grantee", "database", "schema", "view")
+	if err != nil {
+		return err
 	}
+	granteeRole, database, schema, view := id[0], id[1], id[2], id[3]
 
-	statement = strings.Trim(statement, ", ")
-	statement += fmt.Sprintf(" ON %v.%v.%v FROM ROLE %v", database, schema, view, granteeRole)
-
-
-	statement = strings.ToUpper(statement)
-
-	_, err := db.Exec(statement)
-	if err != nil {
+	on := qualifiedName(database, schema, view)
+	if resolvedName(view) == "ALL" {
+		on = fmt.Sprintf("ALL VIEWS IN SCHEMA %s", qualifiedName(database, schema))
+	}
+	from := fmt.Sprintf("ROLE %s", identifier(granteeRole))
+	privileges := grantedPrivileges(d)
+	if len(privileges) == 0 {
+		// Already revoked outside of Terraform.
+		return nil
+	}
+	// Revoking from an object or grantee dropped outside of Terraform fails,
+	// the grant is gone with it.
+	err = db.Revoke(privileges, on, from)
+	if err != nil && !isNotFound(err) {
 		return err
 	}
 	return nil
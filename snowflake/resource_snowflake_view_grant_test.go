@@ -0,0 +1,177 @@
+package snowflake
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func testAccShowViewGrant(c Client, id string) error {
+	names, err := decodeID(id, "grantee", "database", "schema", "view")
+	if err != nil {
+		return err
+	}
+	_, err = c.ShowViewGrant(names[0], names[1], names[2], names[3])
+	return err
+}
+
+func TestAccViewGrant(t *testing.T) {
+	database := testAccName()
+	role := testAccName()
+	id := role + "." + database + ".PUBLIC.REPORT"
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_view_grant", testAccShowViewGrant),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccViewGrantConfig(database, role, "SELECT"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_view_grant.test", testAccShowViewGrant),
+					resource.TestCheckResourceAttr("snowflake_view_grant.test", "id", id),
+					resource.TestCheckResourceAttr("snowflake_view_grant.test", "grantee_role", role),
+					resource.TestCheckResourceAttr("snowflake_view_grant.test", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("snowflake_view_grant.test", "privileges.0", "SELECT"),
+				),
+			},
+			// Replaced.
+			{
+				Config: testAccViewGrantConfig(database, role, "REFERENCES"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_view_grant.test", testAccShowViewGrant),
+					resource.TestCheckResourceAttr("snowflake_view_grant.test", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("snowflake_view_grant.test", "privileges.0", "REFERENCES"),
+				),
+			},
+			{
+				ResourceName:      "snowflake_view_grant.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Revoked outside of Terraform.
+			{
+				Config:             testAccViewGrantConfig(database, role, "REFERENCES"),
+				Check:              testAccExec(fmt.Sprintf("REVOKE REFERENCES ON VIEW %s.PUBLIC.REPORT FROM ROLE %s", database, role)),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccViewGrantConfig(database string, role string, privilege string) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_view" "test" {
+  database        = "${snowflake_database.test.name}"
+  schema          = "PUBLIC"
+  name            = "REPORT"
+  view_definition = "select 1 as one"
+}
+
+resource "snowflake_role" "test" {
+  name = "%s"
+}
+
+resource "snowflake_view_grant" "test" {
+  database     = "${snowflake_view.test.database}"
+  schema       = "${snowflake_view.test.schema}"
+  view         = "${snowflake_view.test.name}"
+  privileges   = ["%s"]
+  grantee_role = "${snowflake_role.test.name}"
+}
+`, database, role, privilege)
+}
+
+// A grant on ALL VIEWS reads back as the privileges granted on every view
+// of the schema.
+func TestAccViewGrantAll(t *testing.T) {
+	database := testAccName()
+	role := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_view_grant", testAccShowViewGrant),
+		Steps: []resource.TestStep{
+			// The schema has no VIEWS, none of them lacks SELECT.
+			{
+				Config: testAccViewGrantAllConfig(database, role),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("snowflake_view_grant.test", "id", role+"."+database+`.PUBLIC."ALL"`),
+					resource.TestCheckResourceAttr("snowflake_view_grant.test", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("snowflake_view_grant.test", "privileges.0", "SELECT"),
+				),
+			},
+			// A view created afterwards lacks SELECT.
+			{
+				Config:             testAccViewGrantAllConfig(database, role),
+				Check:              testAccExec(fmt.Sprintf("CREATE VIEW %s.PUBLIC.REPORT AS SELECT 1 AS ONE", database)),
+				ExpectNonEmptyPlan: true,
+			},
+			// Granting again covers it.
+			{
+				Config: testAccViewGrantAllConfig(database, role),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_view_grant.test", testAccShowViewGrant),
+					resource.TestCheckResourceAttr("snowflake_view_grant.test", "privileges.0", "SELECT"),
+				),
+			},
+		},
+	})
+}
+
+func testAccViewGrantAllConfig(database string, role string) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_role" "test" {
+  name = "%s"
+}
+
+resource "snowflake_view_grant" "test" {
+  database     = "${snowflake_database.test.name}"
+  schema       = "PUBLIC"
+  view         = "ALL"
+  privileges   = ["SELECT"]
+  grantee_role = "${snowflake_role.test.name}"
+}
+`, database, role)
+}
+
+func TestViewGrantFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeViewGrant()
+	c.rows["ShowViewGrant R D S V"] = showViewGrantResult{
+		database:    "D",
+		schema:      "S",
+		view:        "V",
+		granteeRole: "R",
+		privileges:  []string{"SELECT"},
+	}
+
+	state, err := testFakeApply(t, r, meta, nil, map[string]interface{}{
+		"database":     "d",
+		"schema":       "s",
+		"view":         "v",
+		"privileges":   []interface{}{"select"},
+		"grantee_role": "r",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "GRANT SELECT ON D.S.V TO ROLE R")
+	testCheckAttributes(t, state, map[string]string{"id": "R.D.S.V"})
+
+	imported, err := testFakeImport(t, r, meta, "R.D.S.V")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{"view": "V", "grantee_role": "R", "privileges.0": "SELECT"})
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "REVOKE SELECT ON D.S.V FROM ROLE R")
+}
@@ -0,0 +1,142 @@
+package snowflake
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func testAccShowView(c Client, id string) error {
+	names, err := decodeID(id, "database", "schema", "name")
+	if err != nil {
+		return err
+	}
+	_, err = c.ReadView(names[0], names[1], names[2])
+	return err
+}
+
+func TestAccView(t *testing.T) {
+	database := testAccName()
+	name := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_view", testAccShowView),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccViewConfig(database, name, "created", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_view.test", testAccShowView),
+					resource.TestCheckResourceAttr("snowflake_view.test", "id", database+".PUBLIC."+name),
+					resource.TestCheckResourceAttr("snowflake_view.test", "name", name),
+					resource.TestCheckResourceAttr("snowflake_view.test", "comment", "created"),
+					resource.TestCheckResourceAttr("snowflake_view.test", "secure", "false"),
+					resource.TestCheckResourceAttr("snowflake_view.test", "view_definition", "select 1 as one"),
+				),
+			},
+			// Replaced.
+			{
+				Config: testAccViewConfig(database, name, "replaced", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_view.test", testAccShowView),
+					resource.TestCheckResourceAttr("snowflake_view.test", "comment", "replaced"),
+					resource.TestCheckResourceAttr("snowflake_view.test", "secure", "true"),
+				),
+			},
+			{
+				ResourceName:      "snowflake_view.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Dropped outside of Terraform.
+			{
+				Config:             testAccViewConfig(database, name, "replaced", true),
+				Check:              testAccExec(fmt.Sprintf("DROP VIEW %s.PUBLIC.%s", database, name)),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccViewConfig(database string, name string, comment string, secure bool) string {
+	return fmt.Sprintf(`
+resource "snowflake_database" "test" {
+  name = "%s"
+}
+
+resource "snowflake_view" "test" {
+  database        = "${snowflake_database.test.name}"
+  schema          = "PUBLIC"
+  name            = "%s"
+  comment         = "%s"
+  secure          = %t
+  view_definition = "select 1 as one"
+}
+`, database, name, comment, secure)
+}
+
+func TestViewFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeView()
+	c.rows["ReadView D S V"] = infoSchemaView{
+		TableName:      "V",
+		TableCatalog:   "D",
+		TableSchema:    "S",
+		ViewDefinition: "CREATE SECURE VIEW D.S.V COMMENT = 'it''s' AS\nSELECT 1",
+		IsSecure:       "YES",
+		Comment:        "it's",
+	}
+	c.objects["views V IN SCHEMA D.S"] = true
+
+	state, err := testFakeApply(t, r, meta, nil, map[string]interface{}{
+		"database":        "d",
+		"schema":          "s",
+		"name":            "v",
+		"comment":         "it's",
+		"secure":          true,
+		"view_definition": "SELECT 1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "CREATE SECURE VIEW D.S.V COMMENT = 'it''s' AS\nSELECT 1")
+	testCheckAttributes(t, state, map[string]string{"id": "D.S.V"})
+
+	imported, err := testFakeImport(t, r, meta, "d.s.v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{
+		"id":              "D.S.V",
+		"name":            "V",
+		"secure":          "true",
+		"comment":         "it's",
+		"view_definition": "SELECT 1",
+	})
+
+	// Views created outside Terraform may have a definition the CREATE VIEW
+	// prefix is not recognized in, it is kept as a whole.
+	c.rows["ReadView D S W"] = infoSchemaView{
+		TableName:      "W",
+		TableCatalog:   "D",
+		TableSchema:    "S",
+		ViewDefinition: "create view w as select 1",
+		IsSecure:       "NO",
+	}
+	c.objects["views W IN SCHEMA D.S"] = true
+	imported, err = testFakeImport(t, r, meta, "d.s.w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{
+		"id":              "D.S.W",
+		"name":            "W",
+		"secure":          "false",
+		"view_definition": "create view w as select 1",
+	})
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "DROP VIEW D.S.V")
+}
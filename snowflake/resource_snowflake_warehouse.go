@@ -0,0 +1,331 @@
+package snowflake
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
+)
+
+// warehouseSizes are the sizes a warehouse can have, in the form they are
+// kept in state.
+var warehouseSizes = []string{
+	"XSMALL", "SMALL", "MEDIUM", "LARGE", "XLARGE", "XXLARGE", "XXXLARGE", "X4LARGE", "X5LARGE", "X6LARGE",
+}
+
+// warehouseSizeAliases maps the other spellings Snowflake accepts or SHOW
+// WAREHOUSES returns, with dashes removed, onto warehouseSizes.
+var warehouseSizeAliases = map[string]string{
+	"2XLARGE": "XXLARGE",
+	"X2LARGE": "XXLARGE",
+	"3XLARGE": "XXXLARGE",
+	"X3LARGE": "XXXLARGE",
+	"4XLARGE": "X4LARGE",
+	"5XLARGE": "X5LARGE",
+	"6XLARGE": "X6LARGE",
+}
+
+// normalizeWarehouseSize returns the form a warehouse size is kept in state,
+// e.g. XSMALL for x-small and X-Small as returned by SHOW WAREHOUSES.
+func normalizeWarehouseSize(size string) string {
+	size = strings.ToUpper(strings.NewReplacer("-", "", "_", "", " ", "").Replace(size))
+	if alias, ok := warehouseSizeAliases[size]; ok {
+		return alias
+	}
+	return size
+}
+
+func validateWarehouseSize(v interface{}, k string) (ws []string, errors []error) {
+	size := normalizeWarehouseSize(v.(string))
+	for _, s := range warehouseSizes {
+		if s == size {
+			return nil, nil
+		}
+	}
+	return nil, []error{fmt.Errorf("%s must be one of %s, got %s", k, strings.Join(warehouseSizes, ", "), v)}
+}
+
+func resourceSnowflakeWarehouse() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceSnowflakeWarehouseCreate,
+		Read:   resourceSnowflakeWarehouseRead,
+		Update: resourceSnowflakeWarehouseUpdate,
+		Delete: resourceSnowflakeWarehouseDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStateID("name"),
+		},
+		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    identifierStateFunc,
+				ValidateFunc: validateIdentifier,
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"warehouse_size": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "XSMALL",
+				StateFunc: func(v interface{}) string {
+					return normalizeWarehouseSize(v.(string))
+				},
+				ValidateFunc: validateWarehouseSize,
+			},
+			// 0 means the warehouse never suspends.
+			"auto_suspend": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      600,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"auto_resume": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			// Only used when the warehouse is created, see state for whether
+			// it is running.
+			"initially_suspended": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"min_cluster_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"max_cluster_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"scaling_policy": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "STANDARD",
+				StateFunc: func(v interface{}) string {
+					return strings.ToUpper(v.(string))
+				},
+				ValidateFunc: validation.StringInSlice([]string{"STANDARD", "ECONOMY"}, true),
+			},
+			// Left out, the warehouse keeps the monitor it has, which the
+			// warehouses of a snowflake_resource_monitor may assign.
+			"resource_monitor": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				StateFunc: identifierStateFunc,
+			},
+			"statement_timeout_in_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      172800,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			// STARTED or SUSPENDED.
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceSnowflakeWarehouseCreate(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	name := canonicalIdentifier(d.Get("name").(string))
+
+	b := snowsql.NewCreateBuilder("WAREHOUSE", identifier(name)).
+		SetRaw("WAREHOUSE_SIZE", normalizeWarehouseSize(d.Get("warehouse_size").(string))).
+		SetInt("AUTO_SUSPEND", d.Get("auto_suspend").(int)).
+		SetBool("AUTO_RESUME", d.Get("auto_resume").(bool)).
+		SetBool("INITIALLY_SUSPENDED", d.Get("initially_suspended").(bool)).
+		SetInt("MIN_CLUSTER_COUNT", d.Get("min_cluster_count").(int)).
+		SetInt("MAX_CLUSTER_COUNT", d.Get("max_cluster_count").(int)).
+		SetRaw("SCALING_POLICY", strings.ToUpper(d.Get("scaling_policy").(string))).
+		SetInt("STATEMENT_TIMEOUT_IN_SECONDS", d.Get("statement_timeout_in_seconds").(int)).
+		SetString("COMMENT", d.Get("comment").(string))
+	if monitor := d.Get("resource_monitor").(string); monitor != "" {
+		b.SetRaw("RESOURCE_MONITOR", identifier(monitor))
+	}
+	_, err = db.Exec(b.Statement())
+	return createThenRead(d, meta, db, name, err, resourceSnowflakeWarehouseRead)
+}
+
+func resourceSnowflakeWarehouseRead(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	name := d.Id()
+	r, err := db.ShowWarehouse(name)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	d.Set("name", storedIdentifier(r.Name))
+	d.Set("comment", r.Comment)
+	d.Set("warehouse_size", normalizeWarehouseSize(r.Size))
+	d.Set("auto_suspend", r.AutoSuspend)
+	d.Set("auto_resume", r.AutoResume == "true")
+	d.Set("min_cluster_count", r.MinClusterCount)
+	d.Set("max_cluster_count", r.MaxClusterCount)
+	d.Set("scaling_policy", r.ScalingPolicy)
+	// SHOW WAREHOUSES returns the string null for warehouses without one.
+	if r.ResourceMonitor == "null" {
+		d.Set("resource_monitor", "")
+	} else {
+		d.Set("resource_monitor", storedIdentifier(r.ResourceMonitor))
+	}
+	d.Set("state", r.State)
+
+	p, err := db.ShowParameter("STATEMENT_TIMEOUT_IN_SECONDS", "WAREHOUSE "+identifier(name))
+	if err != nil {
+		return err
+	}
+	timeout, err := strconv.Atoi(p.Value)
+	if err != nil {
+		return err
+	}
+	d.Set("statement_timeout_in_seconds", timeout)
+	return nil
+}
+
+func resourceSnowflakeWarehouseUpdate(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	name := d.Id()
+	exists, err := db.ObjectExists("warehouses", name, inAccount)
+	if err != nil {
+		return err
+	}
+	if exists == false {
+		return newNotFoundError("Warehouse", d.Id())
+	}
+	// Rather than issue a single alter warehouse statement for all possible
+	// changes issue an alter for each possible thing that has changed. Enable
+	// partial mode.
+	d.Partial(true)
+	if d.HasChange("name") {
+		// check that the rename target does not exist
+		exists, err := db.ObjectExists("warehouses", d.Get("name").(string), inAccount)
+		if err != nil {
+			return err
+		}
+		if exists == true {
+			return fmt.Errorf("Cannot rename %v to %v, %v already exists", d.Id(), d.Get("name"), d.Get("name"))
+		}
+		statement := snowsql.NewAlterBuilder("WAREHOUSE", identifier(d.Id())).Rename(identifier(d.Get("name").(string)))
+		if _, err = db.Exec(statement); err != nil {
+			return err
+		}
+		d.SetPartial("name")
+		d.SetId(canonicalIdentifier(d.Get("name").(string)))
+	}
+	if d.HasChange("comment") {
+		b := snowsql.NewAlterBuilder("WAREHOUSE", identifier(d.Id())).SetString("COMMENT", d.Get("comment").(string))
+		if err = db.ExecAll(b.Statements()); err != nil {
+			return err
+		}
+		d.SetPartial("comment")
+	}
+	if d.HasChange("warehouse_size") {
+		b := snowsql.NewAlterBuilder("WAREHOUSE", identifier(d.Id())).SetRaw("WAREHOUSE_SIZE", normalizeWarehouseSize(d.Get("warehouse_size").(string)))
+		if err = db.ExecAll(b.Statements()); err != nil {
+			return err
+		}
+		d.SetPartial("warehouse_size")
+	}
+	if d.HasChange("auto_suspend") {
+		b := snowsql.NewAlterBuilder("WAREHOUSE", identifier(d.Id())).SetInt("AUTO_SUSPEND", d.Get("auto_suspend").(int))
+		if err = db.ExecAll(b.Statements()); err != nil {
+			return err
+		}
+		d.SetPartial("auto_suspend")
+	}
+	if d.HasChange("auto_resume") {
+		b := snowsql.NewAlterBuilder("WAREHOUSE", identifier(d.Id())).SetBool("AUTO_RESUME", d.Get("auto_resume").(bool))
+		if err = db.ExecAll(b.Statements()); err != nil {
+			return err
+		}
+		d.SetPartial("auto_resume")
+	}
+	// The cluster counts are set together, setting one at a time could leave
+	// the minimum above the maximum in between.
+	if d.HasChange("min_cluster_count") || d.HasChange("max_cluster_count") {
+		b := snowsql.NewAlterBuilder("WAREHOUSE", identifier(d.Id())).
+			SetInt("MIN_CLUSTER_COUNT", d.Get("min_cluster_count").(int)).
+			SetInt("MAX_CLUSTER_COUNT", d.Get("max_cluster_count").(int))
+		if err = db.ExecAll(b.Statements()); err != nil {
+			return err
+		}
+		d.SetPartial("min_cluster_count")
+		d.SetPartial("max_cluster_count")
+	}
+	if d.HasChange("scaling_policy") {
+		b := snowsql.NewAlterBuilder("WAREHOUSE", identifier(d.Id())).SetRaw("SCALING_POLICY", strings.ToUpper(d.Get("scaling_policy").(string)))
+		if err = db.ExecAll(b.Statements()); err != nil {
+			return err
+		}
+		d.SetPartial("scaling_policy")
+	}
+	if d.HasChange("resource_monitor") {
+		b := snowsql.NewAlterBuilder("WAREHOUSE", identifier(d.Id()))
+		if monitor := d.Get("resource_monitor").(string); monitor != "" {
+			b.SetRaw("RESOURCE_MONITOR", identifier(monitor))
+		} else {
+			b.Unset("RESOURCE_MONITOR")
+		}
+		if err = db.ExecAll(b.Statements()); err != nil {
+			return err
+		}
+		d.SetPartial("resource_monitor")
+	}
+	if d.HasChange("statement_timeout_in_seconds") {
+		b := snowsql.NewAlterBuilder("WAREHOUSE", identifier(d.Id())).SetInt("STATEMENT_TIMEOUT_IN_SECONDS", d.Get("statement_timeout_in_seconds").(int))
+		if err = db.ExecAll(b.Statements()); err != nil {
+			return err
+		}
+		d.SetPartial("statement_timeout_in_seconds")
+	}
+	d.Partial(false)
+	return nil
+}
+
+func resourceSnowflakeWarehouseDelete(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	name := d.Id()
+	exists, err := db.ObjectExists("warehouses", name, inAccount)
+	if err != nil {
+		return err
+	}
+	if exists == false {
+		// Already dropped outside of Terraform.
+		return nil
+	}
+	statement := snowsql.Drop("WAREHOUSE", identifier(name))
+	if _, err = db.Exec(statement); err != nil {
+		return err
+	}
+	return nil
+}
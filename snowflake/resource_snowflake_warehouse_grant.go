@@ -0,0 +1,174 @@
+package snowflake
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// warehousePrivileges are the privileges that can be granted on a warehouse,
+// OWNERSHIP is transferred rather than granted.
+var warehousePrivileges = []string{"USAGE", "OPERATE", "MONITOR", "MODIFY"}
+
+// hashIdentifier is the hash function of sets of object names, a name hashes
+// the same however it is written, e.g. analyst and ANALYST.
+func hashIdentifier(v interface{}) int {
+	return hashcode.String(canonicalIdentifier(v.(string)))
+}
+
+func resourceSnowflakeWarehouseGrant() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceSnowflakeWarehouseGrantCreate,
+		Read:   resourceSnowflakeWarehouseGrantRead,
+		Update: resourceSnowflakeWarehouseGrantUpdate,
+		Delete: resourceSnowflakeWarehouseGrantDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStateID("warehouse_name", "privilege"),
+		},
+		Schema: map[string]*schema.Schema{
+			"execution_role": executionRoleSchema(),
+			"warehouse_name": {
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: identifierStateFunc,
+			},
+			"privilege": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "USAGE",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(warehousePrivileges, false),
+			},
+			// The grant owns the privilege on the warehouse, roles granted it
+			// outside of Terraform are revoked on the next apply.
+			"roles": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      hashIdentifier,
+				Required: true,
+			},
+			"with_grant_option": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+// grantWarehouseToRoles grants privilege on warehouse to roles.
+func grantWarehouseToRoles(db Client, warehouse string, privilege string, grantOption bool, roles *schema.Set) error {
+	on := fmt.Sprintf("WAREHOUSE %s", identifier(warehouse))
+	for _, role := range roles.List() {
+		to := fmt.Sprintf("ROLE %s", identifier(role.(string)))
+		if grantOption {
+			to += " WITH GRANT OPTION"
+		}
+		if err := db.Grant([]string{privilege}, on, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revokeWarehouseFromRoles revokes privilege on warehouse from roles.
+func revokeWarehouseFromRoles(db Client, warehouse string, privilege string, roles *schema.Set) error {
+	on := fmt.Sprintf("WAREHOUSE %s", identifier(warehouse))
+	for _, role := range roles.List() {
+		from := fmt.Sprintf("ROLE %s", identifier(role.(string)))
+		// Revoking from a warehouse or role dropped outside of Terraform
+		// fails, the grant is gone with it.
+		if err := db.Revoke([]string{privilege}, on, from); err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func resourceSnowflakeWarehouseGrantCreate(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	warehouse := canonicalIdentifier(d.Get("warehouse_name").(string))
+	privilege := d.Get("privilege").(string)
+	grantOption := d.Get("with_grant_option").(bool)
+	if err = grantWarehouseToRoles(db, warehouse, privilege, grantOption, d.Get("roles").(*schema.Set)); err != nil {
+		return err
+	}
+	d.SetId(encodeID(warehouse, privilege))
+	return nil
+}
+
+func resourceSnowflakeWarehouseGrantRead(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "warehouse_name", "privilege")
+	if err != nil {
+		return err
+	}
+	warehouse, privilege := id[0], resolvedName(id[1])
+	r, err := db.ShowWarehouseGrant(warehouse, privilege)
+	if err != nil {
+		return removeIfNotFound(d, err)
+	}
+	// A grant revoked from every role outside of Terraform is kept with no
+	// roles, so the plan grants it again.
+	d.Set("warehouse_name", warehouse)
+	d.Set("privilege", privilege)
+	d.Set("roles", r.roles)
+	d.Set("with_grant_option", r.grantOption)
+
+	return nil
+}
+
+func resourceSnowflakeWarehouseGrantUpdate(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "warehouse_name", "privilege")
+	if err != nil {
+		return err
+	}
+	warehouse, privilege := id[0], resolvedName(id[1])
+	// The roles are the only property that can change in place, everything
+	// else forces a new grant.
+	if d.HasChange("roles") {
+		o, n := d.GetChange("roles")
+		if err = revokeWarehouseFromRoles(db, warehouse, privilege, o.(*schema.Set).Difference(n.(*schema.Set))); err != nil {
+			return err
+		}
+		grantOption := d.Get("with_grant_option").(bool)
+		if err = grantWarehouseToRoles(db, warehouse, privilege, grantOption, n.(*schema.Set).Difference(o.(*schema.Set))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resourceSnowflakeWarehouseGrantDelete(d *schema.ResourceData, meta interface{}) error {
+	db, err := newClient(meta, d)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	id, err := decodeID(d.Id(), "warehouse_name", "privilege")
+	if err != nil {
+		return err
+	}
+	warehouse, privilege := id[0], resolvedName(id[1])
+	// Revoke from the roles in state, when the grant is replaced because the
+	// roles changed too d holds the new ones.
+	roles, _ := d.GetChange("roles")
+	return revokeWarehouseFromRoles(db, warehouse, privilege, roles.(*schema.Set))
+}
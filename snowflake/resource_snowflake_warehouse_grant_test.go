@@ -0,0 +1,158 @@
+package snowflake
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func testAccShowWarehouseGrant(c Client, id string) error {
+	names, err := decodeID(id, "warehouse_name", "privilege")
+	if err != nil {
+		return err
+	}
+	r, err := c.ShowWarehouseGrant(names[0], resolvedName(names[1]))
+	if err != nil {
+		return err
+	}
+	if len(r.roles) == 0 {
+		return newNotFoundError("Grant", id)
+	}
+	return nil
+}
+
+func TestAccWarehouseGrant(t *testing.T) {
+	warehouse := testAccName()
+	role1 := testAccName()
+	role2 := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_warehouse_grant", testAccShowWarehouseGrant),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWarehouseGrantConfig(warehouse, role1, role2, "USAGE", false, "snowflake_role.first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_warehouse_grant.test", testAccShowWarehouseGrant),
+					resource.TestCheckResourceAttr("snowflake_warehouse_grant.test", "id", warehouse+".USAGE"),
+					resource.TestCheckResourceAttr("snowflake_warehouse_grant.test", "privilege", "USAGE"),
+					resource.TestCheckResourceAttr("snowflake_warehouse_grant.test", "roles.#", "1"),
+					resource.TestCheckResourceAttr("snowflake_warehouse_grant.test", "with_grant_option", "false"),
+				),
+			},
+			// Granted to another role in place.
+			{
+				Config: testAccWarehouseGrantConfig(warehouse, role1, role2, "USAGE", false, "snowflake_role.first", "snowflake_role.second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_warehouse_grant.test", testAccShowWarehouseGrant),
+					resource.TestCheckResourceAttr("snowflake_warehouse_grant.test", "id", warehouse+".USAGE"),
+					resource.TestCheckResourceAttr("snowflake_warehouse_grant.test", "roles.#", "2"),
+				),
+			},
+			// Replaced.
+			{
+				Config: testAccWarehouseGrantConfig(warehouse, role1, role2, "OPERATE", true, "snowflake_role.second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_warehouse_grant.test", testAccShowWarehouseGrant),
+					resource.TestCheckResourceAttr("snowflake_warehouse_grant.test", "id", warehouse+".OPERATE"),
+					resource.TestCheckResourceAttr("snowflake_warehouse_grant.test", "privilege", "OPERATE"),
+					resource.TestCheckResourceAttr("snowflake_warehouse_grant.test", "roles.#", "1"),
+					resource.TestCheckResourceAttr("snowflake_warehouse_grant.test", "with_grant_option", "true"),
+				),
+			},
+			{
+				ResourceName:      "snowflake_warehouse_grant.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Revoked outside of Terraform.
+			{
+				Config:             testAccWarehouseGrantConfig(warehouse, role1, role2, "OPERATE", true, "snowflake_role.second"),
+				Check:              testAccExec(fmt.Sprintf("REVOKE OPERATE ON WAREHOUSE %s FROM ROLE %s", warehouse, role2)),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// testAccWarehouseGrantConfig grants privilege on warehouse to the roles, of
+// snowflake_role.first and snowflake_role.second.
+func testAccWarehouseGrantConfig(warehouse string, role1 string, role2 string, privilege string, grantOption bool, roles ...string) string {
+	var names []string
+	for _, r := range roles {
+		names = append(names, fmt.Sprintf(`"${%s.name}"`, r))
+	}
+	return fmt.Sprintf(`
+resource "snowflake_warehouse" "test" {
+  name                = "%s"
+  initially_suspended = true
+}
+
+resource "snowflake_role" "first" {
+  name = "%s"
+}
+
+resource "snowflake_role" "second" {
+  name = "%s"
+}
+
+resource "snowflake_warehouse_grant" "test" {
+  warehouse_name    = "${snowflake_warehouse.test.name}"
+  privilege         = "%s"
+  roles             = [%s]
+  with_grant_option = %t
+}
+`, warehouse, role1, role2, privilege, strings.Join(names, ", "), grantOption)
+}
+
+func TestWarehouseGrantFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeWarehouseGrant()
+	c.rows["ShowWarehouseGrant W MONITOR"] = showWarehouseGrantResult{warehouse: "W", privilege: "MONITOR", roles: []string{"A", "B"}}
+
+	state, err := testFakeApply(t, r, meta, nil, map[string]interface{}{
+		"warehouse_name": "w",
+		"privilege":      "MONITOR",
+		"roles":          []interface{}{"a"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "GRANT MONITOR ON WAREHOUSE W TO ROLE A")
+	testCheckAttributes(t, state, map[string]string{"id": "W.MONITOR", "roles.#": "1"})
+
+	// B was granted outside of Terraform and is revoked.
+	if state, err = r.Refresh(state, meta); err != nil {
+		t.Fatal(err)
+	}
+	state, err = testFakeApply(t, r, meta, state, map[string]interface{}{
+		"warehouse_name": "W",
+		"privilege":      "MONITOR",
+		"roles":          []interface{}{"A", "C"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c,
+		"REVOKE MONITOR ON WAREHOUSE W FROM ROLE B",
+		"GRANT MONITOR ON WAREHOUSE W TO ROLE C",
+	)
+
+	imported, err := testFakeImport(t, r, meta, "w.monitor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{"id": "W.MONITOR", "privilege": "MONITOR", "roles.#": "2"})
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	statements := c.takeStatements()
+	sort.Strings(statements)
+	if want := []string{"REVOKE MONITOR ON WAREHOUSE W FROM ROLE A", "REVOKE MONITOR ON WAREHOUSE W FROM ROLE C"}; !reflect.DeepEqual(statements, want) {
+		t.Fatalf("executed %q, want %q", statements, want)
+	}
+}
@@ -0,0 +1,214 @@
+package snowflake
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func testAccShowWarehouse(c Client, id string) error {
+	_, err := c.ShowWarehouse(id)
+	return err
+}
+
+func TestAccWarehouse(t *testing.T) {
+	name := testAccName()
+	newName := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_warehouse", testAccShowWarehouse),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWarehouseConfig(name, "created", "x-small", 60),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_warehouse.test", testAccShowWarehouse),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "id", name),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "name", name),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "comment", "created"),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "warehouse_size", "XSMALL"),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "auto_suspend", "60"),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "auto_resume", "true"),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "scaling_policy", "STANDARD"),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "statement_timeout_in_seconds", "3600"),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "state", "SUSPENDED"),
+				),
+			},
+			// Renamed, resized and altered in place.
+			{
+				Config: testAccWarehouseConfig(newName, "updated", "SMALL", 0),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("snowflake_warehouse.test", testAccShowWarehouse),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "id", newName),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "comment", "updated"),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "warehouse_size", "SMALL"),
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "auto_suspend", "0"),
+				),
+			},
+			// Resumed outside of Terraform, the state is refreshed without a
+			// diff.
+			{
+				Config: testAccWarehouseConfig(newName, "updated", "SMALL", 0),
+				Check:  testAccExec("ALTER WAREHOUSE " + newName + " RESUME"),
+			},
+			{
+				Config: testAccWarehouseConfig(newName, "updated", "SMALL", 0),
+				Check:  resource.TestCheckResourceAttr("snowflake_warehouse.test", "state", "STARTED"),
+			},
+			// initially_suspended only applies to CREATE WAREHOUSE.
+			{
+				ResourceName:            "snowflake_warehouse.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"initially_suspended"},
+			},
+			// Dropped outside of Terraform.
+			{
+				Config:             testAccWarehouseConfig(newName, "updated", "SMALL", 0),
+				Check:              testAccExec("DROP WAREHOUSE " + newName),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// Removing resource_monitor leaves the monitor assigned, it may be assigned by
+// the monitor's warehouses.
+func TestAccWarehouseResourceMonitor(t *testing.T) {
+	name := testAccName()
+	monitor := testAccName()
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroyed("snowflake_warehouse", testAccShowWarehouse),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWarehouseMonitorConfig(name, monitor, "${snowflake_resource_monitor.test.name}"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "resource_monitor", monitor),
+					testAccCheckWarehouseMonitor(name, monitor),
+				),
+			},
+			{
+				Config: testAccWarehouseMonitorConfig(name, monitor, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("snowflake_warehouse.test", "resource_monitor", monitor),
+					testAccCheckWarehouseMonitor(name, monitor),
+				),
+			},
+		},
+	})
+}
+
+// testAccWarehouseMonitorConfig returns the configuration of a warehouse and
+// a monitor, resourceMonitor is the attribute of the warehouse, left out if
+// empty.
+func testAccWarehouseMonitorConfig(name string, monitor string, resourceMonitor string) string {
+	if resourceMonitor != "" {
+		resourceMonitor = fmt.Sprintf("resource_monitor    = %q", resourceMonitor)
+	}
+	return fmt.Sprintf(`
+resource "snowflake_resource_monitor" "test" {
+  name         = "%s"
+  credit_quota = 10
+}
+
+resource "snowflake_warehouse" "test" {
+  name                = "%s"
+  initially_suspended = true
+  %s
+}
+`, monitor, name, resourceMonitor)
+}
+
+func testAccWarehouseConfig(name string, comment string, size string, autoSuspend int) string {
+	return fmt.Sprintf(`
+resource "snowflake_warehouse" "test" {
+  name                         = "%s"
+  comment                      = "%s"
+  warehouse_size               = "%s"
+  auto_suspend                 = %d
+  initially_suspended          = true
+  statement_timeout_in_seconds = 3600
+}
+`, name, comment, size, autoSuspend)
+}
+
+func TestWarehouseFakeClient(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeWarehouse()
+	c.rows["ShowWarehouse W"] = showWarehouseRow{
+		Name:            "W",
+		State:           "SUSPENDED",
+		Size:            "X-Small",
+		MinClusterCount: 1,
+		MaxClusterCount: 1,
+		AutoSuspend:     60,
+		AutoResume:      "true",
+		ResourceMonitor: "M",
+		ScalingPolicy:   "STANDARD",
+	}
+	c.rows["ShowParameter STATEMENT_TIMEOUT_IN_SECONDS WAREHOUSE W"] = showParameterRow{Key: "STATEMENT_TIMEOUT_IN_SECONDS", Value: "3600"}
+	c.objects["warehouses W IN ACCOUNT"] = true
+	raw := map[string]interface{}{
+		"name":                         "w",
+		"warehouse_size":               "x-small",
+		"auto_suspend":                 60,
+		"initially_suspended":          true,
+		"resource_monitor":             "m",
+		"statement_timeout_in_seconds": 3600,
+	}
+
+	state, err := testFakeApply(t, r, meta, nil, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "CREATE WAREHOUSE W WAREHOUSE_SIZE = XSMALL AUTO_SUSPEND = 60 AUTO_RESUME = TRUE INITIALLY_SUSPENDED = TRUE MIN_CLUSTER_COUNT = 1 MAX_CLUSTER_COUNT = 1 SCALING_POLICY = STANDARD STATEMENT_TIMEOUT_IN_SECONDS = 3600 RESOURCE_MONITOR = M")
+	testCheckAttributes(t, state, map[string]string{
+		"id":               "W",
+		"warehouse_size":   "XSMALL",
+		"resource_monitor": "M",
+		"state":            "SUSPENDED",
+	})
+
+	raw["warehouse_size"] = "small"
+	raw["max_cluster_count"] = 3
+	raw["comment"] = "c"
+	if state, err = testFakeApply(t, r, meta, state, raw); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c,
+		"ALTER WAREHOUSE W SET COMMENT = 'c'",
+		"ALTER WAREHOUSE W SET WAREHOUSE_SIZE = SMALL",
+		"ALTER WAREHOUSE W SET MIN_CLUSTER_COUNT = 1 MAX_CLUSTER_COUNT = 3",
+	)
+
+	// The monitor stays, the warehouses of a snowflake_resource_monitor may
+	// have assigned it.
+	delete(raw, "resource_monitor")
+	if state, err = testFakeApply(t, r, meta, state, raw); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c)
+	testCheckAttributes(t, state, map[string]string{"resource_monitor": "M"})
+
+	imported, err := testFakeImport(t, r, meta, "w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckAttributes(t, imported, map[string]string{
+		"id":                           "W",
+		"auto_suspend":                 "60",
+		"auto_resume":                  "true",
+		"statement_timeout_in_seconds": "3600",
+	})
+
+	if _, err = testFakeApply(t, r, meta, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c, "DROP WAREHOUSE W")
+
+	delete(c.objects, "warehouses W IN ACCOUNT")
+	if _, err = testFakeApply(t, r, meta, state, map[string]interface{}{"name": "W"}); !isNotFound(err) {
+		t.Fatalf("updating a dropped warehouse returned %v, want a notFoundError", err)
+	}
+	testCheckStatements(t, c)
+}
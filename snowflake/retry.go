@@ -0,0 +1,110 @@
+package snowflake
+
+import (
+	"log"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/snowflakedb/gosnowflake"
+)
+
+// Snowflake error codes that gosnowflake does not export.
+const (
+	errCodeObjectNotFound = 2003
+	errCodeObjectLocked   = 625
+)
+
+// Fragments of transient error messages that do not have a dedicated code.
+var retryableMessages = []string{
+	"is currently locked",
+	"lock has not yet been released",
+	"statement queued",
+}
+
+// reIdempotentStatement matches the statements that may be issued again when
+// it is unknown whether Snowflake received them: reads, session setup and DDL
+// that tolerates the object already existing or being gone.
+var reIdempotentStatement = regexp.MustCompile(`(?i)^((SHOW|DESC|DESCRIBE|SELECT|WITH|USE)\b|ALTER\s+SESSION\b|CREATE\s+OR\s+REPLACE\b|CREATE\s+(\w+\s+)+IF\s+NOT\s+EXISTS\b|DROP\s+(\w+\s+)+IF\s+EXISTS\b)`)
+
+// retryPolicy decides how often and how long to wait before a failed statement
+// is attempted again.
+type retryPolicy struct {
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+/*
+isRetryableError reports whether statement, which failed with err, is likely to
+succeed when issued again: queued statements, lock contention on the object
+being altered, and failures talking to the service (gosnowflake already
+retries 5xx responses internally and gives up with one of the network error
+codes). When posting the statement failed it may still have been executed, so
+only idempotent statements are retried then.
+
+Expired or closed sessions, including a failed renewal of the session token,
+are not retried. The statement would be issued on the same pinned connection,
+whose session and with it the role, warehouse and query tag are gone. Anything
+else, including SQL compilation and access control errors, is fatal as well.
+*/
+func isRetryableError(statement string, err error) bool {
+	sfErr, ok := originalError(err).(*gosnowflake.SnowflakeError)
+	if !ok {
+		return false
+	}
+	switch sfErr.Number {
+	case gosnowflake.ErrFailedToPostQuery:
+		return isIdempotentStatement(statement)
+	case gosnowflake.ErrCodeServiceUnavailable,
+		gosnowflake.ErrFailedToHeartbeat,
+		gosnowflake.ErrFailedToGetChunk,
+		errCodeObjectLocked:
+		return true
+	}
+	message := strings.ToLower(sfErr.Message)
+	for _, m := range retryableMessages {
+		if strings.Contains(message, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdempotentStatement reports whether statement can safely be executed
+// twice, leading whitespace and comments aside.
+func isIdempotentStatement(statement string) bool {
+	return reIdempotentStatement.MatchString(reLeadingComments.ReplaceAllString(statement, ""))
+}
+
+// backoff returns the time to wait before retry number attempt (starting at
+// 0): exponential in the attempt, capped at maxBackoff, with the upper half
+// randomized so concurrent resources do not retry in lock step.
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	wait := p.maxBackoff
+	if attempt < 32 {
+		if d := p.minBackoff << uint(attempt); d > 0 && d < p.maxBackoff {
+			wait = d
+		}
+	}
+	half := wait / 2
+	if half <= 0 {
+		return wait
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// do runs f until it succeeds, fails with an error that is not retryable or
+// the retries are exhausted, and returns the last error.
+func (p retryPolicy) do(statement string, f func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= p.maxRetries || !isRetryableError(statement, err) {
+			return err
+		}
+		wait := p.backoff(attempt)
+		log.Printf("[WARN] Retrying %q in %s (retry %d of %d): %v", redactSQL(statement), wait, attempt+1, p.maxRetries, redactError(err))
+		time.Sleep(wait)
+	}
+}
@@ -0,0 +1,126 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/snowflakedb/gosnowflake"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	sfErr := func(number int, message string) error {
+		return &gosnowflake.SnowflakeError{Number: number, Message: message}
+	}
+	cases := []struct {
+		statement string
+		err       error
+		want      bool
+	}{
+		{"SHOW DATABASES", errors.New("connection reset by peer"), false},
+		{"SHOW DATABASES", sfErr(gosnowflake.ErrCodeServiceUnavailable, ""), true},
+		{"CREATE TABLE T (A INT)", sfErr(gosnowflake.ErrFailedToGetChunk, ""), true},
+		{`ALTER TABLE "T" ADD COLUMN B INT`, sfErr(errCodeObjectLocked, ""), true},
+		{"DROP TABLE T", sfErr(1234, "Object T is currently locked by another statement"), true},
+		{"SHOW DATABASES", sfErr(1234, "Statement queued too long"), true},
+
+		// Posting the statement failed, it may have been executed.
+		{"SHOW DATABASES", sfErr(gosnowflake.ErrFailedToPostQuery, ""), true},
+		{"desc user U", sfErr(gosnowflake.ErrFailedToPostQuery, ""), true},
+		{"SELECT CURRENT_ROLE()", sfErr(gosnowflake.ErrFailedToPostQuery, ""), true},
+		{"  -- comment\n/* and\nanother */ SHOW GRANTS ON WAREHOUSE W", sfErr(gosnowflake.ErrFailedToPostQuery, ""), true},
+		{`USE ROLE "SYSADMIN"`, sfErr(gosnowflake.ErrFailedToPostQuery, ""), true},
+		{"ALTER SESSION SET QUERY_TAG = 'tag'", sfErr(gosnowflake.ErrFailedToPostQuery, ""), true},
+		{"CREATE OR REPLACE VIEW V AS SELECT 1", sfErr(gosnowflake.ErrFailedToPostQuery, ""), true},
+		{"CREATE TABLE IF NOT EXISTS T (A INT)", sfErr(gosnowflake.ErrFailedToPostQuery, ""), true},
+		{"DROP DATABASE IF EXISTS D", sfErr(gosnowflake.ErrFailedToPostQuery, ""), true},
+		{"CREATE TABLE T (A INT)", sfErr(gosnowflake.ErrFailedToPostQuery, ""), false},
+		{"CREATE TRANSIENT TABLE IF NOT EXISTS T (A INT)", sfErr(gosnowflake.ErrFailedToPostQuery, ""), true},
+		{"CREATE VIEW V COMMENT = 'IF NOT EXISTS' AS SELECT 1", sfErr(gosnowflake.ErrFailedToPostQuery, ""), false},
+		{"DROP FILE FORMAT IF EXISTS D.S.F", sfErr(gosnowflake.ErrFailedToPostQuery, ""), true},
+		{"DROP DATABASE D", sfErr(gosnowflake.ErrFailedToPostQuery, ""), false},
+		{"ALTER WAREHOUSE W SET WAREHOUSE_SIZE = 'SMALL'", sfErr(gosnowflake.ErrFailedToPostQuery, ""), false},
+		{"GRANT USAGE ON DATABASE D TO ROLE R", sfErr(gosnowflake.ErrFailedToPostQuery, ""), false},
+		{"-- SELECT\nINSERT INTO T VALUES (1)", sfErr(gosnowflake.ErrFailedToPostQuery, ""), false},
+
+		// The session of the pinned connection is gone.
+		{"SHOW DATABASES", sfErr(390112, "Your session has expired. Please login again."), false},
+		{"SHOW DATABASES", sfErr(390114, "Authentication token has expired."), false},
+		{"SHOW DATABASES", sfErr(gosnowflake.ErrSessionGone, ""), false},
+		{"SHOW DATABASES", sfErr(gosnowflake.ErrFailedToRenewSession, ""), false},
+
+		{"SELEC 1", sfErr(1003, "SQL compilation error: syntax error"), false},
+		{"SHOW DATABASES", sfErr(3001, "Insufficient privileges to operate on account"), false},
+		{"DROP TABLE T", sfErr(errCodeObjectNotFound, "Object does not exist"), false},
+	}
+	for _, c := range cases {
+		if got := isRetryableError(c.statement, c.err); got != c.want {
+			t.Errorf("isRetryableError(%q, %v) = %t, want %t", c.statement, c.err, got, c.want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := retryPolicy{maxRetries: 100, minBackoff: time.Second, maxBackoff: 30 * time.Second}
+	for attempt := 0; attempt < 100; attempt++ {
+		// The exponential wait, of which the upper half is randomized.
+		wait := p.maxBackoff
+		if attempt < 5 {
+			wait = p.minBackoff << uint(attempt)
+		}
+		for i := 0; i < 10; i++ {
+			got := p.backoff(attempt)
+			if got < wait/2 || got > wait {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", attempt, got, wait/2, wait)
+			}
+		}
+	}
+
+	// A minimum above the maximum is capped.
+	p = retryPolicy{minBackoff: time.Minute, maxBackoff: time.Second}
+	if got := p.backoff(0); got > time.Second {
+		t.Fatalf("backoff(0) = %s, want at most %s", got, time.Second)
+	}
+	// Without backoff there is no wait.
+	p = retryPolicy{}
+	if got := p.backoff(3); got != 0 {
+		t.Fatalf("backoff(3) = %s, want 0", got)
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	retryable := &gosnowflake.SnowflakeError{Number: errCodeObjectLocked}
+	fatal := &gosnowflake.SnowflakeError{Number: 1003}
+	cases := []struct {
+		name       string
+		maxRetries int
+		errs       []error
+		calls      int
+		err        error
+	}{
+		{"success", 3, nil, 1, nil},
+		{"success after retries", 3, []error{retryable, retryable}, 3, nil},
+		{"retries exhausted", 3, []error{retryable, retryable, retryable, retryable, retryable}, 4, retryable},
+		{"no retries", 0, []error{retryable}, 1, retryable},
+		{"not retryable", 3, []error{retryable, fatal, retryable}, 2, fatal},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := retryPolicy{maxRetries: c.maxRetries, minBackoff: time.Millisecond, maxBackoff: time.Millisecond}
+			calls := 0
+			err := p.do("ALTER TABLE T ADD COLUMN B INT", func() error {
+				calls++
+				if calls <= len(c.errs) {
+					return c.errs[calls-1]
+				}
+				return nil
+			})
+			if err != c.err {
+				t.Fatalf("got error %v, want %v", err, c.err)
+			}
+			if calls != c.calls {
+				t.Fatalf("got %d calls, want %d", calls, c.calls)
+			}
+		})
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"time"
 )
 
+// The rows returned by SHOW, DESC and information_schema queries, see
+// scanRow for how columns are mapped onto the fields.
+
 type descUserResult struct {
 	name                          string
 	comment                       string
@@ -32,6 +35,13 @@ type descUserResult struct {
 	rsa_public_key_2              string
 }
 
+type descUserRow struct {
+	Property    string `db:"property"`
+	Value       string `db:"value"`
+	Default     string `db:"default"`
+	Description string `db:"description"`
+}
+
 type descStageResult struct {
 	url                string
 	aws_role           string
@@ -39,190 +49,258 @@ type descStageResult struct {
 	snowflake_iam_user string
 }
 
+type descStageRow struct {
+	ParentProperty  string `db:"parent_property"`
+	Property        string `db:"property"`
+	PropertyType    string `db:"property_type"`
+	PropertyValue   string `db:"property_value"`
+	PropertyDefault string `db:"property_default"`
+}
+
 type showDatabaseRow struct {
-	createdOn     time.Time
-	name          string
-	isDefault     string
-	isCurrent     string
-	origin        string
-	owner         string
-	comment       string
-	options       string
-	retentionTime string
+	CreatedOn     time.Time `db:"created_on"`
+	Name          string    `db:"name"`
+	IsDefault     string    `db:"is_default"`
+	IsCurrent     string    `db:"is_current"`
+	Origin        string    `db:"origin"`
+	Owner         string    `db:"owner"`
+	Comment       string    `db:"comment"`
+	Options       string    `db:"options"`
+	RetentionTime string    `db:"retention_time"`
 }
 
 type showSchemaRow struct {
-	createdOn     time.Time
-	name          string
-	isDefault     string
-	isCurrent     string
-	databaseName  string
-	owner         string
-	comment       string
-	options       string
-	retentionTime string
+	CreatedOn     time.Time `db:"created_on"`
+	Name          string    `db:"name"`
+	IsDefault     string    `db:"is_default"`
+	IsCurrent     string    `db:"is_current"`
+	DatabaseName  string    `db:"database_name"`
+	Owner         string    `db:"owner"`
+	Comment       string    `db:"comment"`
+	Options       string    `db:"options"`
+	RetentionTime string    `db:"retention_time"`
 }
 
 type showTableRow struct {
-	createdOn     time.Time
-	name          string
-	databaseName  string
-	schemaName    string
-	kind          string
-	comment       string
-	clusterBy     string
-	rows          int
-	bytes         int
-	owner         string
-	retentionTime string
+	CreatedOn     time.Time `db:"created_on"`
+	Name          string    `db:"name"`
+	DatabaseName  string    `db:"database_name"`
+	SchemaName    string    `db:"schema_name"`
+	Kind          string    `db:"kind"`
+	Comment       string    `db:"comment"`
+	ClusterBy     string    `db:"cluster_by"`
+	Rows          int       `db:"rows"`
+	Bytes         int       `db:"bytes"`
+	Owner         string    `db:"owner"`
+	RetentionTime string    `db:"retention_time"`
 }
 
 type descTableRow struct {
-	colName      string
-	colType      string
-	kind         string
-	isNullable   string
-	defaultValue sql.NullString
-	isPrimaryKey string
-	isUniqueKey  string
-	check        sql.NullString
-	expression   sql.NullString
-	comment      sql.NullString
+	ColName      string         `db:"name"`
+	ColType      string         `db:"type"`
+	Kind         string         `db:"kind"`
+	IsNullable   string         `db:"null?"`
+	DefaultValue sql.NullString `db:"default"`
+	IsPrimaryKey string         `db:"primary key"`
+	IsUniqueKey  string         `db:"unique key"`
+	Check        string         `db:"check"`
+	Expression   string         `db:"expression"`
+	Comment      string         `db:"comment"`
 }
 
 type showColumnsRow struct {
-	tableName     string
-	schemaName    string
-	columnName    string
-	dataType      map[string]interface{}
-	isNullable    string
-	defaultValue  string
-	kind          string
-	expression    string
-	comment       string
-	databaseName  string
-	autoincrement string
+	TableName     string `db:"table_name"`
+	SchemaName    string `db:"schema_name"`
+	ColumnName    string `db:"column_name"`
+	DataType      string `db:"data_type"`
+	IsNullable    string `db:"null?"`
+	DefaultValue  string `db:"default"`
+	Kind          string `db:"kind"`
+	Expression    string `db:"expression"`
+	Comment       string `db:"comment"`
+	DatabaseName  string `db:"database_name"`
+	Autoincrement string `db:"autoincrement"`
 }
 
 type showPipeRow struct {
-	createdOn           time.Time
-	name                string
-	databaseName        string
-	schemaName          string
-	definition          string
-	owner               string
-	notificationChannel string
-	comment             string
+	CreatedOn           time.Time `db:"created_on"`
+	Name                string    `db:"name"`
+	DatabaseName        string    `db:"database_name"`
+	SchemaName          string    `db:"schema_name"`
+	Definition          string    `db:"definition"`
+	Owner               string    `db:"owner"`
+	NotificationChannel string    `db:"notification_channel"`
+	Comment             string    `db:"comment"`
+}
+
+type showFileFormatRow struct {
+	CreatedOn     time.Time `db:"created_on"`
+	Name          string    `db:"name"`
+	DatabaseName  string    `db:"database_name"`
+	SchemaName    string    `db:"schema_name"`
+	Type          string    `db:"type"`
+	Owner         string    `db:"owner"`
+	Comment       string    `db:"comment"`
+	FormatOptions string    `db:"format_options"`
+}
+
+type descFileFormatRow struct {
+	Property        string `db:"property"`
+	PropertyType    string `db:"property_type"`
+	PropertyValue   string `db:"property_value"`
+	PropertyDefault string `db:"property_default"`
+}
+
+// descFileFormatResult holds the options of a file format, options of other
+// types than its own are left empty.
+type descFileFormatResult struct {
+	formatType                string
+	recordDelimiter           string
+	fieldDelimiter            string
+	skipHeader                int
+	fieldOptionallyEnclosedBy string
+	stripOuterArray           bool
+	compression               string
+	trimSpace                 bool
+}
+
+type showSequenceRow struct {
+	Name         string    `db:"name"`
+	DatabaseName string    `db:"database_name"`
+	SchemaName   string    `db:"schema_name"`
+	NextValue    int       `db:"next_value"`
+	Interval     int       `db:"interval"`
+	CreatedOn    time.Time `db:"created_on"`
+	Owner        string    `db:"owner"`
+	Comment      string    `db:"comment"`
 }
 
 type infoSchemaDatabase struct {
-	databaseName  string
-	databaseOwner string
-	isTransient   string
-	comment       sql.NullString
-	created       time.Time
-	lastAltered   time.Time
-	retentionTime int
+	DatabaseName  string    `db:"database_name"`
+	DatabaseOwner string    `db:"database_owner"`
+	IsTransient   string    `db:"is_transient"`
+	Comment       string    `db:"comment"`
+	Created       time.Time `db:"created"`
+	LastAltered   time.Time `db:"last_altered"`
+	RetentionTime int       `db:"retention_time"`
 }
 
 type infoSchemaSchemata struct {
-	catalogName                string
-	schemaName                 string
-	schemaOwner                string
-	isTransient                string
-	retentionTime              int
-	defaultCharacterSetCatalog sql.NullString
-	defaultCharacterSetSchema  sql.NullString
-	defaultCharacterSetName    sql.NullString
-	sqlPath                    sql.NullString
-	created                    sql.NullString
-	lastAltered                sql.NullString
-	comment                    sql.NullString
+	CatalogName                string `db:"catalog_name"`
+	SchemaName                 string `db:"schema_name"`
+	SchemaOwner                string `db:"schema_owner"`
+	IsTransient                string `db:"is_transient"`
+	RetentionTime              int    `db:"retention_time"`
+	DefaultCharacterSetCatalog string `db:"default_character_set_catalog"`
+	DefaultCharacterSetSchema  string `db:"default_character_set_schema"`
+	DefaultCharacterSetName    string `db:"default_character_set_name"`
+	SQLPath                    string `db:"sql_path"`
+	Created                    string `db:"created"`
+	LastAltered                string `db:"last_altered"`
+	Comment                    string `db:"comment"`
 }
 
 type infoSchemaTable struct {
-	tableCatalog              string
-	tableSchema               string
-	tableName                 string
-	tableOwner                string
-	tableType                 string
-	isTransient               string
-	clusteringKey             sql.NullString
-	rowCount                  int
-	bytes                     int
-	retentionTime             int
-	selfReferencingColumnName sql.NullString
-	referenceGeneration       sql.NullString
-	userDefinedTypeColumn     sql.NullString
-	userDefinedTypeSchema     sql.NullString
-	userDefinedTypeName       sql.NullString
-	isInsertableInto          string
-	isTyped                   string
-	commitAction              sql.NullString
-	created                   time.Time
-	lastAltered               time.Time
-	autoClusteringOn          string
-	comment                   sql.NullString
+	TableCatalog              string    `db:"table_catalog"`
+	TableSchema               string    `db:"table_schema"`
+	TableName                 string    `db:"table_name"`
+	TableOwner                string    `db:"table_owner"`
+	TableType                 string    `db:"table_type"`
+	IsTransient               string    `db:"is_transient"`
+	ClusteringKey             string    `db:"clustering_key"`
+	RowCount                  int       `db:"row_count"`
+	Bytes                     int       `db:"bytes"`
+	RetentionTime             int       `db:"retention_time"`
+	SelfReferencingColumnName string    `db:"self_referencing_column_name"`
+	ReferenceGeneration       string    `db:"reference_generation"`
+	UserDefinedTypeCatalog    string    `db:"user_defined_type_catalog"`
+	UserDefinedTypeSchema     string    `db:"user_defined_type_schema"`
+	UserDefinedTypeName       string    `db:"user_defined_type_name"`
+	IsInsertableInto          string    `db:"is_insertable_into"`
+	IsTyped                   string    `db:"is_typed"`
+	CommitAction              string    `db:"commit_action"`
+	Created                   time.Time `db:"created"`
+	LastAltered               time.Time `db:"last_altered"`
+	AutoClusteringOn          string    `db:"auto_clustering_on"`
+	Comment                   string    `db:"comment"`
 }
 
 type infoSchemaView struct {
-	tableCatalog   string
-	tableSchema    string
-	tableName      string
-	tableOwner     string
-	viewDefinition string
-	checkOption    string
-	isUpdatable    string
-	insertableInto string
-	isSecure       string
-	created        time.Time
-	lastAltered    time.Time
-	comment        sql.NullString
+	TableCatalog   string    `db:"table_catalog"`
+	TableSchema    string    `db:"table_schema"`
+	TableName      string    `db:"table_name"`
+	TableOwner     string    `db:"table_owner"`
+	ViewDefinition string    `db:"view_definition"`
+	CheckOption    string    `db:"check_option"`
+	IsUpdatable    string    `db:"is_updatable"`
+	InsertableInto string    `db:"insertable_into"`
+	IsSecure       string    `db:"is_secure"`
+	Created        time.Time `db:"created"`
+	LastAltered    time.Time `db:"last_altered"`
+	Comment        string    `db:"comment"`
 }
 
 type infoSchemaColumn struct {
-	tableCatalog           string
-	tableSchema            string
-	tableName              string
-	columnName             string
-	ordinalPosition        int
-	columnDefault          sql.NullString
-	isNullable             string
-	dataType               string
-	characterMaximumLength sql.NullInt64
-	characterOctetLength   sql.NullInt64
-	numericPrecision       sql.NullInt64
-	numericPrecisionRadix  sql.NullInt64
-	numericScale           sql.NullInt64
-	datetimePrecision      sql.NullInt64
-	intervalType           sql.NullString
-	intervalPrecision      sql.NullString
-	characterSetCatalog    sql.NullString
-	characterSetSchema     sql.NullString
-	characterSetName       sql.NullString
-	collationCatalog       sql.NullString
-	collationSchema        sql.NullString
-	collationName          sql.NullString
-	domainCatalog          sql.NullString
-	domainSchema           sql.NullString
-	domainName             sql.NullString
-	udtCatalog             sql.NullString
-	udtSchema              sql.NullString
-	udtName                sql.NullString
-	scopeCatalog           sql.NullString
-	scopeSchema            sql.NullString
-	scopeName              sql.NullString
-	maximumCardinality     sql.NullString
-	dtdIdentifier          sql.NullString
-	isSelfReferencing      string
-	isIdentity             string
-	identityGeneration     sql.NullString
-	identityStart          sql.NullString
-	identityIncrement      sql.NullString
-	identityMaximum        sql.NullString
-	identityMinimum        sql.NullString
-	identityCycle          sql.NullString
-	comment                sql.NullString
+	TableCatalog           string         `db:"table_catalog"`
+	TableSchema            string         `db:"table_schema"`
+	TableName              string         `db:"table_name"`
+	ColumnName             string         `db:"column_name"`
+	OrdinalPosition        int            `db:"ordinal_position"`
+	ColumnDefault          sql.NullString `db:"column_default"`
+	IsNullable             string         `db:"is_nullable"`
+	DataType               string         `db:"data_type"`
+	CharacterMaximumLength sql.NullInt64  `db:"character_maximum_length"`
+	CharacterOctetLength   sql.NullInt64  `db:"character_octet_length"`
+	NumericPrecision       sql.NullInt64  `db:"numeric_precision"`
+	NumericPrecisionRadix  sql.NullInt64  `db:"numeric_precision_radix"`
+	NumericScale           sql.NullInt64  `db:"numeric_scale"`
+	DatetimePrecision      sql.NullInt64  `db:"datetime_precision"`
+	IntervalType           string         `db:"interval_type"`
+	IntervalPrecision      string         `db:"interval_precision"`
+	CharacterSetCatalog    string         `db:"character_set_catalog"`
+	CharacterSetSchema     string         `db:"character_set_schema"`
+	CharacterSetName       string         `db:"character_set_name"`
+	CollationCatalog       string         `db:"collation_catalog"`
+	CollationSchema        string         `db:"collation_schema"`
+	CollationName          string         `db:"collation_name"`
+	DomainCatalog          string         `db:"domain_catalog"`
+	DomainSchema           string         `db:"domain_schema"`
+	DomainName             string         `db:"domain_name"`
+	UdtCatalog             string         `db:"udt_catalog"`
+	UdtSchema              string         `db:"udt_schema"`
+	UdtName                string         `db:"udt_name"`
+	ScopeCatalog           string         `db:"scope_catalog"`
+	ScopeSchema            string         `db:"scope_schema"`
+	ScopeName              string         `db:"scope_name"`
+	MaximumCardinality     string         `db:"maximum_cardinality"`
+	DtdIdentifier          string         `db:"dtd_identifier"`
+	IsSelfReferencing      string         `db:"is_self_referencing"`
+	IsIdentity             string         `db:"is_identity"`
+	IdentityGeneration     string         `db:"identity_generation"`
+	IdentityStart          string         `db:"identity_start"`
+	IdentityIncrement      string         `db:"identity_increment"`
+	IdentityMaximum        string         `db:"identity_maximum"`
+	IdentityMinimum        string         `db:"identity_minimum"`
+	IdentityCycle          string         `db:"identity_cycle"`
+	Comment                string         `db:"comment"`
+}
+
+type objectPrivilegeRow struct {
+	Grantee       string `db:"grantee"`
+	PrivilegeType string `db:"privilege_type"`
+	IsGrantable   string `db:"is_grantable"`
+	ObjectName    string `db:"object_name"`
+}
+
+type showGrantRow struct {
+	CreatedOn   time.Time `db:"created_on"`
+	Privilege   string    `db:"privilege"`
+	GrantedOn   string    `db:"granted_on"`
+	Name        string    `db:"name"`
+	GrantedTo   string    `db:"granted_to"`
+	GranteeName string    `db:"grantee_name"`
+	GrantOption string    `db:"grant_option"`
+	GrantedBy   string    `db:"granted_by"`
 }
 
 type showTableGrantResult struct {
@@ -241,15 +319,65 @@ type showViewGrantResult struct {
 	privileges  []string
 }
 
+type showWarehouseGrantResult struct {
+	warehouse   string
+	privilege   string
+	roles       []string
+	grantOption bool
+}
+
 type showRoleRow struct {
-	createdOn       time.Time
-	name            string
-	isDefault       string
-	isCurrent       string
-	isInherited     string
-	assignedToUsers string
-	grantedToRoles  string
-	grantedRoles    string
-	owner           string
-	comment         sql.NullString
+	CreatedOn       time.Time `db:"created_on"`
+	Name            string    `db:"name"`
+	IsDefault       string    `db:"is_default"`
+	IsCurrent       string    `db:"is_current"`
+	IsInherited     string    `db:"is_inherited"`
+	AssignedToUsers string    `db:"assigned_to_users"`
+	GrantedToRoles  string    `db:"granted_to_roles"`
+	GrantedRoles    string    `db:"granted_roles"`
+	Owner           string    `db:"owner"`
+	Comment         string    `db:"comment"`
+}
+
+type showWarehouseRow struct {
+	CreatedOn       time.Time `db:"created_on"`
+	Name            string    `db:"name"`
+	State           string    `db:"state"`
+	Type            string    `db:"type"`
+	Size            string    `db:"size"`
+	MinClusterCount int       `db:"min_cluster_count"`
+	MaxClusterCount int       `db:"max_cluster_count"`
+	AutoSuspend     int       `db:"auto_suspend"`
+	AutoResume      string    `db:"auto_resume"`
+	Owner           string    `db:"owner"`
+	Comment         string    `db:"comment"`
+	ResourceMonitor string    `db:"resource_monitor"`
+	ScalingPolicy   string    `db:"scaling_policy"`
+}
+
+type showParameterRow struct {
+	Key         string `db:"key"`
+	Value       string `db:"value"`
+	Default     string `db:"default"`
+	Level       string `db:"level"`
+	Description string `db:"description"`
+	Type        string `db:"type"`
+}
+
+type showResourceMonitorRow struct {
+	Name                 string    `db:"name"`
+	CreditQuota          string    `db:"credit_quota"`
+	UsedCredits          string    `db:"used_credits"`
+	RemainingCredits     string    `db:"remaining_credits"`
+	Level                string    `db:"level"`
+	Frequency            string    `db:"frequency"`
+	StartTime            string    `db:"start_time"`
+	EndTime              string    `db:"end_time"`
+	NotifyAt             string    `db:"notify_at"`
+	SuspendAt            string    `db:"suspend_at"`
+	SuspendImmediatelyAt string    `db:"suspend_immediately_at"`
+	CreatedOn            time.Time `db:"created_on"`
+	Owner                string    `db:"owner"`
+	Comment              string    `db:"comment"`
+	NotifyUsers          string    `db:"notify_users"`
 }
@@ -0,0 +1,151 @@
+package snowflake
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+/*
+scanRow scans the current row of rows into the struct dest points to. Columns
+are mapped onto the fields by their db tag, compared case insensitively as
+SHOW commands return lower case and information_schema upper case column
+names.
+
+Snowflake adds columns to the output of SHOW and DESC commands from time to
+time, so columns without a field are skipped rather than failing, and fields
+without a column keep their zero value. NULL leaves a field at its zero value
+too, fields that need to tell NULL apart from the zero value use a sql.Scanner
+such as sql.NullString.
+*/
+func scanRow(rows *sql.Rows, dest interface{}) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	values, err := scanValues(rows, len(columns))
+	if err != nil {
+		return err
+	}
+	return assignRow(columns, values, dest)
+}
+
+// scanValues scans the current row of rows into the values returned by the
+// driver.
+func scanValues(rows *sql.Rows, n int) ([]interface{}, error) {
+	values := make([]interface{}, n)
+	targets := make([]interface{}, n)
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	if err := rows.Scan(targets...); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// assignRow assigns the values of a row with the given columns to the struct
+// dest points to, see scanRow.
+func assignRow(columns []string, values []interface{}, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("scanRow: expected a pointer to a struct, got %T", dest)
+	}
+	if len(values) != len(columns) {
+		return fmt.Errorf("scanRow: %d values for %d columns", len(values), len(columns))
+	}
+	v = v.Elem()
+	fields := map[string]int{}
+	for i := 0; i < v.NumField(); i++ {
+		if tag := v.Type().Field(i).Tag.Get("db"); tag != "" {
+			fields[strings.ToLower(tag)] = i
+		}
+	}
+	for i, column := range columns {
+		index, ok := fields[strings.ToLower(column)]
+		if !ok {
+			continue
+		}
+		if err := assignValue(v.Field(index), values[i]); err != nil {
+			return fmt.Errorf("scanRow: column %s: %v", column, err)
+		}
+	}
+	return nil
+}
+
+/*
+assignValue assigns a value returned by the driver to field. gosnowflake
+returns most columns as strings, including numbers, so strings are parsed into
+numeric and boolean fields. Fields that are a sql.Scanner do their own
+conversion.
+*/
+func assignValue(field reflect.Value, value interface{}) error {
+	if field.Addr().Type().Implements(scannerType) {
+		return field.Addr().Interface().(sql.Scanner).Scan(value)
+	}
+	switch v := value.(type) {
+	case nil:
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	case []byte:
+		value = string(v)
+	case time.Time:
+		if field.Kind() == reflect.String {
+			value = v.Format(time.RFC3339Nano)
+		}
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Type().AssignableTo(field.Type()) {
+		field.Set(rv)
+		return nil
+	}
+	s := fmt.Sprint(value)
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("cannot assign %T to %s", value, field.Type())
+	}
+	return nil
+}
+
+// scanRows scans all rows into the slice of structs dest points to, see
+// scanRow.
+func scanRows(rows *sql.Rows, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("scanRows: expected a pointer to a slice, got %T", dest)
+	}
+	slice := v.Elem()
+	for rows.Next() {
+		row := reflect.New(slice.Type().Elem())
+		if err := scanRow(rows, row.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, row.Elem()))
+	}
+	return rows.Err()
+}
@@ -0,0 +1,138 @@
+package snowflake
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testScanRow struct {
+	Name      string         `db:"name"`
+	Rows      int64          `db:"rows"`
+	Secure    bool           `db:"is_secure"`
+	Retention int            `db:"RETENTION_TIME"`
+	Comment   sql.NullString `db:"comment"`
+	CreatedOn string         `db:"created_on"`
+	Untagged  string
+}
+
+func TestAssignRow(t *testing.T) {
+	created := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		columns []string
+		values  []interface{}
+		want    testScanRow
+		err     bool
+	}{
+		{
+			name:    "strings",
+			columns: []string{"name", "rows", "is_secure", "retention_time", "comment"},
+			values:  []interface{}{"T", "42", "true", "1", "c"},
+			want:    testScanRow{Name: "T", Rows: 42, Secure: true, Retention: 1, Comment: sql.NullString{String: "c", Valid: true}},
+		},
+		{
+			name:    "driver types",
+			columns: []string{"NAME", "ROWS", "IS_SECURE", "CREATED_ON"},
+			values:  []interface{}{[]byte("T"), int64(42), false, created},
+			want:    testScanRow{Name: "T", Rows: 42, CreatedOn: "2019-05-01T10:00:00Z"},
+		},
+		{
+			name:    "unknown columns",
+			columns: []string{"kind", "name", "budget", "Untagged"},
+			values:  []interface{}{"TABLE", "T", "B", "U"},
+			want:    testScanRow{Name: "T"},
+		},
+		{
+			name:    "NULL",
+			columns: []string{"name", "rows", "is_secure", "comment"},
+			values:  []interface{}{nil, nil, nil, nil},
+			want:    testScanRow{},
+		},
+		{
+			name:    "not a number",
+			columns: []string{"rows"},
+			values:  []interface{}{"many"},
+			err:     true,
+		},
+		{
+			name:    "not a bool",
+			columns: []string{"is_secure"},
+			values:  []interface{}{"Y"},
+			err:     true,
+		},
+		{
+			name:    "too few values",
+			columns: []string{"name", "rows"},
+			values:  []interface{}{"T"},
+			err:     true,
+		},
+		{
+			name:    "too many values",
+			columns: []string{"name"},
+			values:  []interface{}{"T", "42"},
+			err:     true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// Fields without a column keep their value.
+			got := testScanRow{Untagged: "kept"}
+			c.want.Untagged = "kept"
+			err := assignRow(c.columns, c.values, &got)
+			if c.err {
+				if err == nil {
+					t.Fatalf("assignRow = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("assignRow = %+v, want %+v", got, c.want)
+			}
+		})
+	}
+
+	if err := assignRow([]string{"name"}, []interface{}{"T"}, testScanRow{}); err == nil {
+		t.Fatal("assignRow into a struct rather than a pointer succeeded")
+	}
+}
+
+// TestScanRows scans the output of a SHOW command of the stand-in into rows
+// that lack fields for most of its columns.
+func TestScanRows(t *testing.T) {
+	r := newRecordingDriver()
+	db := testSessionMeta(r).db
+	defer db.Close()
+	if _, err := db.Exec("CREATE DATABASE D COMMENT = 'c'"); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := db.Query("SHOW DATABASES")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var databases []struct {
+		CreatedOn time.Time `db:"created_on"`
+		Name      string    `db:"name"`
+		Comment   string    `db:"comment"`
+	}
+	if err := scanRows(rows, &databases); err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, d := range databases {
+		if d.Name == "D" {
+			found = d.Comment == "c" && !d.CreatedOn.IsZero()
+		}
+	}
+	if !found {
+		t.Fatalf("SHOW DATABASES = %+v, want D with comment c", databases)
+	}
+	if err := scanRows(rows, databases); err == nil {
+		t.Fatal("scanRows into a slice rather than a pointer succeeded")
+	}
+}
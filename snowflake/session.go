@@ -0,0 +1,275 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// providerMeta is the configured provider handed to every resource as meta.
+type providerMeta struct {
+	db             *sql.DB
+	role           string
+	warehouse      string
+	retry          retryPolicy
+	tagQueries     bool
+	queryTagPrefix string
+	sqlLog         *sqlLog
+	dryRun         bool
+	dryRunScript   *sqlLog
+	readOnly       bool
+	showCache      *showCache
+
+	// openClient opens the Client of an operation instead of a session, see
+	// newClient.
+	openClient func(m *providerMeta, d *schema.ResourceData) (Client, error)
+
+	// resourceType and operation are set for the copy handed to a single
+	// resource operation, see withOperations.
+	resourceType string
+	operation    string
+}
+
+/*
+session is a connection pinned for the duration of a single resource
+operation. USE ROLE and USE WAREHOUSE only affect the connection they are
+issued on, so every statement of an operation has to go through the same
+connection for the execution role to apply.
+
+Connections are returned to the pool with whatever role and query tag the last
+operation used. Rather than trying to undo that on close, every session sets
+the role, warehouse and query tag it needs when it is opened, falling back to
+the defaults of the login (see loadSessionDefaults), so no session state leaks
+from one resource to the next however the pool hands out connections.
+
+All statements of the provider go through a session, which retries transient
+errors, records statements in the SQL log and redacts secrets from errors.
+Statements passed to Exec are run by the session's executor, which in dry-run
+mode writes them to the dry-run script instead of sending them to Snowflake.
+*/
+type session struct {
+	conn     *sql.Conn
+	executor executor
+	meta     *providerMeta
+	d        *schema.ResourceData
+	role     string
+}
+
+// executor runs the statements a resource passes to Session.Exec. *sql.Conn is
+// the executor used outside of dry-run mode.
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// dryRunExecutor appends statements to the dry-run script rather than
+// executing them.
+type dryRunExecutor struct {
+	s *session
+}
+
+func (e dryRunExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if len(args) > 0 {
+		return nil, fmt.Errorf("Statements with arguments cannot be rendered in dry-run mode: %s", redactSQL(query))
+	}
+	e.s.meta.dryRunScript.recordStatement(e.s.address(), query)
+	return driver.RowsAffected(0), nil
+}
+
+// executionRoleSchema returns the schema of the execution_role attribute that
+// every resource and data source accepts to override the provider role.
+func executionRoleSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Role to run this resource's statements as, overrides the provider role.",
+	}
+}
+
+// loadSessionDefaults fills in the role and warehouse a fresh connection of
+// the login starts out with when the provider does not configure them.
+func (m *providerMeta) loadSessionDefaults() error {
+	if m.role != "" && m.warehouse != "" {
+		return nil
+	}
+	var role, warehouse sql.NullString
+	statement := "SELECT CURRENT_ROLE(), CURRENT_WAREHOUSE()"
+	err := m.retry.do(statement, func() error {
+		return m.db.QueryRow(statement).Scan(&role, &warehouse)
+	})
+	if err != nil {
+		return err
+	}
+	if m.role == "" {
+		m.role = role.String
+	}
+	if m.warehouse == "" {
+		m.warehouse = warehouse.String
+	}
+	return nil
+}
+
+// newSession pins a connection from the pool and switches it to the execution
+// role of the resource and the provider warehouse.
+func newSession(m *providerMeta, d *schema.ResourceData) (*session, error) {
+	var conn *sql.Conn
+	err := m.retry.do("connect", func() error {
+		var err error
+		conn, err = m.db.Conn(context.Background())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s := &session{conn: conn, executor: conn, meta: m, d: d}
+	if m.dryRun {
+		s.executor = dryRunExecutor{s}
+	}
+	role := m.role
+	if executionRole, ok := d.GetOk("execution_role"); ok {
+		role = executionRole.(string)
+	}
+	s.role = role
+	if role != "" {
+		if _, err := s.exec(s.conn, fmt.Sprintf("USE ROLE %s", identifier(role))); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	if m.warehouse != "" {
+		if _, err := s.exec(s.conn, fmt.Sprintf("USE WAREHOUSE %s", identifier(m.warehouse))); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	if m.tagQueries {
+		statement, err := m.queryTagStatement(d)
+		if err == nil {
+			_, err = s.exec(s.conn, statement)
+		}
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Exec executes a statement, retrying transient errors.
+func (s *session) Exec(query string, args ...interface{}) (sql.Result, error) {
+	if err := s.checkReadOnlyStatement(query); err != nil {
+		return nil, err
+	}
+	if !s.meta.dryRun {
+		// Also on failure, a statement may have changed objects before
+		// failing.
+		defer s.meta.showCache.invalidate()
+	}
+	return s.exec(s.executor, query, args...)
+}
+
+// exec executes a statement with e. Statements that set up the session are
+// executed directly on the connection, also in dry-run mode, as the reads of
+// the operation depend on them. They skip the read-only check for the same
+// reason, USE and ALTER SESSION only change the session, not Snowflake.
+func (s *session) exec(e executor, query string, args ...interface{}) (sql.Result, error) {
+	if _, ok := e.(dryRunExecutor); ok {
+		// Nothing is executed, so there is nothing to retry or log.
+		return e.ExecContext(context.Background(), query, args...)
+	}
+	var result sql.Result
+	start := time.Now()
+	err := s.meta.retry.do(query, func() error {
+		var err error
+		result, err = e.ExecContext(context.Background(), query, args...)
+		return err
+	})
+	rows := "-"
+	if err == nil {
+		if n, err := result.RowsAffected(); err == nil {
+			rows = strconv.FormatInt(n, 10)
+		}
+	}
+	s.meta.sqlLog.record(s.address(), query, time.Since(start), rows, err)
+	return result, redactError(err)
+}
+
+// ExecAll executes statements in order, stopping at the first error.
+func (s *session) ExecAll(statements []string) error {
+	for _, statement := range statements {
+		if _, err := s.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Query runs a query, retrying transient errors.
+func (s *session) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	if err := s.checkReadOnlyStatement(query); err != nil {
+		return nil, err
+	}
+	var rows *sql.Rows
+	start := time.Now()
+	err := s.meta.retry.do(query, func() error {
+		var err error
+		rows, err = s.conn.QueryContext(context.Background(), query, args...)
+		return err
+	})
+	s.meta.sqlLog.record(s.address(), query, time.Since(start), "-", err)
+	return rows, redactError(err)
+}
+
+// Show returns the output of SHOW <objectType> IN <in>, e.g. SHOW TABLES IN
+// SCHEMA A.B, from the show cache.
+func (s *session) Show(objectType string, in string) (*showResult, error) {
+	statement := fmt.Sprintf("SHOW %s IN %s", strings.ToUpper(objectType), in)
+	return s.meta.showCache.load(s.role+"\n"+statement, func() (*showResult, error) {
+		return s.QueryAll(statement)
+	})
+}
+
+// QueryAll runs a query and reads all of its rows into memory.
+func (s *session) QueryAll(query string, args ...interface{}) (*showResult, error) {
+	rows, err := s.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	r := &showResult{}
+	if r.columns, err = rows.Columns(); err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		values, err := scanValues(rows, len(r.columns))
+		if err != nil {
+			return nil, err
+		}
+		r.rows = append(r.rows, values)
+	}
+	return r, rows.Err()
+}
+
+// address identifies the resource the session runs statements for.
+func (s *session) address() string {
+	if s.d.Id() == "" {
+		return fmt.Sprintf("%s (%s)", s.meta.resourceType, s.meta.operation)
+	}
+	return fmt.Sprintf("%s.%s (%s)", s.meta.resourceType, s.d.Id(), s.meta.operation)
+}
+
+// Close returns the connection to the pool.
+func (s *session) Close() error {
+	return s.conn.Close()
+}
+
+// executionRoleUpdate is the Update of resources whose only in-place change is
+// execution_role, which does not affect the object itself.
+func executionRoleUpdate(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
@@ -0,0 +1,442 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/fakesnowflake"
+)
+
+/*
+recordingDriver is a database/sql connector for unit tests of sessions, which
+check the statements sent to Snowflake and the connections they are sent on.
+Statements are run by a fakesnowflake driver of its own, so every test starts
+out with an empty account, and recorded as "<connection> <exec|query>
+<statement>", connections being numbered in the order they are opened.
+*/
+type recordingDriver struct {
+	driver *fakesnowflake.Driver
+
+	mu sync.Mutex
+	// calls are the statements run, in order.
+	calls []string
+	// conns counts the connections opened.
+	conns int
+	// execErr, if set, fails executing the statements it returns an error for.
+	execErr func(statement string) error
+}
+
+var _ driver.Connector = (*recordingDriver)(nil)
+
+func newRecordingDriver() *recordingDriver {
+	return &recordingDriver{driver: fakesnowflake.NewDriver()}
+}
+
+func (r *recordingDriver) Connect(ctx context.Context) (driver.Conn, error) {
+	c, err := r.driver.Open(testAccFakeDSN)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.conns++
+	return &recordingConn{Conn: c, r: r, n: r.conns}, nil
+}
+
+func (r *recordingDriver) Driver() driver.Driver {
+	return r.driver
+}
+
+func (r *recordingDriver) record(n int, method string, statement string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, fmt.Sprintf("%d %s %s", n, method, statement))
+}
+
+// takeCalls returns the statements run since it was last called.
+func (r *recordingDriver) takeCalls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := r.calls
+	r.calls = nil
+	return calls
+}
+
+// recordingConn is connection n of a recordingDriver.
+type recordingConn struct {
+	driver.Conn
+	r *recordingDriver
+	n int
+}
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.r.record(c.n, "exec", query)
+	if c.r.execErr != nil {
+		if err := c.r.execErr(query); err != nil {
+			return nil, err
+		}
+	}
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.r.record(c.n, "query", query)
+	return c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+}
+
+// testSessionMeta returns the provider meta of a create of a database whose
+// sessions connect through r.
+func testSessionMeta(r *recordingDriver) *providerMeta {
+	return &providerMeta{
+		db:           sql.OpenDB(r),
+		showCache:    newShowCache(),
+		resourceType: "snowflake_database",
+		operation:    "create",
+	}
+}
+
+// testCheckCalls checks the statements run since the last check.
+func testCheckCalls(t *testing.T, r *recordingDriver, want ...string) {
+	t.Helper()
+	if calls := r.takeCalls(); !reflect.DeepEqual(calls, want) {
+		t.Fatalf("statements run:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snowflake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dry_run.sql")
+
+	r := newRecordingDriver()
+	m := testSessionMeta(r)
+	m.role = "SYSADMIN"
+	m.dryRun = true
+	if m.dryRunScript, err = openSQLLog(path); err != nil {
+		t.Fatal(err)
+	}
+	s, err := newSession(m, resourceSnowflakeDatabase().TestResourceData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// Statements go to the script, reads and setting up the session still
+	// reach Snowflake.
+	if err := s.ExecAll([]string{"CREATE DATABASE D", "ALTER DATABASE D SET COMMENT = 'it''s'"}); err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := s.ObjectExists("databases", "D", inAccount); err != nil || exists {
+		t.Fatalf("ObjectExists = %t, %v after a dry run", exists, err)
+	}
+	if _, err := s.Exec("ALTER USER U SET PASSWORD = ?", "hunter2"); err == nil {
+		t.Fatal("a statement with arguments was rendered")
+	}
+	testCheckCalls(t, r,
+		"1 exec USE ROLE SYSADMIN",
+		"1 query SHOW DATABASES IN ACCOUNT",
+	)
+
+	script, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "-- snowflake_database (create)\nCREATE DATABASE D;\n" +
+		"-- snowflake_database (create)\nALTER DATABASE D SET COMMENT = 'it''s';\n"
+	if string(script) != want {
+		t.Fatalf("dry-run script:\n%s\nwant:\n%s", script, want)
+	}
+}
+
+// TestReadOnlySession checks that sessions are set up in read-only mode, USE
+// and ALTER SESSION only change the session.
+func TestReadOnlySession(t *testing.T) {
+	r := newRecordingDriver()
+	m := testSessionMeta(r)
+	m.role = "SYSADMIN"
+	m.tagQueries = true
+	m.readOnly = true
+	m.operation = "read"
+	s, err := newSession(m, resourceSnowflakeDatabase().TestResourceData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if _, err := s.Exec("CREATE DATABASE D"); err == nil {
+		t.Fatal("a statement was executed in read-only mode")
+	}
+	calls := r.takeCalls()
+	if len(calls) != 2 || calls[0] != "1 exec USE ROLE SYSADMIN" || !strings.HasPrefix(calls[1], "1 exec ALTER SESSION SET QUERY_TAG = ") {
+		t.Fatalf("statements run:\n%s", strings.Join(calls, "\n"))
+	}
+}
+
+// TestDryRunProvider checks that a provider in dry-run mode writes the
+// statements of an apply to dry_run_script_path and leaves Snowflake alone.
+func TestDryRunProvider(t *testing.T) {
+	if testAccUseAccount() {
+		t.Skip("the provider is only configured for the stand-in")
+	}
+	dir, err := ioutil.TempDir("", "snowflake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dry_run.sql")
+
+	p := Provider().(*schema.Provider)
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"dsn":                 testAccFakeDSN,
+		"dry_run":             true,
+		"dry_run_script_path": path,
+		"tag_queries":         false,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Configure(terraform.NewResourceConfig(raw)); err != nil {
+		t.Fatal(err)
+	}
+	name := testAccName()
+	state, err := testFakeApply(t, p.ResourcesMap["snowflake_role"], p.Meta(), nil, map[string]interface{}{"name": name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.ID != name {
+		t.Fatalf("ID = %q, want %q", state.ID, name)
+	}
+
+	script, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("-- snowflake_role (create)\nCREATE ROLE %s;\n", name)
+	if string(script) != want {
+		t.Fatalf("dry-run script:\n%s\nwant:\n%s", script, want)
+	}
+	c, err := newSession(p.Meta().(*providerMeta), resourceSnowflakeRole().TestResourceData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if exists, err := c.ObjectExists("roles", name, inAccount); err != nil || exists {
+		t.Fatalf("ObjectExists = %t, %v after a dry run", exists, err)
+	}
+}
+
+// TestSessionExecutionRole checks that the statements of a resource run as its
+// execution_role, falling back to the provider role.
+func TestSessionExecutionRole(t *testing.T) {
+	r := newRecordingDriver()
+	m := testSessionMeta(r)
+	exec := func(d *schema.ResourceData, statement string) {
+		t.Helper()
+		s, err := newSession(m, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		if _, err := s.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d := resourceSnowflakeDatabase().TestResourceData()
+
+	// Without a role, the session keeps the role of the login.
+	exec(d, "CREATE WAREHOUSE W")
+	testCheckCalls(t, r, "1 exec CREATE WAREHOUSE W")
+
+	m.role = "SYSADMIN"
+	m.warehouse = "W"
+	exec(d, "CREATE DATABASE D")
+	testCheckCalls(t, r,
+		"1 exec USE ROLE SYSADMIN",
+		"1 exec USE WAREHOUSE W",
+		"1 exec CREATE DATABASE D",
+	)
+
+	d.Set("execution_role", "accountadmin")
+	exec(d, "CREATE DATABASE D2")
+	testCheckCalls(t, r,
+		"1 exec USE ROLE ACCOUNTADMIN",
+		"1 exec USE WAREHOUSE W",
+		"1 exec CREATE DATABASE D2",
+	)
+}
+
+// TestExecutionRoleUpdate checks that changing only the execution_role of a
+// resource executes nothing and leaves no diff.
+func TestExecutionRoleUpdate(t *testing.T) {
+	c := newFakeClient()
+	meta := testFakeMeta(c)
+	r := resourceSnowflakeView()
+	raw := map[string]interface{}{
+		"database":        "D",
+		"schema":          "S",
+		"name":            "V",
+		"view_definition": "SELECT 1",
+		"execution_role":  "SYSADMIN",
+	}
+	state := testFakeState(t, r, "D.S.V", raw)
+
+	raw["execution_role"] = "ACCOUNTADMIN"
+	state, err := testFakeApply(t, r, meta, state, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckStatements(t, c)
+	testCheckAttributes(t, state, map[string]string{"id": "D.S.V", "execution_role": "ACCOUNTADMIN"})
+
+	cfg, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfig(cfg), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() {
+		t.Fatalf("diff after changing execution_role: %v", diff)
+	}
+}
+
+// TestSessionConnection checks that all statements of an operation go through
+// the connection its session pins, and that the connection is released also
+// when setting up the session fails.
+func TestSessionConnection(t *testing.T) {
+	r := newRecordingDriver()
+	m := testSessionMeta(r)
+	m.role = "SYSADMIN"
+	m.db.SetMaxIdleConns(2)
+	first, err := newSession(m, resourceSnowflakeDatabase().TestResourceData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := newSession(m, resourceSnowflakeDatabase().TestResourceData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.Exec("CREATE DATABASE D"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.QueryAll("SHOW DATABASES"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.QueryAll("SHOW SCHEMAS IN DATABASE D"); err != nil {
+		t.Fatal(err)
+	}
+	if n := m.db.Stats().InUse; n != 2 {
+		t.Fatalf("%d connections in use, want 2", n)
+	}
+	first.Close()
+	second.Close()
+	if n := m.db.Stats().InUse; n != 0 {
+		t.Fatalf("%d connections in use after closing the sessions", n)
+	}
+	testCheckCalls(t, r,
+		"1 exec USE ROLE SYSADMIN",
+		"2 exec USE ROLE SYSADMIN",
+		"1 exec CREATE DATABASE D",
+		"2 query SHOW DATABASES",
+		"1 query SHOW SCHEMAS IN DATABASE D",
+	)
+
+	m.warehouse = "W"
+	if _, err := newSession(m, resourceSnowflakeDatabase().TestResourceData()); err == nil {
+		t.Fatal("using a warehouse that does not exist succeeded")
+	}
+	if n := m.db.Stats().InUse; n != 0 {
+		t.Fatalf("%d connections in use after failing to set up a session", n)
+	}
+
+	// A failing operation releases its session too.
+	m.warehouse = ""
+	r.execErr = func(statement string) error {
+		if strings.HasPrefix(statement, "CREATE") {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	}
+	if _, err := testFakeApply(t, resourceSnowflakeRole(), m, nil, map[string]interface{}{"name": "R"}); err == nil {
+		t.Fatal("creating a role succeeded")
+	}
+	if n := m.db.Stats().InUse; n != 0 {
+		t.Fatalf("%d connections in use after a failed create", n)
+	}
+}
+
+// TestProviderConnectionPool checks that the provider limits the connections
+// it opens and keeps to max_open_connections and max_idle_connections.
+func TestProviderConnectionPool(t *testing.T) {
+	if testAccUseAccount() {
+		t.Skip("the provider is only configured for the stand-in")
+	}
+	p := Provider().(*schema.Provider)
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"dsn":                  testAccFakeDSN,
+		"max_open_connections": 2,
+		"max_idle_connections": 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Configure(terraform.NewResourceConfig(raw)); err != nil {
+		t.Fatal(err)
+	}
+	m := p.Meta().(*providerMeta)
+	if n := m.db.Stats().MaxOpenConnections; n != 2 {
+		t.Fatalf("MaxOpenConnections = %d, want 2", n)
+	}
+
+	d := resourceSnowflakeRole().TestResourceData()
+	var sessions []*session
+	for i := 0; i < 2; i++ {
+		s, err := newSession(m, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessions = append(sessions, s)
+	}
+	// A third session waits for one of the others to be closed.
+	opened := make(chan *session)
+	go func() {
+		s, err := newSession(m, d)
+		if err != nil {
+			t.Error(err)
+		}
+		opened <- s
+	}()
+	select {
+	case <-opened:
+		t.Fatal("a third connection was opened")
+	case <-time.After(100 * time.Millisecond):
+	}
+	sessions[0].Close()
+	sessions[0] = <-opened
+	if n := m.db.Stats().OpenConnections; n != 2 {
+		t.Fatalf("%d connections open, want 2", n)
+	}
+
+	for _, s := range sessions {
+		s.Close()
+	}
+	if stats := m.db.Stats(); stats.Idle != 1 || stats.OpenConnections != 1 {
+		t.Fatalf("%d connections open and %d idle, want 1 idle", stats.OpenConnections, stats.Idle)
+	}
+}
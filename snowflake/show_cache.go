@@ -0,0 +1,81 @@
+package snowflake
+
+import (
+	"sync"
+)
+
+/*
+showCache holds the output of the SHOW commands listing all objects of a type
+in a container, e.g. SHOW TABLES IN SCHEMA ANALYTICS.PUBLIC, for the duration
+of a Terraform run. Every Read looks up the SHOW row of its object, so without
+the cache refreshing 500 tables in a schema runs 500 nearly identical SHOW
+commands. With it, a single SHOW serves all of them, concurrent Reads waiting
+for the one in flight rather than issuing their own.
+
+Entries are keyed by the role the SHOW ran as, as the objects a role can see
+depend on its privileges. Every statement a session executes drops all
+entries: a refresh executes none, so that is where the cache pays off, and
+during an apply working out which containers a statement touched is not worth
+the risk of serving stale rows. A SHOW that was running while a statement was
+executed is not stored.
+
+A nil *showCache caches nothing.
+*/
+type showCache struct {
+	mu      sync.Mutex
+	entries map[string]*showEntry
+}
+
+// showResult is the output of a SHOW command.
+type showResult struct {
+	columns []string
+	rows    [][]interface{}
+}
+
+type showEntry struct {
+	done   chan struct{}
+	result *showResult
+	err    error
+}
+
+func newShowCache() *showCache {
+	return &showCache{entries: map[string]*showEntry{}}
+}
+
+// load returns the cached result for key, calling fetch to get it if there is
+// none. Errors are not cached.
+func (c *showCache) load(key string, fetch func() (*showResult, error)) (*showResult, error) {
+	if c == nil {
+		return fetch()
+	}
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		<-e.done
+		return e.result, e.err
+	}
+	e := &showEntry{done: make(chan struct{})}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	e.result, e.err = fetch()
+	close(e.done)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// The entry is gone already if the cache was invalidated meanwhile.
+	if e.err != nil && c.entries[key] == e {
+		delete(c.entries, key)
+	}
+	return e.result, e.err
+}
+
+// invalidate drops all entries, including those still being fetched.
+func (c *showCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]*showEntry{}
+}
@@ -0,0 +1,202 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// testShowFetch returns a fetch func for showCache.load counting its calls
+// in n.
+func testShowFetch(n *int32, err error) func() (*showResult, error) {
+	return func() (*showResult, error) {
+		atomic.AddInt32(n, 1)
+		if err != nil {
+			return nil, err
+		}
+		return &showResult{columns: []string{"name"}}, nil
+	}
+}
+
+func TestShowCache(t *testing.T) {
+	c := newShowCache()
+	var n int32
+	first, err := c.load("SYSADMIN\nSHOW TABLES IN SCHEMA D.S", testShowFetch(&n, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.load("SYSADMIN\nSHOW TABLES IN SCHEMA D.S", testShowFetch(&n, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || first != second {
+		t.Fatalf("a cached SHOW was fetched %d times", n)
+	}
+	if _, err := c.load("SYSADMIN\nSHOW VIEWS IN SCHEMA D.S", testShowFetch(&n, nil)); err != nil || n != 2 {
+		t.Fatalf("another SHOW was fetched %d times: %v", n-1, err)
+	}
+
+	c.invalidate()
+	if _, err := c.load("SYSADMIN\nSHOW TABLES IN SCHEMA D.S", testShowFetch(&n, nil)); err != nil || n != 3 {
+		t.Fatal("a SHOW was not fetched again after invalidating the cache")
+	}
+
+	// Errors are not cached.
+	failed := errors.New("failed")
+	if _, err := c.load("SYSADMIN\nSHOW PIPES IN SCHEMA D.S", testShowFetch(&n, failed)); err != failed {
+		t.Fatalf("load = %v, want %v", err, failed)
+	}
+	if _, err := c.load("SYSADMIN\nSHOW PIPES IN SCHEMA D.S", testShowFetch(&n, nil)); err != nil || n != 5 {
+		t.Fatal("a failed SHOW was cached")
+	}
+
+	// A nil cache fetches every time.
+	var nilCache *showCache
+	n = 0
+	for i := 0; i < 2; i++ {
+		if _, err := nilCache.load("SYSADMIN\nSHOW TABLES IN SCHEMA D.S", testShowFetch(&n, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	nilCache.invalidate()
+	if n != 2 {
+		t.Fatalf("a nil cache fetched %d times, want 2", n)
+	}
+}
+
+// TestShowCacheInvalidatedInFlight checks that a SHOW running while the cache
+// is invalidated is returned to its caller, but not stored.
+func TestShowCacheInvalidatedInFlight(t *testing.T) {
+	c := newShowCache()
+	key := "SYSADMIN\nSHOW TABLES IN SCHEMA D.S"
+	var n int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	stale := &showResult{columns: []string{"name"}}
+	result := make(chan *showResult)
+	go func() {
+		r, _ := c.load(key, func() (*showResult, error) {
+			atomic.AddInt32(&n, 1)
+			close(started)
+			<-release
+			return stale, nil
+		})
+		result <- r
+	}()
+	<-started
+	c.invalidate()
+	close(release)
+	if r := <-result; r != stale {
+		t.Fatalf("load = %v, want the result of the SHOW in flight", r)
+	}
+
+	r, err := c.load(key, testShowFetch(&n, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r == stale || n != 2 {
+		t.Fatal("a SHOW running while the cache was invalidated was stored")
+	}
+}
+
+// TestShowCacheConcurrent loads and invalidates from many goroutines, which
+// is mostly of use with -race.
+func TestShowCacheConcurrent(t *testing.T) {
+	c := newShowCache()
+	var n int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("SYSADMIN\nSHOW TABLES IN SCHEMA D.S%d", i%5)
+			r, err := c.load(key, testShowFetch(&n, nil))
+			if err != nil || len(r.columns) != 1 {
+				t.Errorf("load = %v, %v", r, err)
+			}
+			if i%10 == 0 {
+				c.invalidate()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Concurrent loads of the same SHOW fetch it once.
+	n = 0
+	c.invalidate()
+	results := make([]*showResult, 50)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = c.load("SYSADMIN\nSHOW TABLES IN SCHEMA D.S", testShowFetch(&n, nil))
+		}(i)
+	}
+	wg.Wait()
+	if n != 1 {
+		t.Fatalf("concurrent loads fetched %d times", n)
+	}
+	for _, r := range results {
+		if r != results[0] {
+			t.Fatal("concurrent loads returned different results")
+		}
+	}
+}
+
+// TestSessionShow checks that sessions share SHOW output by role and that
+// executing a statement drops it.
+func TestSessionShow(t *testing.T) {
+	r := newRecordingDriver()
+	m := testSessionMeta(r)
+	m.role = "SYSADMIN"
+	m.operation = "read"
+	d := resourceSnowflakeDatabase().TestResourceData()
+	admin := resourceSnowflakeDatabase().TestResourceData()
+	admin.Set("execution_role", "ACCOUNTADMIN")
+
+	show := func(d *schema.ResourceData) {
+		t.Helper()
+		s, err := newSession(m, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		if _, err := s.Show("databases", inAccount); err != nil {
+			t.Fatal(err)
+		}
+	}
+	show(d)
+	show(d)
+	show(admin)
+	show(admin)
+	testCheckCalls(t, r,
+		"1 exec USE ROLE SYSADMIN",
+		"1 query SHOW DATABASES IN ACCOUNT",
+		"1 exec USE ROLE SYSADMIN",
+		"1 exec USE ROLE ACCOUNTADMIN",
+		"1 query SHOW DATABASES IN ACCOUNT",
+		"1 exec USE ROLE ACCOUNTADMIN",
+	)
+
+	s, err := newSession(m, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Exec("CREATE DATABASE D"); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	r.takeCalls()
+	show(d)
+	show(admin)
+	testCheckCalls(t, r,
+		"1 exec USE ROLE SYSADMIN",
+		"1 query SHOW DATABASES IN ACCOUNT",
+		"1 exec USE ROLE ACCOUNTADMIN",
+		"1 query SHOW DATABASES IN ACCOUNT",
+	)
+}
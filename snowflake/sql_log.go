@@ -0,0 +1,60 @@
+package snowflake
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+sqlLog appends the statements executed by the provider to a file, each
+preceded by a comment with the time, resource address, duration and rows
+affected:
+
+-- 2019-05-01T10:00:00Z snowflake_role.ANALYST (delete) duration=52ms rows=0
+DROP ROLE ANALYST;
+
+The same format without the timings makes up the dry-run script. Secrets are
+redacted before anything is written. A nil *sqlLog discards everything.
+*/
+type sqlLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func openSQLLog(path string) (*sqlLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Could not open SQL log: %v", err)
+	}
+	return &sqlLog{file: f}, nil
+}
+
+func (l *sqlLog) record(address string, statement string, duration time.Duration, rows string, err error) {
+	if l == nil {
+		return
+	}
+	entry := fmt.Sprintf("-- %s %s duration=%s rows=%s\n", time.Now().UTC().Format(time.RFC3339), address, duration.Round(time.Millisecond), rows)
+	if err != nil {
+		entry += fmt.Sprintf("-- error: %s\n", strings.Replace(redactSQL(err.Error()), "\n", " ", -1))
+	}
+	entry += redactSQL(strings.TrimSpace(statement)) + ";\n"
+	l.write(entry)
+}
+
+// recordStatement appends a statement annotated with the resource address
+// only.
+func (l *sqlLog) recordStatement(address string, statement string) {
+	if l == nil {
+		return
+	}
+	l.write(fmt.Sprintf("-- %s\n%s;\n", address, redactSQL(strings.TrimSpace(statement))))
+}
+
+func (l *sqlLog) write(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.file.WriteString(entry)
+}
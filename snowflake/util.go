@@ -1,16 +1,168 @@
 package snowflake
 
 import (
-	"database/sql"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/preston4tw/terraform-provider-snowflake/snowflake/internal/snowsql"
 )
 
 /*
-showStatementExists is a helper function to check whether a Snowflake object
-exists or not. In the context of the Snowflake terraform provider, before
-modifying any object we want to know that one and only one object exists. While
-by default identifiers are case insensitive, (see
+parseIdentifier parses a name from the configuration or state. Names are case
+insensitive unless they are enclosed in double quotes, exactly as in a
+statement, so `foo` and `FOO` name the same object while `"foo"` names a
+different one. This lets the provider manage objects created with quoted
+mixed case names, e.g. by dbt.
+*/
+func parseIdentifier(name string) snowsql.Identifier {
+	id, err := snowsql.ParseIdentifier(name)
+	if err != nil {
+		// validateIdentifier rejects such names in the configuration.
+		return snowsql.NewIdentifier(name)
+	}
+	return id
+}
+
+// identifier renders a name from the configuration or state for use in a
+// statement.
+func identifier(name string) string {
+	return parseIdentifier(name).String()
+}
+
+// qualifiedName renders the dot separated name of an object, e.g.
+// database.schema.table.
+func qualifiedName(names ...string) string {
+	ids := make([]snowsql.Identifier, len(names))
+	for i, name := range names {
+		ids[i] = parseIdentifier(name)
+	}
+	return snowsql.QualifiedName(ids...)
+}
+
+// resolvedName returns name the way Snowflake stores it, which is what SHOW
+// commands and information_schema return.
+func resolvedName(name string) string {
+	return parseIdentifier(name).Name()
+}
+
+/*
+canonicalIdentifier returns the form names are kept in state and IDs: bare and
+upper case for case insensitive names, in double quotes for everything else.
+Both `foo` and `"FOO"` become `FOO`, `"foo"` stays `"foo"`.
+*/
+func canonicalIdentifier(name string) string {
+	if name == "" {
+		return ""
+	}
+	return identifier(name)
+}
+
+// identifierStateFunc is the StateFunc of attributes naming an object.
+func identifierStateFunc(v interface{}) string {
+	return canonicalIdentifier(v.(string))
+}
+
+// storedIdentifier returns the canonical form of a name as returned by SHOW
+// commands and information_schema.
+func storedIdentifier(name string) string {
+	return snowsql.NewQuotedIdentifier(name).String()
+}
+
+// validateIdentifier is the ValidateFunc of attributes naming an object.
+func validateIdentifier(v interface{}, k string) (ws []string, errors []error) {
+	id, err := snowsql.ParseIdentifier(v.(string))
+	if err == nil {
+		err = id.Validate()
+	}
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%s: %v", k, err))
+	}
+	return
+}
+
+// validatePrivilege is the ValidateFunc of privileges of grant resources.
+func validatePrivilege(v interface{}, k string) (ws []string, errors []error) {
+	if err := snowsql.ValidatePrivilege(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%s: %v", k, err))
+	}
+	return
+}
+
+// The containers SHOW commands can be limited to.
+const inAccount = "ACCOUNT"
+
+func inDatabase(database string) string {
+	return "DATABASE " + identifier(database)
+}
+
+func inSchema(database string, schema string) string {
+	return "SCHEMA " + qualifiedName(database, schema)
+}
+
+// expandPrivileges returns the privileges attribute of a grant resource.
+func expandPrivileges(d *schema.ResourceData) []string {
+	return toPrivileges(d.Get("privileges"))
+}
+
+// grantedPrivileges returns the privileges of a grant resource in the state.
+// These are the ones to revoke, when the grant is replaced because the
+// privileges changed d holds the new ones.
+func grantedPrivileges(d *schema.ResourceData) []string {
+	old, _ := d.GetChange("privileges")
+	return toPrivileges(old)
+}
+
+/*
+managedPrivileges returns the privileges of granted that grant resource d
+manages, in the order of the state. A grant resource only manages the
+privileges it lists, so that several resources can grant different privileges
+on an object to the same grantee. An imported grant lists none yet and takes
+all of them.
+*/
+func managedPrivileges(d *schema.ResourceData, granted []string) []string {
+	managed := expandPrivileges(d)
+	if len(managed) == 0 {
+		sorted := append([]string(nil), granted...)
+		sort.Strings(sorted)
+		return sorted
+	}
+	isGranted := map[string]bool{}
+	for _, p := range granted {
+		isGranted[strings.ToUpper(p)] = true
+	}
+	var privileges []string
+	for _, p := range managed {
+		if isGranted[strings.ToUpper(p)] {
+			privileges = append(privileges, p)
+		}
+	}
+	return privileges
+}
+
+func toPrivileges(v interface{}) []string {
+	var privileges []string
+	for _, p := range v.([]interface{}) {
+		privileges = append(privileges, p.(string))
+	}
+	return privileges
+}
+
+// showRowLimit is the maximum number of rows a SHOW command returns.
+const showRowLimit = 10000
+
+/*
+showObject looks up the object of objectType named name in the container in,
+see inAccount, inDatabase and inSchema, and scans its SHOW row into dest unless
+dest is nil. It reports whether the object exists, and fails unless one and
+only one object has that name.
+
+The rows come from SHOW <objectType> IN <in>, which is cached for all objects
+of the container, see showCache. Names are compared exactly, while by default
+identifiers are case insensitive, (see
 https://docs.snowflake.net/manuals/sql-reference/identifiers-syntax.html)
 identifiers can be made case sensitive by wrapping them in double quotes. For
 instance, it's possible to issue these queries:
@@ -21,314 +173,164 @@ create database "FOO";
 And get two results for the following
 show databases like 'foo';
 */
-func showStatementExists(db *sql.DB, statement string) (bool, error) {
-	rows, err := db.Query(statement)
+func showObject(db *session, objectType string, name string, in string, dest interface{}) (bool, error) {
+	r, err := db.Show(objectType, in)
 	if err != nil {
-		return true, err
+		return false, err
 	}
-	defer rows.Close()
-	index := 0
-	for rows.Next() {
-		index++
+	if len(r.rows) >= showRowLimit {
+		// The output is truncated, only ask for objects named alike.
+		statement := fmt.Sprintf("SHOW %s LIKE %s IN %s", objectType, snowsql.LikePattern(resolvedName(name)), in)
+		if r, err = db.QueryAll(statement); err != nil {
+			return false, err
+		}
+	}
+	index := -1
+	for i, column := range r.columns {
+		if strings.ToLower(column) == "name" {
+			index = i
+		}
 	}
-	if index == 0 {
+	if index == -1 {
+		return false, fmt.Errorf("SHOW %s returned no name column", objectType)
+	}
+	var match []interface{}
+	for _, row := range r.rows {
+		if n, _ := row[index].(string); n != resolvedName(name) {
+			continue
+		}
+		if match != nil {
+			return true, fmt.Errorf("More than 1 of %s named %s in %s", objectType, identifier(name), in)
+		}
+		match = row
+	}
+	if match == nil {
 		return false, nil
 	}
-	if index == 1 {
+	if dest == nil {
 		return true, nil
 	}
-	if index > 1 {
-		return true, fmt.Errorf("More than 1 row returned for \"%s\"", statement)
-	}
-	return true, fmt.Errorf("this should never happen")
+	return true, assignRow(r.columns, match, dest)
 }
 
-func sqlObjExists(db *sql.DB, objectType string, name string, inClause string) (bool, error) {
-	statement := fmt.Sprintf("SHOW %s LIKE '%s' in %s", objectType, name, inClause)
-	return showStatementExists(db, statement)
+// sqlObjExists checks that one and only one object of objectType named name
+// exists in the container in, see showObject.
+func sqlObjExists(db *session, objectType string, name string, in string) (bool, error) {
+	return showObject(db, objectType, name, in, nil)
 }
 
-func showDatabase(db *sql.DB, name string) (showDatabaseRow, error) {
+func showDatabase(db *session, name string) (showDatabaseRow, error) {
 	var r showDatabaseRow
-	// This verifies that one and only one database exists
-	exists, err := sqlObjExists(db, "databases", name, "account")
+	exists, err := showObject(db, "databases", name, inAccount, &r)
 	if err != nil {
 		return r, err
 	}
 	if exists == false {
-		return r, fmt.Errorf("Database %s does not exist", name)
-	}
-	statement := fmt.Sprintf("SHOW DATABASES LIKE '%s'", name)
-	rows, err := db.Query(statement)
-	if err != nil {
-		return r, err
-	}
-	defer rows.Close()
-	for rows.Next() {
-		if err := rows.Scan(
-			&r.createdOn,
-			&r.name,
-			&r.isDefault,
-			&r.isCurrent,
-			&r.origin,
-			&r.owner,
-			&r.comment,
-			&r.options,
-			&r.retentionTime,
-		); err != nil {
-			return r, err
-		}
+		return r, newNotFoundError("Database", name)
 	}
 	return r, nil
 }
 
-func showSchema(db *sql.DB, databaseName string, name string) (showSchemaRow, error) {
+func showSchema(db *session, databaseName string, name string) (showSchemaRow, error) {
 	var r showSchemaRow
-	// This verifies that one and only one database exists
-	exists, err := sqlObjExists(db, "schemas", name, databaseName)
-	if err != nil {
-		return r, err
-	}
-	if exists == false {
-		return r, fmt.Errorf("Schema %s.%s does not exist", databaseName, name)
-	}
-	statement := fmt.Sprintf("SHOW SCHEMAS LIKE '%s' in %s", name, databaseName)
-	rows, err := db.Query(statement)
-	if err != nil {
-		return r, err
-	}
-	defer rows.Close()
-	for rows.Next() {
-		if err := rows.Scan(
-			&r.createdOn,
-			&r.name,
-			&r.isDefault,
-			&r.isCurrent,
-			&r.databaseName,
-			&r.owner,
-			&r.comment,
-			&r.options,
-			&r.retentionTime,
-		); err != nil {
-			return r, err
-		}
-	}
-	return r, nil
-}
-
-func readTable(db *sql.DB, database string, schema string, name string) (infoSchemaTable, error) {
-	var r infoSchemaTable
-	exists, err := sqlObjExists(db, "tables", name, fmt.Sprintf("%s.%s", database, schema))
+	exists, err := showObject(db, "schemas", name, inDatabase(databaseName), &r)
 	if err != nil {
 		return r, err
 	}
 	if exists == false {
-		return r, fmt.Errorf("Table %s.%s.%s does not exist", database, schema, name)
-	}
-	statement := fmt.Sprintf("SELECT * from %s.information_schema.tables where table_name = '%s' and table_schema = '%s'", database, name, schema)
-	rows, err := db.Query(statement)
-	if err != nil {
-		return r, err
-	}
-	defer rows.Close()
-	for rows.Next() {
-		if err := rows.Scan(
-			&r.tableCatalog,
-			&r.tableSchema,
-			&r.tableName,
-			&r.tableOwner,
-			&r.tableType,
-			&r.isTransient,
-			&r.clusteringKey,
-			&r.rowCount,
-			&r.bytes,
-			&r.retentionTime,
-			&r.selfReferencingColumnName,
-			&r.referenceGeneration,
-			&r.userDefinedTypeColumn,
-			&r.userDefinedTypeSchema,
-			&r.userDefinedTypeName,
-			&r.isInsertableInto,
-			&r.isTyped,
-			&r.commitAction,
-			&r.created,
-			&r.lastAltered,
-			&r.autoClusteringOn,
-			&r.comment,
-		); err != nil {
-			return r, err
-		}
+		return r, newNotFoundError("Schema", databaseName, name)
 	}
 	return r, nil
 }
 
-func readView(db *sql.DB, database string, schema string, name string) (infoSchemaView, error) {
+func readView(db *session, database string, schema string, name string) (infoSchemaView, error) {
 	var r infoSchemaView
-	exists, err := sqlObjExists(db, "views", name, fmt.Sprintf("%s.%s", database, schema))
+	exists, err := sqlObjExists(db, "views", name, inSchema(database, schema))
 	if err != nil {
 		return r, err
 	}
 	if exists == false {
-		return r, fmt.Errorf("View %s.%s.%s does not exist", database, schema, name)
+		return r, newNotFoundError("View", database, schema, name)
 	}
-	statement := fmt.Sprintf("SELECT * from %s.information_schema.views where table_name = '%s' and table_schema = '%s'", database, name, schema)
+	statement := fmt.Sprintf("SELECT * from %s.information_schema.views where table_name = %s and table_schema = %s", identifier(database), snowsql.Literal(resolvedName(name)), snowsql.Literal(resolvedName(schema)))
 	rows, err := db.Query(statement)
 	if err != nil {
 		return r, err
 	}
 	defer rows.Close()
 	for rows.Next() {
-		if err := rows.Scan(
-			&r.tableCatalog,
-			&r.tableSchema,
-			&r.tableName,
-			&r.tableOwner,
-			&r.viewDefinition,
-			&r.checkOption,
-			&r.isUpdatable,
-			&r.insertableInto,
-			&r.isSecure,
-			&r.created,
-			&r.lastAltered,
-			&r.comment,
-		); err != nil {
+		if err := scanRow(rows, &r); err != nil {
 			return r, err
 		}
 	}
 	return r, nil
 }
 
-func showTable(db *sql.DB, databaseName string, schemaName string, name string) (showTableRow, error) {
+func showTable(db *session, databaseName string, schemaName string, name string) (showTableRow, error) {
 	var r showTableRow
-	// This verifies that one and only one database exists
-	exists, err := sqlObjExists(db, "tables", name, fmt.Sprintf("%s.%s", databaseName, schemaName))
+	exists, err := showObject(db, "tables", name, inSchema(databaseName, schemaName), &r)
 	if err != nil {
 		return r, err
 	}
 	if exists == false {
-		return r, fmt.Errorf("Table %s.%s.%s does not exist", databaseName, schemaName, name)
-	}
-	statement := fmt.Sprintf("SHOW TABLES LIKE '%s' in %s.%s", name, databaseName, schemaName)
-	rows, err := db.Query(statement)
-	if err != nil {
-		return r, err
-	}
-	defer rows.Close()
-	for rows.Next() {
-		if err := rows.Scan(
-			&r.createdOn,
-			&r.name,
-			&r.databaseName,
-			&r.schemaName,
-			&r.kind,
-			&r.comment,
-			&r.clusterBy,
-			&r.rows,
-			&r.bytes,
-			&r.owner,
-			&r.retentionTime,
-		); err != nil {
-			return r, err
-		}
+		return r, newNotFoundError("Table", databaseName, schemaName, name)
 	}
 	return r, nil
 }
 
-func descTable(db *sql.DB, databaseName string, schemaName string, name string) ([]descTableRow, error) {
+func descTable(db *session, databaseName string, schemaName string, name string) ([]descTableRow, error) {
 	var columnInfo []descTableRow
-	// This verifies that one and only one database exists
-	exists, err := sqlObjExists(db, "tables", name, fmt.Sprintf("%s.%s", databaseName, schemaName))
+	exists, err := sqlObjExists(db, "tables", name, inSchema(databaseName, schemaName))
 	if err != nil {
 		return columnInfo, err
 	}
 	if exists == false {
-		return columnInfo, fmt.Errorf("Table %s.%s.%s does not exist", databaseName, schemaName, name)
+		return columnInfo, newNotFoundError("Table", databaseName, schemaName, name)
 	}
-	statement := fmt.Sprintf("DESC TABLE %s.%s.%s", databaseName, schemaName, name)
+	statement := fmt.Sprintf("DESC TABLE %s", qualifiedName(databaseName, schemaName, name))
 	rows, err := db.Query(statement)
 	if err != nil {
 		return columnInfo, err
 	}
 	defer rows.Close()
-	for rows.Next() {
-		var r descTableRow
-		if err := rows.Scan(
-			&r.colName,
-			&r.colType,
-			&r.kind,
-			&r.isNullable,
-			&r.defaultValue,
-			&r.isPrimaryKey,
-			&r.isUniqueKey,
-			&r.check,
-			&r.expression,
-			&r.comment,
-		); err != nil {
-			return columnInfo, err
-		}
-		columnInfo = append(columnInfo, r)
-	}
-	return columnInfo, nil
+	err = scanRows(rows, &columnInfo)
+	return columnInfo, err
 }
 
-func showPipe(db *sql.DB, database string, schema string, name string) (showPipeRow, error) {
+func showPipe(db *session, database string, schema string, name string) (showPipeRow, error) {
 	var r showPipeRow
-	// This verifies that one and only one database exists
-	exists, err := sqlObjExists(db, "pipes", name, fmt.Sprintf("%s.%s", database, schema))
+	exists, err := showObject(db, "pipes", name, inSchema(database, schema), &r)
 	if err != nil {
 		return r, err
 	}
 	if exists == false {
-		return r, fmt.Errorf("Pipe %s.%s.%s does not exist", database, schema, name)
-	}
-	statement := fmt.Sprintf("SHOW PIPES LIKE '%s' in %s.%s", name, database, schema)
-	rows, err := db.Query(statement)
-	if err != nil {
-		return r, err
-	}
-	defer rows.Close()
-	for rows.Next() {
-		if err := rows.Scan(
-			&r.createdOn,
-			&r.name,
-			&r.databaseName,
-			&r.schemaName,
-			&r.definition,
-			&r.owner,
-			&r.notificationChannel,
-			&r.comment,
-		); err != nil {
-			return r, err
-		}
+		return r, newNotFoundError("Pipe", database, schema, name)
 	}
 	return r, nil
 }
 
-func descUser(db *sql.DB, name string) (descUserResult, error) {
+func descUser(db *session, name string) (descUserResult, error) {
 	var r descUserResult
-	// This verifies that one and only one user exists
-	exists, err := sqlObjExists(db, "users", name, "account")
+	exists, err := sqlObjExists(db, "users", name, inAccount)
 	if err != nil {
 		return r, err
 	}
 	if exists == false {
-		return r, fmt.Errorf("User %s does not exist", name)
+		return r, newNotFoundError("User", name)
 	}
-	statement := fmt.Sprintf("DESC USER %s", name)
+	statement := fmt.Sprintf("DESC USER %s", identifier(name))
 	rows, err := db.Query(statement)
 	if err != nil {
 		return r, err
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var property string
-		var value string
-		var sfc_default string
-		var description string
-		if err := rows.Scan(&property, &value, &sfc_default, &description); err != nil {
+		var row descUserRow
+		if err := scanRow(rows, &row); err != nil {
 			return r, err
 		}
-		switch property {
+		value := row.Value
+		switch row.Property {
 		case "NAME":
 			r.name = value
 		case "COMMENT":
@@ -382,32 +384,29 @@ func descUser(db *sql.DB, name string) (descUserResult, error) {
 	return r, nil
 }
 
-func descStage(db *sql.DB, database string, schema string, name string) (descStageResult, error) {
+func descStage(db *session, database string, schema string, name string) (descStageResult, error) {
 	var r descStageResult
-	exists, err := sqlObjExists(db, "stages", name, fmt.Sprintf("%s.%s", database, schema))
+	exists, err := sqlObjExists(db, "stages", name, inSchema(database, schema))
 	if err != nil {
 		return r, err
 	}
 	if exists == false {
-		return r, fmt.Errorf("Stage %s does not exist", name)
+		return r, newNotFoundError("Stage", database, schema, name)
 	}
-	statement := fmt.Sprintf("DESC STAGE %s.%s.%s", database, schema, name)
+	statement := fmt.Sprintf("DESC STAGE %s", qualifiedName(database, schema, name))
 	rows, err := db.Query(statement)
 	if err != nil {
 		return r, err
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var parent_property string
-		var property string
-		var property_type string
-		var property_value string
-		var property_default string
-		if err := rows.Scan(&parent_property, &property, &property_type, &property_value, &property_default); err != nil {
+		var row descStageRow
+		if err := scanRow(rows, &row); err != nil {
 			return r, err
 		}
+		property_value := row.PropertyValue
 
-		switch property {
+		switch row.Property {
 		case "URL":
 			//when you DESC STAGE, the url is inside brackets and quotated. At least it's not in the middle of the other side, in parentheses and capital letters.
 			r.url = strings.Trim(property_value, "[\"]")
@@ -422,110 +421,349 @@ func descStage(db *sql.DB, database string, schema string, name string) (descSta
 	return r, nil
 }
 
-func showTableGrant(db *sql.DB, grantee string, database string, schema string, table string) (showTableGrantResult, error) {
-	var r showTableGrantResult
-	statement := fmt.Sprintf("select grantee, privilege_type, is_grantable from %v.information_schema.object_privileges where grantee = '%v' and object_type = 'TABLE' and object_name = '%v' and object_catalog = '%v' and object_schema = '%v'", database, grantee, table, database, schema)
-	statement = strings.ToUpper(statement)
-	rows, err := db.Query(statement)
+func showFileFormat(db *session, database string, schema string, name string) (showFileFormatRow, error) {
+	var r showFileFormatRow
+	exists, err := showObject(db, "file formats", name, inSchema(database, schema), &r)
+	if err != nil {
+		return r, err
+	}
+	if exists == false {
+		return r, newNotFoundError("File format", database, schema, name)
+	}
+	return r, nil
+}
+
+func showSequence(db *session, database string, schema string, name string) (showSequenceRow, error) {
+	var r showSequenceRow
+	exists, err := showObject(db, "sequences", name, inSchema(database, schema), &r)
 	if err != nil {
 		return r, err
 	}
-	var uGrantee = strings.ToUpper(grantee)
+	if exists == false {
+		return r, newNotFoundError("Sequence", database, schema, name)
+	}
+	return r, nil
+}
 
+// descUnescaper reverts the backslash escapes DESC FILE FORMAT shows string
+// options with, e.g. a RECORD_DELIMITER of \n is a newline.
+var descUnescaper = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\t`, "\t", `\r`, "\r", `\'`, `'`, `\"`, `"`)
+
+func descFileFormat(db *session, database string, schema string, name string) (descFileFormatResult, error) {
+	var r descFileFormatResult
+	statement := fmt.Sprintf("DESC FILE FORMAT %s", qualifiedName(database, schema, name))
+	rows, err := db.Query(statement)
+	if err != nil {
+		return r, err
+	}
 	defer rows.Close()
 	for rows.Next() {
-		var qGrantee string
-		var privilegeType string
-		var isGrantable string
-
-		if err := rows.Scan(&qGrantee, &privilegeType, &isGrantable); err != nil {
+		var row descFileFormatRow
+		if err := scanRow(rows, &row); err != nil {
 			return r, err
 		}
+		value := row.PropertyValue
 
-		if qGrantee == uGrantee {
-			r.privileges = append(r.privileges, privilegeType)
+		switch row.Property {
+		case "TYPE":
+			r.formatType = value
+		case "RECORD_DELIMITER":
+			r.recordDelimiter = descUnescaper.Replace(value)
+		case "FIELD_DELIMITER":
+			r.fieldDelimiter = descUnescaper.Replace(value)
+		case "SKIP_HEADER":
+			if r.skipHeader, err = strconv.Atoi(value); err != nil {
+				return r, err
+			}
+		case "FIELD_OPTIONALLY_ENCLOSED_BY":
+			// NONE is how DESC shows that fields are not enclosed.
+			if value != "NONE" {
+				r.fieldOptionallyEnclosedBy = descUnescaper.Replace(value)
+			}
+		case "STRIP_OUTER_ARRAY":
+			r.stripOuterArray = value == "true"
+		case "COMPRESSION":
+			r.compression = value
+		case "TRIM_SPACE":
+			r.trimSpace = value == "true"
 		}
 	}
+	return r, rows.Err()
+}
+
+// formatOptionsNullIf returns the NULL_IF of a file format from the JSON
+// object in the format_options column of SHOW FILE FORMATS. DESC FILE FORMAT
+// shows the list as [a, b], which cannot be split when a value contains a
+// comma.
+func formatOptionsNullIf(formatOptions string) ([]string, error) {
+	if formatOptions == "" {
+		return nil, nil
+	}
+	var options struct {
+		NullIf []string `json:"NULL_IF"`
+	}
+	if err := json.Unmarshal([]byte(formatOptions), &options); err != nil {
+		return nil, fmt.Errorf("Could not parse file format options %q: %v", formatOptions, err)
+	}
+	return options.NullIf, nil
+}
 
-	r.grantee = grantee
-	r.database = database
-	r.schema = schema
-	r.table = table
+// showTableGrant returns the privileges grantee has on a table, or on every
+// table of the schema if table is ALL, see showAllGrant. It returns a
+// notFoundError if grantee has none.
+func showTableGrant(db *session, grantee string, database string, schema string, table string) (showTableGrantResult, error) {
+	r := showTableGrantResult{grantee: grantee, database: database, schema: schema, table: table}
+	var err error
+	if resolvedName(table) == "ALL" {
+		r.privileges, err = showAllGrant(db, grantee, database, schema, "tables")
+		return r, err
+	}
+	statement := fmt.Sprintf(
+		"SELECT grantee, privilege_type, is_grantable FROM %s.information_schema.object_privileges WHERE grantee = %s AND object_type = 'TABLE' AND object_name = %s AND object_catalog = %s AND object_schema = %s",
+		identifier(database),
+		snowsql.Literal(resolvedName(grantee)),
+		snowsql.Literal(resolvedName(table)),
+		snowsql.Literal(resolvedName(database)),
+		snowsql.Literal(resolvedName(schema)),
+	)
+	rows, err := db.Query(statement)
+	if err != nil {
+		return r, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row objectPrivilegeRow
+		if err := scanRow(rows, &row); err != nil {
+			return r, err
+		}
+		if row.Grantee == resolvedName(grantee) {
+			r.privileges = append(r.privileges, row.PrivilegeType)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return r, err
+	}
+	if len(r.privileges) == 0 {
+		return r, newNotFoundError("Grant", grantee, database, schema, table)
+	}
+	return r, nil
+}
 
+// showViewGrant returns the privileges granteeRole has on a view, or on every
+// view of the schema if view is ALL, see showAllGrant. It returns a
+// notFoundError if granteeRole has none.
+func showViewGrant(db *session, granteeRole string, database string, schema string, view string) (showViewGrantResult, error) {
+	r := showViewGrantResult{granteeRole: granteeRole, database: database, schema: schema, view: view}
+	var err error
+	if resolvedName(view) == "ALL" {
+		r.privileges, err = showAllGrant(db, granteeRole, database, schema, "views")
+		return r, err
+	}
+	statement := fmt.Sprintf("SHOW GRANTS ON VIEW %s", qualifiedName(database, schema, view))
+	rows, err := db.Query(statement)
+	if err != nil {
+		return r, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row showGrantRow
+		if err := scanRow(rows, &row); err != nil {
+			return r, err
+		}
+		if row.GranteeName == resolvedName(granteeRole) {
+			r.privileges = append(r.privileges, row.Privilege)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return r, err
+	}
+	if len(r.privileges) == 0 {
+		return r, newNotFoundError("Grant", granteeRole, database, schema, view)
+	}
 	return r, nil
+}
+
+/*
+showAllGrant returns the privileges grantee has on every object of
+objectType, tables or views, in a schema. This is what a grant ON ALL TABLES
+or ON ALL VIEWS IN SCHEMA reads back as: Snowflake keeps no record of the grant
+itself, it grants on each object that exists at the time.
+
+A privilege missing on any object counts as revoked, a notFoundError is
+returned if no privilege is left. A schema without such objects has every
+privilege granted on all of them, that is none is missing, and privileges is
+nil without an error.
+*/
+func showAllGrant(db *session, grantee string, database string, schema string, objectType string) ([]string, error) {
+	objects, err := db.Show(objectType, inSchema(database, schema))
+	if err != nil {
+		return nil, err
+	}
+	if len(objects.rows) == 0 {
+		return nil, nil
+	}
+	statement := fmt.Sprintf(
+		"SELECT privilege_type, object_name FROM %s.information_schema.object_privileges WHERE grantee = %s AND object_type = %s AND object_catalog = %s AND object_schema = %s",
+		identifier(database),
+		snowsql.Literal(resolvedName(grantee)),
+		snowsql.Literal(strings.ToUpper(strings.TrimSuffix(objectType, "s"))),
+		snowsql.Literal(resolvedName(database)),
+		snowsql.Literal(resolvedName(schema)),
+	)
+	rows, err := db.Query(statement)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	granted := map[string]map[string]bool{}
+	var privileges []string
+	for rows.Next() {
+		var row objectPrivilegeRow
+		if err := scanRow(rows, &row); err != nil {
+			return nil, err
+		}
+		if granted[row.PrivilegeType] == nil {
+			granted[row.PrivilegeType] = map[string]bool{}
+			privileges = append(privileges, row.PrivilegeType)
+		}
+		granted[row.PrivilegeType][row.ObjectName] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
+	var names []string
+	for _, row := range objects.rows {
+		for i, column := range objects.columns {
+			if strings.ToLower(column) == "name" {
+				name, _ := row[i].(string)
+				names = append(names, name)
+			}
+		}
+	}
+	var onAll []string
+	for _, p := range privileges {
+		n := 0
+		for _, name := range names {
+			if granted[p][name] {
+				n++
+			}
+		}
+		if n == len(names) {
+			onAll = append(onAll, p)
+		}
+	}
+	if len(onAll) == 0 {
+		return nil, newNotFoundError("Grant", grantee, database, schema, "ALL")
+	}
+	return onAll, nil
 }
 
-func showViewGrant(db *sql.DB, granteeRole string, database string, schema string, view string) (showViewGrantResult, error) {
-	var r showViewGrantResult
-	statement := fmt.Sprintf("show grants on %v.%v.%v", database, schema, view)
-	statement = strings.ToUpper(statement)
+// showWarehouseGrant returns the roles privilege on warehouse is granted to.
+// The grant option counts as granted only if every role has it.
+func showWarehouseGrant(db *session, warehouse string, privilege string) (showWarehouseGrantResult, error) {
+	var r showWarehouseGrantResult
+	statement := fmt.Sprintf("SHOW GRANTS ON WAREHOUSE %s", identifier(warehouse))
 	rows, err := db.Query(statement)
 	if err != nil {
 		return r, err
 	}
 
 	defer rows.Close()
+	grantOption := true
 	for rows.Next() {
-		var createdOn string
-		var privilege string
-		var grantedOn string
-		var name string
-		var grantedTo string
-		var granteeName string
-		var grantOption string
-		var grantedBy string
-
-		if err := rows.Scan(&createdOn, &privilege, &grantedOn, &name, &grantedTo, &granteeName, &grantOption, &grantedBy); err != nil {
+		var row showGrantRow
+		if err := scanRow(rows, &row); err != nil {
 			return r, err
 		}
 
-		if granteeRole == granteeName {
-			r.privileges = append(r.privileges, privilege)
+		if row.Privilege == privilege && row.GrantedTo == "ROLE" {
+			r.roles = append(r.roles, storedIdentifier(row.GranteeName))
+			grantOption = grantOption && row.GrantOption == "true"
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return r, err
+	}
 
-	r.granteeRole = granteeRole
-	r.database = database
-	r.schema = schema
-	r.view = view
+	r.warehouse = warehouse
+	r.privilege = privilege
+	r.grantOption = grantOption && len(r.roles) > 0
 
 	return r, nil
+}
 
+func showWarehouse(db *session, name string) (showWarehouseRow, error) {
+	var r showWarehouseRow
+	exists, err := showObject(db, "warehouses", name, inAccount, &r)
+	if err != nil {
+		return r, err
+	}
+	if exists == false {
+		return r, newNotFoundError("Warehouse", name)
+	}
+	return r, nil
 }
 
-func showRole(db *sql.DB, role string) (showRoleRow, error) {
-	var r showRoleRow
-	exists, err := sqlObjExists(db, "roles", role, "account")
+// showWarehouses returns all warehouses of the account.
+func showWarehouses(db *session) ([]showWarehouseRow, error) {
+	r, err := db.Show("warehouses", inAccount)
+	if err != nil {
+		return nil, err
+	}
+	warehouses := make([]showWarehouseRow, len(r.rows))
+	for i, row := range r.rows {
+		if err := assignRow(r.columns, row, &warehouses[i]); err != nil {
+			return nil, err
+		}
+	}
+	return warehouses, nil
+}
+
+func showResourceMonitor(db *session, name string) (showResourceMonitorRow, error) {
+	var r showResourceMonitorRow
+	exists, err := showObject(db, "resource monitors", name, inAccount, &r)
 	if err != nil {
 		return r, err
 	}
 	if exists == false {
-		return r, fmt.Errorf("Role %s does not exist", role)
+		return r, newNotFoundError("Resource monitor", name)
 	}
+	return r, nil
+}
 
-	statement := fmt.Sprintf("show roles like '%v'", role)
-	statement = strings.ToUpper(statement)
+// showParameter looks up the parameter key of the object in, e.g. WAREHOUSE
+// W, which has the default value unless it was set on the object.
+func showParameter(db *session, key string, in string) (showParameterRow, error) {
+	var r showParameterRow
+	statement := fmt.Sprintf("SHOW PARAMETERS LIKE %s IN %s", snowsql.Literal(key), in)
 	rows, err := db.Query(statement)
 	if err != nil {
 		return r, err
 	}
 	defer rows.Close()
 	for rows.Next() {
-		if err := rows.Scan(
-			&r.createdOn,
-			&r.name,
-			&r.isDefault,
-			&r.isCurrent,
-			&r.isInherited,
-			&r.assignedToUsers,
-			&r.grantedToRoles,
-			&r.grantedRoles,
-			&r.owner,
-			&r.comment,
-		); err != nil {
+		if err := scanRow(rows, &r); err != nil {
 			return r, err
 		}
+		if r.Key == key {
+			return r, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return r, err
+	}
+	return r, fmt.Errorf("%s has no parameter %s", in, key)
+}
+
+func showRole(db *session, role string) (showRoleRow, error) {
+	var r showRoleRow
+	exists, err := showObject(db, "roles", role, inAccount, &r)
+	if err != nil {
+		return r, err
+	}
+	if exists == false {
+		return r, newNotFoundError("Role", role)
 	}
 	return r, nil
 }